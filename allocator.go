@@ -0,0 +1,306 @@
+package ldtkgo
+
+import "sync"
+
+// Allocator provides the memory that the Tiles, IntGrid Integers, and Entities of loaded Levels are stored in, for games that load and
+// unload Levels continuously on platforms with tight memory (i.e. consoles or WebAssembly), where reusing memory reduces fragmentation and
+// garbage collection. Each method returns a slice of the length given, and Free returns the slices given out for a Layer once the Layer
+// is released (see Project.Release and Level.Release). A Pool can be used as an Allocator, or a game can provide its own (i.e. backed by
+// an arena). An Allocator is only called from the goroutine loading or releasing a Project, but it may be shared between Projects loaded
+// on different goroutines.
+type Allocator interface {
+	Tiles(count int) []Tile
+	Integers(count int) []Integer
+	Entities(count int) []Entity
+	Ints(count int) []int // For the positions of Tiles, Integers, and Entities
+	Free(tiles []Tile, integers []Integer, entities []Entity, ints []int)
+}
+
+// UseAllocator returns a LoadOption that stores the Tiles, IntGrid Integers, and Entities of the Project's Levels (and their positions) in
+// memory from the Allocator given, so that it can be reused once the Levels are released using Project.Release or Level.Release. Note that
+// decoding the JSON still allocates temporary memory, which is left to the garbage collector, as do the slices of pointers in each Layer.
+func UseAllocator(allocator Allocator) LoadOption {
+	return func(config *loadConfig) {
+		config.allocator = allocator
+	}
+}
+
+// layerMemory is the memory a Layer's contents are stored in, if they came from an Allocator.
+type layerMemory struct {
+	tiles    []Tile
+	integers []Integer
+	entities []Entity
+	ints     []int
+}
+
+// allocateLevel moves the contents of the Level's Layers into memory from the Project's Allocator, if it has one. This has to be done
+// before references to the Level's Entities are resolved.
+func (project *Project) allocateLevel(level *Level) {
+
+	if project.allocator == nil {
+		return
+	}
+
+	for _, layer := range level.Layers {
+		if layer.memory == nil {
+			layer.allocate(project.allocator)
+		}
+	}
+
+}
+
+// allocate moves the Layer's Tiles, Integers, and Entities (and their positions) into memory from the Allocator given.
+func (layer *Layer) allocate(allocator Allocator) {
+
+	intCount := 0
+
+	for _, tiles := range [][]*Tile{layer.Tiles, layer.AutoTiles} {
+		for _, tile := range tiles {
+			intCount += len(tile.Position) + len(tile.Src)
+		}
+	}
+
+	for _, integer := range layer.IntGrid {
+		intCount += len(integer.Position)
+	}
+
+	for _, entity := range layer.Entities {
+		intCount += len(entity.Position) + len(entity.GridPosition)
+	}
+
+	memory := &layerMemory{
+		tiles:    allocator.Tiles(len(layer.Tiles) + len(layer.AutoTiles)),
+		integers: allocator.Integers(len(layer.IntGrid)),
+		entities: allocator.Entities(len(layer.Entities)),
+		ints:     allocator.Ints(intCount),
+	}
+
+	ints := memory.ints[:0]
+
+	// moveInts copies the values given into the Layer's memory, returning the copy.
+	moveInts := func(values []int) []int {
+		if values == nil {
+			return nil
+		}
+		start := len(ints)
+		ints = append(ints, values...)
+		return ints[start:len(ints):len(ints)]
+	}
+
+	tileIndex := 0
+
+	for _, tiles := range [][]*Tile{layer.Tiles, layer.AutoTiles} {
+		for i, tile := range tiles {
+			moved := &memory.tiles[tileIndex]
+			*moved = *tile
+			moved.Position = moveInts(tile.Position)
+			moved.Src = moveInts(tile.Src)
+			tiles[i] = moved
+			tileIndex++
+		}
+	}
+
+	for i, integer := range layer.IntGrid {
+		moved := &memory.integers[i]
+		*moved = *integer
+		moved.Position = moveInts(integer.Position)
+		layer.IntGrid[i] = moved
+	}
+
+	for i, entity := range layer.Entities {
+		moved := &memory.entities[i]
+		*moved = *entity
+		moved.Position = moveInts(entity.Position)
+		moved.GridPosition = moveInts(entity.GridPosition)
+		layer.Entities[i] = moved
+	}
+
+	layer.memory = memory
+
+}
+
+// Release removes the Project's Levels from it, returning the memory of their Tiles, Integers, and Entities to the Allocator they were
+// allocated from (see UseAllocator). Without an Allocator, the Levels are just left to the garbage collector. Nothing from the Levels may be
+// used afterwards.
+func (project *Project) Release() {
+
+	for _, level := range project.Levels {
+		level.free()
+		level.pending = true
+	}
+
+	project.Levels = nil
+
+	project.resolveReferences()
+
+}
+
+// Release removes the Layers from the Level, returning the memory of their Tiles, Integers, and Entities to the Allocator they were allocated
+// from (see UseAllocator), i.e. to unload a Level that isn't currently needed. The Level itself stays in its Project, but is no longer loaded
+// (see IsLoaded); entity references to its Entities are resolved again, so that they no longer point into the released memory. Levels loaded
+// lazily (see LazyLevels) can be loaded again using EnsureLoaded. Nothing from the Level's Layers may be used afterwards.
+func (level *Level) Release() {
+
+	level.free()
+
+	level.pending = true
+
+	if level.Project != nil {
+		level.Project.resolveReferences()
+	}
+
+}
+
+// free removes the Layers from the Level, returning their memory to the Project's Allocator, if it has one.
+func (level *Level) free() {
+
+	for _, layer := range level.Layers {
+
+		if layer.memory != nil && level.Project != nil && level.Project.allocator != nil {
+			memory := layer.memory
+			level.Project.allocator.Free(memory.tiles, memory.integers, memory.entities, memory.ints)
+		}
+
+		layer.memory = nil
+		layer.Tiles = nil
+		layer.AutoTiles = nil
+		layer.IntGrid = nil
+		layer.Entities = nil
+
+	}
+
+	level.Layers = nil
+
+}
+
+// Pool is an Allocator that keeps the memory freed to it to be reused by later allocations of a similar size, rather than leaving it to the
+// garbage collector. Sizes are rounded up to the next power of two, so Levels of similar sizes share memory. A Pool is safe for concurrent
+// use; its zero value is ready to use.
+type Pool struct {
+	mutex    sync.Mutex
+	tiles    map[int][]interface{}
+	integers map[int][]interface{}
+	entities map[int][]interface{}
+	ints     map[int][]interface{}
+}
+
+// NewPool creates a new, empty Pool.
+func NewPool() *Pool {
+	return &Pool{}
+}
+
+// Tiles returns a slice of the number of Tiles given, reusing freed memory if possible.
+func (pool *Pool) Tiles(count int) []Tile {
+	if reused, ok := pool.get(&pool.tiles, count).([]Tile); ok {
+		return reused[:count]
+	}
+	return make([]Tile, count, poolCapacity(count))
+}
+
+// Integers returns a slice of the number of Integers given, reusing freed memory if possible.
+func (pool *Pool) Integers(count int) []Integer {
+	if reused, ok := pool.get(&pool.integers, count).([]Integer); ok {
+		return reused[:count]
+	}
+	return make([]Integer, count, poolCapacity(count))
+}
+
+// Entities returns a slice of the number of Entities given, reusing freed memory if possible.
+func (pool *Pool) Entities(count int) []Entity {
+	if reused, ok := pool.get(&pool.entities, count).([]Entity); ok {
+		return reused[:count]
+	}
+	return make([]Entity, count, poolCapacity(count))
+}
+
+// Ints returns a slice of the number of ints given, reusing freed memory if possible.
+func (pool *Pool) Ints(count int) []int {
+	if reused, ok := pool.get(&pool.ints, count).([]int); ok {
+		return reused[:count]
+	}
+	return make([]int, count, poolCapacity(count))
+}
+
+// Free clears the slices given and keeps them to be reused. Slices that weren't allocated by a Pool are left to the garbage collector.
+func (pool *Pool) Free(tiles []Tile, integers []Integer, entities []Entity, ints []int) {
+
+	// The slices are cleared so that they don't keep anything they pointed to alive while they're in the Pool.
+	tiles = tiles[:cap(tiles)]
+	for i := range tiles {
+		tiles[i] = Tile{}
+	}
+	pool.put(&pool.tiles, cap(tiles), tiles)
+
+	integers = integers[:cap(integers)]
+	for i := range integers {
+		integers[i] = Integer{}
+	}
+	pool.put(&pool.integers, cap(integers), integers)
+
+	entities = entities[:cap(entities)]
+	for i := range entities {
+		entities[i] = Entity{}
+	}
+	pool.put(&pool.entities, cap(entities), entities)
+
+	ints = ints[:cap(ints)]
+	for i := range ints {
+		ints[i] = 0
+	}
+	pool.put(&pool.ints, cap(ints), ints)
+
+}
+
+// get removes and returns a freed slice with room for the number of elements given from the free lists given, or nil if there isn't one.
+func (pool *Pool) get(free *map[int][]interface{}, count int) interface{} {
+
+	if count == 0 {
+		return nil
+	}
+
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+
+	capacity := poolCapacity(count)
+	list := (*free)[capacity]
+
+	if len(list) == 0 {
+		return nil
+	}
+
+	reused := list[len(list)-1]
+	(*free)[capacity] = list[:len(list)-1]
+
+	return reused
+
+}
+
+// put adds a freed slice with the capacity given to the free lists given, if the Pool could have allocated it.
+func (pool *Pool) put(free *map[int][]interface{}, capacity int, slice interface{}) {
+
+	if capacity == 0 || poolCapacity(capacity) != capacity {
+		return
+	}
+
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+
+	if *free == nil {
+		*free = map[int][]interface{}{}
+	}
+
+	(*free)[capacity] = append((*free)[capacity], slice)
+
+}
+
+// poolCapacity returns the capacity of the slices a Pool allocates for the number of elements given: the next power of two.
+func poolCapacity(count int) int {
+	if count == 0 {
+		return 0
+	}
+	capacity := 1
+	for capacity < count {
+		capacity *= 2
+	}
+	return capacity
+}
@@ -0,0 +1,80 @@
+package ldtkgo
+
+import "testing"
+
+// refProject has a Switch Entity in Level A that references a Door Entity in Level B.
+const refProject = `{"jsonVersion":"1.5.3","defs":{"tilesets":[],"layers":[],"entities":[]},"levels":[
+{"identifier":"A","iid":"level-a","pxWid":16,"pxHei":16,"layerInstances":[
+	{"__identifier":"Entities","__type":"Entities","__gridSize":16,"__cWid":1,"__cHei":1,"iid":"layer-a","entityInstances":[
+		{"__identifier":"Switch","iid":"switch","px":[0,0],"width":16,"height":16,"fieldInstances":[
+			{"__identifier":"Target","__type":"EntityRef","__value":{"entityIid":"door","layerIid":"layer-b","levelIid":"level-b","worldIid":""}}
+		]}
+	]}
+]},
+{"identifier":"B","iid":"level-b","worldX":16,"pxWid":16,"pxHei":16,"layerInstances":[
+	{"__identifier":"Entities","__type":"Entities","__gridSize":16,"__cWid":1,"__cHei":1,"iid":"layer-b","entityInstances":[
+		{"__identifier":"Door","iid":"door","px":[0,0],"width":16,"height":16,"fieldInstances":[]}
+	]}
+]}
+]}`
+
+// TestReleaseEntityRefs checks that entity references don't point into the memory of released Levels, which a Pool reuses.
+func TestReleaseEntityRefs(t *testing.T) {
+
+	for _, lazy := range []bool{false, true} {
+
+		options := []LoadOption{UseAllocator(NewPool())}
+		if lazy {
+			options = append(options, LazyLevels())
+		}
+
+		project, err := Read([]byte(refProject), options...)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		a, b := project.LevelByIdentifier("A"), project.LevelByIdentifier("B")
+
+		for _, level := range []*Level{a, b} {
+			if err := level.EnsureLoaded(); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		target := a.Entities()[0].PropertyByIdentifier("Target")
+
+		if door := b.Entities()[0]; target.AsEntityRef() != door {
+			t.Fatalf("lazy %t: reference is %v, not the Door %p", lazy, target.AsEntityRef(), door)
+		}
+
+		b.Release()
+
+		if b.IsLoaded() {
+			t.Errorf("lazy %t: released Level is still loaded", lazy)
+		}
+
+		if ref := target.AsEntityRef(); ref != nil {
+			t.Fatalf("lazy %t: reference to a released Entity is %p, not nil", lazy, ref)
+		}
+
+		err = b.EnsureLoaded()
+
+		if !lazy {
+			if err == nil {
+				t.Errorf("released Level that wasn't loaded lazily was loaded again")
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if door := b.Entities()[0]; target.AsEntityRef() != door {
+			t.Fatalf("reference after reloading is %p, not the reloaded Door %p", target.AsEntityRef(), door)
+		}
+
+	}
+
+}
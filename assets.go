@@ -0,0 +1,80 @@
+package ldtkgo
+
+import (
+	"path"
+	"path/filepath"
+	"sort"
+)
+
+// AssetPaths returns the paths of every file the Project references: the images of its Tilesets and Levels' backgrounds, the files of Levels
+// saved separately, the files its external enums are imported from, and the values of FilePath Properties on the Project, its Levels, and
+// their Entities. This is useful for build pipelines to copy exactly the assets a game needs into a bundle (or to generate embed directives).
+// The paths are slash-separated and relative to the root of the file system the Project was loaded from (see ResolvePath), sorted, and free
+// of duplicates; the project file itself isn't included.
+func (project *Project) AssetPaths() []string {
+
+	found := map[string]bool{}
+
+	add := func(relPath string) {
+		if relPath == "" {
+			return
+		}
+		found[path.Clean(filepath.ToSlash(project.ResolvePath(relPath)))] = true
+	}
+
+	addFilePaths := func(properties []*Property) {
+		for _, prop := range properties {
+			if prop.LDtkType() != PropertyTypeFilePath {
+				continue
+			}
+			if array, ok := prop.Value.([]interface{}); ok {
+				for _, value := range array {
+					if filePath, ok := value.(string); ok {
+						add(filePath)
+					}
+				}
+			} else if filePath, ok := prop.Value.(string); ok {
+				add(filePath)
+			}
+		}
+	}
+
+	for _, tileset := range project.Tilesets {
+		add(tileset.Path)
+	}
+
+	for _, enumPath := range project.ExternalEnumPaths {
+		add(enumPath)
+	}
+
+	addFilePaths(project.Properties)
+
+	for _, level := range project.Levels {
+
+		if level.BGImage != nil {
+			add(level.BGImage.Path)
+		}
+
+		add(level.ExternalPath)
+
+		addFilePaths(level.Properties)
+
+		for _, layer := range level.Layers {
+			for _, entity := range layer.Entities {
+				addFilePaths(entity.Properties)
+			}
+		}
+
+	}
+
+	paths := make([]string, 0, len(found))
+
+	for assetPath := range found {
+		paths = append(paths, assetPath)
+	}
+
+	sort.Strings(paths)
+
+	return paths
+
+}
@@ -0,0 +1,135 @@
+package ldtkgo
+
+import (
+	"image"
+	"image/color"
+	"io/fs"
+
+	_ "image/png" // Importing for loading PNGs
+)
+
+// TilesetAtlas provides access to the individual tiles in a Tileset's image without depending on any particular game engine or rendering
+// framework. Renderers can wrap it to get at tile images, pixel rectangles, or texture (UV) coordinates.
+type TilesetAtlas struct {
+	Tileset *Tileset
+	Image   image.Image
+}
+
+// NewTilesetAtlas creates a new TilesetAtlas for the Tileset using the image given, which should be the image at the Tileset's Path.
+func NewTilesetAtlas(tileset *Tileset, img image.Image) *TilesetAtlas {
+	return &TilesetAtlas{
+		Tileset: tileset,
+		Image:   img,
+	}
+}
+
+// OpenTilesetAtlas creates a new TilesetAtlas for the Tileset by loading its image from the file system given. PNG images are supported
+// by default; to load other formats, import the matching image decoder package.
+func OpenTilesetAtlas(tileset *Tileset, fileSystem fs.FS) (*TilesetAtlas, error) {
+
+	file, err := fileSystem.Open(tileset.Path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	img, _, err := image.Decode(file)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return NewTilesetAtlas(tileset, img), nil
+
+}
+
+// OpenTilesetAtlases creates a TilesetAtlas for each of the Project's Tilesets that has an image, loading the images from the file system given.
+// Like Project.ResolvePath, image paths are resolved relative to the project file when the file system given is the one the Project was loaded
+// from. The atlases are returned in a map keyed by the Tilesets' IDs.
+func (project *Project) OpenTilesetAtlases(fileSystem fs.FS) (map[int]*TilesetAtlas, error) {
+
+	atlases := map[int]*TilesetAtlas{}
+
+	for _, tileset := range project.Tilesets {
+
+		if tileset.Path == "" {
+			continue
+		}
+
+		resolved := *tileset
+		resolved.Path = project.ResolvePath(tileset.Path)
+
+		atlas, err := OpenTilesetAtlas(&resolved, fileSystem)
+
+		if err != nil && resolved.Path != tileset.Path {
+			atlas, err = OpenTilesetAtlas(tileset, fileSystem)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		atlas.Tileset = tileset
+		atlases[tileset.ID] = atlas
+
+	}
+
+	return atlases, nil
+
+}
+
+// TileRect returns the rectangle of the tile of the ID given within the atlas's image, in pixels.
+func (atlas *TilesetAtlas) TileRect(tileID int) image.Rectangle {
+	return atlas.Tileset.TileRect(tileID).Add(atlas.Image.Bounds().Min)
+}
+
+// TileImage returns the tile of the ID given as an image.Image that shares its pixels with the atlas's image.
+func (atlas *TilesetAtlas) TileImage(tileID int) image.Image {
+
+	rect := atlas.TileRect(tileID)
+
+	if subImager, ok := atlas.Image.(interface {
+		SubImage(r image.Rectangle) image.Image
+	}); ok {
+		return subImager.SubImage(rect)
+	}
+
+	return &subImage{Image: atlas.Image, bounds: rect.Intersect(atlas.Image.Bounds())}
+
+}
+
+// TileUV returns the texture coordinates of the top-left (u0, v0) and bottom-right (u1, v1) corners of the tile of the ID given, ranging from
+// 0 to 1 across the atlas's image.
+func (atlas *TilesetAtlas) TileUV(tileID int) (u0, v0, u1, v1 float64) {
+
+	bounds := atlas.Image.Bounds()
+
+	if bounds.Dx() <= 0 || bounds.Dy() <= 0 {
+		return 0, 0, 0, 0
+	}
+
+	rect := atlas.TileRect(tileID).Sub(bounds.Min)
+	w, h := float64(bounds.Dx()), float64(bounds.Dy())
+
+	return float64(rect.Min.X) / w, float64(rect.Min.Y) / h, float64(rect.Max.X) / w, float64(rect.Max.Y) / h
+
+}
+
+// subImage restricts an image.Image to a portion of its bounds, for images that don't support SubImage themselves.
+type subImage struct {
+	image.Image
+	bounds image.Rectangle
+}
+
+func (img *subImage) Bounds() image.Rectangle {
+	return img.bounds
+}
+
+func (img *subImage) At(x, y int) color.Color {
+	if !(image.Point{x, y}.In(img.bounds)) {
+		return color.Transparent
+	}
+	return img.Image.At(x, y)
+}
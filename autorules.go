@@ -0,0 +1,511 @@
+package ldtkgo
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+// AutoRule checker constants indicating how an AutoRule's modulo is offset on alternating rows or columns.
+const (
+	AutoRuleCheckerNone       = "None"
+	AutoRuleCheckerHorizontal = "Horizontal"
+	AutoRuleCheckerVertical   = "Vertical"
+)
+
+// AutoRule tile mode constants indicating how an AutoRule places its tiles.
+const (
+	AutoRuleTileModeSingle = "Single" // One of the AutoRule's tile rectangles is chosen at random, and its tile placed in the cell
+	AutoRuleTileModeStamp  = "Stamp"  // All of the tiles of one of the AutoRule's tile rectangles are placed around the cell
+)
+
+// autoRuleAnything is the pattern value that matches any non-empty IntGrid cell (or, if negative, only empty cells).
+const autoRuleAnything = 1000001
+
+// AutoRuleGroup represents a group of auto-layer rules in a LayerDefinition.
+type AutoRuleGroup struct {
+	UID        int         `json:"uid"`        // UID of the group
+	Name       string      `json:"name"`       // Name of the group
+	Active     bool        `json:"active"`     // Whether the group is enabled
+	IsOptional bool        `json:"isOptional"` // Whether the group is only applied to Layers that enable it (see Layer.OptionalRules)
+	Rules      []*AutoRule `json:"rules"`      // The rules in the group, in order of priority
+}
+
+// AutoRule represents an auto-layer rule, which places tiles wherever its pattern matches the values of an IntGrid.
+type AutoRule struct {
+	UID              int     `json:"uid"`              // UID of the rule
+	Active           bool    `json:"active"`           // Whether the rule is enabled
+	Size             int     `json:"size"`             // Width and height of the rule's pattern in cells
+	Pattern          []int   `json:"pattern"`          // The IntGrid values the rule matches, row by row; 0 matches anything, a positive value requires that value, and a negative value forbids it
+	TileRectIDs      [][]int `json:"tileRectsIds"`     // The rectangles of tiles (as lists of tile IDs) that the rule can place
+	Chance           float64 `json:"chance"`           // The chance (from 0 to 1) that the rule is applied to a matching cell
+	BreakOnMatch     bool    `json:"breakOnMatch"`     // Whether lower-priority rules are skipped for cells this rule applies to
+	FlipX            bool    `json:"flipX"`            // Whether the rule also matches its pattern flipped horizontally (placing flipped tiles)
+	FlipY            bool    `json:"flipY"`            // Whether the rule also matches its pattern flipped vertically (placing flipped tiles)
+	XModulo          int     `json:"xModulo"`          // The rule is only applied to every XModulo-th column
+	YModulo          int     `json:"yModulo"`          // The rule is only applied to every YModulo-th row
+	XOffset          int     `json:"xOffset"`          // Offset of the columns selected by XModulo
+	YOffset          int     `json:"yOffset"`          // Offset of the rows selected by YModulo
+	Checker          string  `json:"checker"`          // How the modulo is offset on alternating rows or columns; can be compared using AutoRuleChecker constants
+	TileMode         string  `json:"tileMode"`         // How the rule places its tiles; can be compared using AutoRuleTileMode constants
+	PivotX           float64 `json:"pivotX"`           // The horizontal pivot of stamps placed by the rule (0 = left, 1 = right)
+	PivotY           float64 `json:"pivotY"`           // The vertical pivot of stamps placed by the rule (0 = top, 1 = bottom)
+	TileXOffset      int     `json:"tileXOffset"`      // Horizontal offset of the placed tiles in pixels
+	TileYOffset      int     `json:"tileYOffset"`      // Vertical offset of the placed tiles in pixels
+	TileRandomXMin   int     `json:"tileRandomXMin"`   // Minimum random horizontal offset of the placed tiles in pixels
+	TileRandomXMax   int     `json:"tileRandomXMax"`   // Maximum random horizontal offset of the placed tiles in pixels
+	TileRandomYMin   int     `json:"tileRandomYMin"`   // Minimum random vertical offset of the placed tiles in pixels
+	TileRandomYMax   int     `json:"tileRandomYMax"`   // Maximum random vertical offset of the placed tiles in pixels
+	OutOfBoundsValue *int    `json:"outOfBoundsValue"` // The value used for cells outside of the IntGrid; if nil, the pattern doesn't match near the edges wherever it checks those cells
+	PerlinActive     bool    `json:"perlinActive"`     // Whether the rule is filtered using Perlin noise in LDtk (which isn't supported by LDtk-Go; such rules are applied without the filter)
+}
+
+type autoRuleAlias AutoRule
+
+// autoRuleJSON is what an AutoRule is decoded into (see decode.go).
+type autoRuleJSON struct {
+	*autoRuleAlias
+	TileIDs []int `json:"tileIds"`
+}
+
+// UnmarshalJSON decodes an AutoRule from LDtk JSON. Projects saved with versions of LDtk before 1.5 list the rule's tiles as a single
+// list of tile IDs, so in that case, they're converted to tile rectangles.
+func (rule *AutoRule) UnmarshalJSON(data []byte) error {
+
+	aux := autoRuleJSON{autoRuleAlias: (*autoRuleAlias)(rule)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	if len(rule.TileRectIDs) == 0 && len(aux.TileIDs) > 0 {
+		if rule.TileMode == AutoRuleTileModeStamp {
+			rule.TileRectIDs = [][]int{aux.TileIDs}
+		} else {
+			for _, id := range aux.TileIDs {
+				rule.TileRectIDs = append(rule.TileRectIDs, []int{id})
+			}
+		}
+	}
+
+	return nil
+
+}
+
+// RunAutoRules applies the auto-layer rules of the Layer's definition to its source IntGrid (the Layer's own IntGrid for IntGrid layers, or
+// the IntGrid layer it's based on for AutoLayers), replacing the Layer's AutoTiles. This allows the tiles to be updated after the IntGrid
+// has been modified at runtime (i.e. for destructible or buildable terrain).
+//
+// The rules are applied the same way as LDtk applies them; however, the rules' random choices (chances, random tiles, and random offsets)
+// (other than rule chances) aren't guaranteed to match LDtk's exactly, and Perlin noise filters and biomes aren't supported.
+func (layer *Layer) RunAutoRules() error {
+
+	if layer.level == nil || layer.level.Project == nil {
+		return fmt.Errorf("layer %s isn't part of a Project", layer.Identifier)
+	}
+
+	project := layer.level.Project
+
+	def := project.LayerDefinitionByUID(layer.DefUID)
+
+	if def == nil {
+		return fmt.Errorf("%w: definition %d of layer %s", ErrLayerNotFound, layer.DefUID, layer.Identifier)
+	}
+
+	source := layer
+
+	if def.Type == LayerTypeAutoTile {
+		source = nil
+		for _, other := range layer.level.Layers {
+			if other.DefUID == def.AutoSourceLayerDefUID {
+				source = other
+				break
+			}
+		}
+		if source == nil {
+			return fmt.Errorf("%w: source IntGrid layer of layer %s", ErrLayerNotFound, layer.Identifier)
+		}
+	}
+
+	tileset := layer.Tileset
+
+	if tileset == nil {
+		return fmt.Errorf("layer %s has no tileset to place tiles from", layer.Identifier)
+	}
+
+	width, height := source.CellWidth, source.CellHeight
+
+	if width <= 0 || height <= 0 {
+		return fmt.Errorf("source IntGrid layer %s has an invalid size of %dx%d cells", source.Identifier, width, height)
+	}
+
+	cells := make([]int, width*height)
+	for _, integer := range source.IntGrid {
+		if integer.ID >= 0 && integer.ID < len(cells) {
+			cells[integer.ID] = integer.Value
+		}
+	}
+
+	groups := map[int]int{}
+	for _, intGridDef := range project.LayerDefinitions {
+		if intGridDef.UID == source.DefUID {
+			for _, value := range intGridDef.IntGridValues {
+				groups[value.Value] = value.GroupUID
+			}
+		}
+	}
+
+	optional := map[int]bool{}
+	for _, uid := range layer.OptionalRules {
+		optional[uid] = true
+	}
+
+	evaluator := &autoRuleEvaluator{
+		cells:  cells,
+		width:  width,
+		height: height,
+		groups: groups,
+	}
+
+	// Each rule's tiles are gathered separately, as tiles from higher-priority rules should be drawn over those from lower-priority ones.
+	ruleTiles := [][]tileData{}
+	done := make([]bool, width*height)
+
+	for _, group := range def.AutoRuleGroups {
+
+		if !group.Active || (group.IsOptional && !optional[group.UID]) {
+			continue
+		}
+
+		for _, rule := range group.Rules {
+
+			if !rule.Active || len(rule.TileRectIDs) == 0 {
+				continue
+			}
+
+			tiles := []tileData{}
+
+			for cy := 0; cy < height; cy++ {
+				for cx := 0; cx < width; cx++ {
+
+					if done[cy*width+cx] || !rule.appliesAt(cx, cy) {
+						continue
+					}
+
+					seed := layer.Seed + rule.UID
+
+					if rule.Chance <= 0 || (rule.Chance < 1 && autoRuleRandom(seed, cx, cy, 100) >= int(rule.Chance*100)) {
+						continue
+					}
+
+					flip, matched := evaluator.match(rule, cx, cy)
+
+					if !matched {
+						continue
+					}
+
+					tiles = append(tiles, rule.placeTiles(tileset, layer.GridSize, seed, cx, cy, flip)...)
+
+					if rule.BreakOnMatch {
+						done[cy*width+cx] = true
+					}
+
+				}
+			}
+
+			ruleTiles = append(ruleTiles, tiles)
+
+		}
+
+	}
+
+	all := []tileData{}
+
+	for i := len(ruleTiles) - 1; i >= 0; i-- {
+		all = append(all, ruleTiles[i]...)
+	}
+
+	layer.AutoTiles = newTiles(all, true)
+
+	for _, tile := range layer.AutoTiles {
+		tile.layer = layer
+	}
+
+	return nil
+
+}
+
+// RunAutoRules runs the auto-layer rules of each of the Level's Layers (see Layer.RunAutoRules), i.e. after modifying an IntGrid layer that
+// AutoLayers are based on.
+func (level *Level) RunAutoRules() error {
+
+	if level.Project == nil {
+		return fmt.Errorf("level %s isn't part of a Project", level.Identifier)
+	}
+
+	for _, layer := range level.Layers {
+
+		def := level.Project.LayerDefinitionByUID(layer.DefUID)
+
+		if def == nil || len(def.AutoRuleGroups) == 0 || layer.Tileset == nil {
+			continue
+		}
+
+		if err := layer.RunAutoRules(); err != nil {
+			return err
+		}
+
+	}
+
+	return nil
+
+}
+
+// appliesAt returns if the rule's modulo allows it to be applied to the cell given.
+func (rule *AutoRule) appliesAt(cx, cy int) bool {
+
+	xModulo, yModulo := rule.XModulo, rule.YModulo
+
+	if xModulo < 1 {
+		xModulo = 1
+	}
+
+	if yModulo < 1 {
+		yModulo = 1
+	}
+
+	switch rule.Checker {
+
+	case AutoRuleCheckerHorizontal:
+		row := positiveModulo(cy-rule.YOffset, yModulo*2) / yModulo
+		return positiveModulo(cy-rule.YOffset, yModulo) == 0 && positiveModulo(cx-rule.XOffset+row*(xModulo/2), xModulo) == 0
+
+	case AutoRuleCheckerVertical:
+		column := positiveModulo(cx-rule.XOffset, xModulo*2) / xModulo
+		return positiveModulo(cx-rule.XOffset, xModulo) == 0 && positiveModulo(cy-rule.YOffset+column*(yModulo/2), yModulo) == 0
+
+	}
+
+	return positiveModulo(cx-rule.XOffset, xModulo) == 0 && positiveModulo(cy-rule.YOffset, yModulo) == 0
+
+}
+
+// placeTiles returns the tiles the rule places in the cell given, flipped as indicated.
+func (rule *AutoRule) placeTiles(tileset *Tileset, gridSize, seed, cx, cy int, flip TileFlip) []tileData {
+
+	rect := rule.TileRectIDs[0]
+	if len(rule.TileRectIDs) > 1 {
+		rect = rule.TileRectIDs[autoRuleRandom(seed, cx, cy, len(rule.TileRectIDs))]
+	}
+
+	if len(rect) == 0 {
+		return nil
+	}
+
+	x := cx*gridSize + rule.TileXOffset
+	y := cy*gridSize + rule.TileYOffset
+
+	if rule.TileRandomXMax > rule.TileRandomXMin {
+		x += rule.TileRandomXMin + autoRuleRandom(seed+1, cx, cy, rule.TileRandomXMax-rule.TileRandomXMin+1)
+	} else {
+		x += rule.TileRandomXMin
+	}
+
+	if rule.TileRandomYMax > rule.TileRandomYMin {
+		y += rule.TileRandomYMin + autoRuleRandom(seed+2, cx, cy, rule.TileRandomYMax-rule.TileRandomYMin+1)
+	} else {
+		y += rule.TileRandomYMin
+	}
+
+	newTile := func(id, px, py int) tileData {
+		src := tileset.TileRect(id)
+		return tileData{Position: [2]int{px, py}, Src: [2]int{src.Min.X, src.Min.Y}, Flip: flip, ID: id, Data: [2]int{rule.UID}}
+	}
+
+	if rule.TileMode != AutoRuleTileModeStamp || len(rect) == 1 {
+		return []tileData{newTile(rect[0], x, y)}
+	}
+
+	// Stamps are placed relative to the cell according to the rule's pivot, mirrored if the pattern matched flipped.
+	cellsWide := tileset.CellsWide()
+
+	if cellsWide <= 0 {
+		return nil
+	}
+
+	left, top, right, bottom := math.MaxInt32, math.MaxInt32, math.MinInt32, math.MinInt32
+
+	for _, id := range rect {
+		tx, ty := id%cellsWide, id/cellsWide
+		if tx < left {
+			left = tx
+		}
+		if tx > right {
+			right = tx
+		}
+		if ty < top {
+			top = ty
+		}
+		if ty > bottom {
+			bottom = ty
+		}
+	}
+
+	pivotX := int(math.Round(rule.PivotX * float64((right-left)*gridSize)))
+	pivotY := int(math.Round(rule.PivotY * float64((bottom-top)*gridSize)))
+
+	tiles := make([]tileData, 0, len(rect))
+
+	for _, id := range rect {
+
+		tx, ty := id%cellsWide-left, id/cellsWide-top
+
+		if flip.FlipX() {
+			tx = right - left - tx
+		}
+
+		if flip.FlipY() {
+			ty = bottom - top - ty
+		}
+
+		tiles = append(tiles, newTile(id, x+tx*gridSize-pivotX, y+ty*gridSize-pivotY))
+
+	}
+
+	return tiles
+
+}
+
+type autoRuleEvaluator struct {
+	cells  []int
+	width  int
+	height int
+	groups map[int]int // IntGrid value to the UID of the group it belongs to
+}
+
+// match returns if the rule's pattern matches the IntGrid at the cell given, and how the tiles should be flipped if it does. The pattern is
+// checked as-is first, and then flipped if the rule allows it.
+func (evaluator *autoRuleEvaluator) match(rule *AutoRule, cx, cy int) (TileFlip, bool) {
+
+	directions := []TileFlip{0}
+
+	if rule.FlipX {
+		directions = append(directions, TileFlipX)
+	}
+
+	if rule.FlipY {
+		directions = append(directions, TileFlipY)
+	}
+
+	if rule.FlipX && rule.FlipY {
+		directions = append(directions, TileFlipX|TileFlipY)
+	}
+
+	for _, flip := range directions {
+		if evaluator.matchDirection(rule, cx, cy, flip) {
+			return flip, true
+		}
+	}
+
+	return 0, false
+
+}
+
+func (evaluator *autoRuleEvaluator) matchDirection(rule *AutoRule, cx, cy int, flip TileFlip) bool {
+
+	dirX, dirY := 1, 1
+
+	if flip.FlipX() {
+		dirX = -1
+	}
+
+	if flip.FlipY() {
+		dirY = -1
+	}
+
+	radius := rule.Size / 2
+
+	for py := 0; py < rule.Size; py++ {
+		for px := 0; px < rule.Size; px++ {
+
+			index := px + py*rule.Size
+
+			if index >= len(rule.Pattern) || rule.Pattern[index] == 0 {
+				continue
+			}
+
+			required := rule.Pattern[index]
+
+			x := cx + dirX*(px-radius)
+			y := cy + dirY*(py-radius)
+
+			value := 0
+
+			if x >= 0 && y >= 0 && x < evaluator.width && y < evaluator.height {
+				value = evaluator.cells[y*evaluator.width+x]
+			} else if rule.OutOfBoundsValue != nil {
+				value = *rule.OutOfBoundsValue
+			} else {
+				return false
+			}
+
+			absolute := required
+			if absolute < 0 {
+				absolute = -absolute
+			}
+
+			var matches bool
+
+			switch {
+			case absolute == autoRuleAnything:
+				matches = value != 0
+			case absolute >= 1000:
+				// Values above 1000 refer to groups of IntGrid values.
+				matches = value != 0 && evaluator.groups[value] == absolute/1000-1
+			default:
+				matches = value == absolute
+			}
+
+			if matches != (required > 0) {
+				return false
+			}
+
+		}
+	}
+
+	return true
+
+}
+
+// autoRuleRandom returns a pseudo-random number from 0 up to (but not including) max that's always the same for the seed and cell given.
+// LDtk runs on JavaScript, so its arithmetic is reproduced with floating-point numbers truncated to 32-bit integers after each step for
+// the results to match.
+func autoRuleRandom(seed, x, y, max int) int {
+
+	if max <= 0 {
+		return 0
+	}
+
+	h := toInt32(float64(seed) + float64(x)*374761393 + float64(y)*668265263)
+	h = toInt32(float64(h^(h>>13)) * 1274126177)
+
+	return int(math.Abs(math.Mod(float64(h^(h>>16)), float64(max))))
+
+}
+
+// toInt32 converts a number to a 32-bit integer the way JavaScript's bitwise operators do, wrapping values that are out of range.
+func toInt32(value float64) int32 {
+	value = math.Mod(math.Trunc(value), 1<<32)
+	if value < 0 {
+		value += 1 << 32
+	}
+	return int32(uint32(value))
+}
+
+func positiveModulo(value, modulo int) int {
+	result := value % modulo
+	if result < 0 {
+		result += modulo
+	}
+	return result
+}
@@ -0,0 +1,561 @@
+package ldtkgo
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/gob"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+)
+
+// cacheMagic identifies a binary cache file written by WriteCache; the trailing byte is the version of the cache format, which should be
+// incremented whenever the Project's structure changes so that old caches aren't misread.
+var cacheMagic = []byte("LDTKGOCACHE\x0f")
+
+// ErrInvalidCache is returned by ReadCache when the data given isn't a binary cache written by this version of LDtk-Go.
+var ErrInvalidCache = errors.New("invalid or outdated ldtkgo cache")
+
+// ErrLevelsNotLoaded is returned by Project.WriteCache when some of the Project's Levels haven't been loaded yet (see the LazyLevels option).
+var ErrLevelsNotLoaded = errors.New("levels haven't been loaded")
+
+func init() {
+	// Property values (and table of contents fields) can hold these types, and gob needs to know about them to encode them as interface values.
+	gob.Register(map[string]interface{}{})
+	gob.Register([]interface{}{})
+	gob.Register(cacheMap{})
+}
+
+// cacheData is the data encoded in a cache. As gob encodes maps in a random order, the Project's maps are left out of the Project and stored
+// separately as slices sorted by key (and maps within Property values are stored as cacheMaps), so that the same Project always gives a
+// byte-identical cache.
+type cacheData struct {
+	Project   *Project
+	Tilesets  []cacheTileset // The CustomData and Enums of the Project's Tilesets, in the same order as the Tilesets
+	TOCFields [][]cacheMap   // The Fields of the Project's table of contents instances, by entry and then by instance
+	Worlds    []int          // The index of the World each of the Project's Levels is in, or -1 for Levels that aren't in one, in the same order as the Levels
+}
+
+type cacheTileset struct {
+	CustomDataIDs []int
+	CustomData    []string
+	EnumIDs       []int
+	Enums         []EnumSet
+}
+
+// cacheMap stores a map from a Property value as a slice of entries sorted by key.
+type cacheMap []cacheMapEntry
+
+type cacheMapEntry struct {
+	Key   string
+	Value interface{}
+}
+
+// WriteCache writes the Project to the io.Writer given in a compact binary format that can be read back using ReadCache much more quickly than
+// the original LDtk JSON can be parsed. Note that any custom Data set on Entities isn't written to the cache. If the Project was loaded using
+// the LazyLevels option, all of its Levels have to be loaded (see Level.EnsureLoaded) before it can be cached.
+func (project *Project) WriteCache(writer io.Writer) error {
+
+	for _, level := range project.Levels {
+		if !level.IsLoaded() {
+			return ErrLevelsNotLoaded
+		}
+	}
+
+	buffered := bufio.NewWriter(writer)
+
+	if _, err := buffered.Write(cacheMagic); err != nil {
+		return err
+	}
+
+	if _, err := buffered.Write(project.sourceHash[:]); err != nil {
+		return err
+	}
+
+	if err := gob.NewEncoder(buffered).Encode(project.cacheData()); err != nil {
+		return err
+	}
+
+	return buffered.Flush()
+
+}
+
+// ReadCache reads a Project from a binary cache written by Project.WriteCache. Returns the Project and an error should there be an error in
+// the loading process (ErrInvalidCache if the data isn't a cache, or is a cache written by a different version of LDtk-Go).
+func ReadCache(reader io.Reader) (*Project, error) {
+
+	buffered := bufio.NewReader(reader)
+
+	header := make([]byte, len(cacheMagic)+sha256.Size)
+
+	if _, err := io.ReadFull(buffered, header); err != nil {
+		return nil, ErrInvalidCache
+	}
+
+	if !bytes.Equal(header[:len(cacheMagic)], cacheMagic) {
+		return nil, ErrInvalidCache
+	}
+
+	data := &cacheData{}
+
+	if err := gob.NewDecoder(buffered).Decode(data); err != nil {
+		return nil, err
+	}
+
+	if data.Project == nil {
+		return nil, ErrInvalidCache
+	}
+
+	project := data.Project
+
+	if !data.restore() {
+		return nil, ErrInvalidCache
+	}
+
+	copy(project.sourceHash[:], header[len(cacheMagic):])
+
+	// The cache only holds the data from the LDtk file, so everything needs to be linked back together as usual.
+	if project.IntGridNames == nil {
+		project.IntGridNames = []string{}
+	}
+
+	if project.EntityDefinitions == nil {
+		project.EntityDefinitions = []*EntityDefinition{}
+	}
+
+	if project.LayerDefinitions == nil {
+		project.LayerDefinitions = []*LayerDefinition{}
+	}
+
+	if project.LevelFieldDefinitions == nil {
+		project.LevelFieldDefinitions = []*FieldDefinition{}
+	}
+
+	project.setupBGColor()
+
+	project.setupDefinitions()
+
+	restoreProperties(project.Properties)
+
+	for _, level := range project.Levels {
+		project.setupLevel(level)
+		restoreProperties(level.Properties)
+		for _, layer := range level.Layers {
+			for _, entity := range layer.Entities {
+				restoreProperties(entity.Properties)
+			}
+		}
+	}
+
+	project.resolveReferences()
+
+	return project, nil
+
+}
+
+// restoreProperties restores the maps within the values of Properties read from a cache, along with the values of empty array Properties, as
+// gob decodes empty slices as nil.
+func restoreProperties(properties []*Property) {
+	for _, prop := range properties {
+		prop.Value = fromCacheValue(prop.Value)
+		if array, ok := prop.Value.([]interface{}); ok && array == nil {
+			prop.Value = []interface{}{}
+		}
+	}
+}
+
+// restore puts the maps stored separately in the cacheData back into its Project and links its Levels to their Worlds, returning false if the
+// stored data doesn't match the Project.
+func (data *cacheData) restore() bool {
+
+	if len(data.Tilesets) != len(data.Project.Tilesets) || len(data.TOCFields) != len(data.Project.TableOfContents) || len(data.Worlds) != len(data.Project.Levels) {
+		return false
+	}
+
+	project := data.Project
+
+	for i, level := range project.Levels {
+		if index := data.Worlds[i]; index >= len(project.Worlds) {
+			return false
+		} else if index >= 0 {
+			world := project.Worlds[index]
+			level.world = world
+			world.levels = append(world.levels, level)
+		}
+	}
+
+	for _, world := range project.Worlds {
+		world.UpdateBounds()
+	}
+
+	for i, tileset := range project.Tilesets {
+
+		cached := data.Tilesets[i]
+
+		if len(cached.CustomData) != len(cached.CustomDataIDs) || len(cached.Enums) != len(cached.EnumIDs) {
+			return false
+		}
+
+		// Like when loading from JSON, the maps are never nil.
+		tileset.CustomData = make(map[int]string, len(cached.CustomDataIDs))
+		for j, id := range cached.CustomDataIDs {
+			tileset.CustomData[id] = cached.CustomData[j]
+		}
+
+		tileset.Enums = make(map[int]EnumSet, len(cached.EnumIDs))
+		for j, id := range cached.EnumIDs {
+			tileset.Enums[id] = cached.Enums[j]
+		}
+
+	}
+
+	for i, entry := range project.TableOfContents {
+		if len(data.TOCFields[i]) != len(entry.Instances) {
+			return false
+		}
+		for j, instance := range entry.Instances {
+			if data.TOCFields[i][j] != nil {
+				instance.Fields, _ = fromCacheValue(data.TOCFields[i][j]).(map[string]interface{})
+			}
+		}
+	}
+
+	for _, def := range project.EntityDefinitions {
+		for _, fieldDef := range def.FieldDefinitions {
+			fieldDef.DefaultValue = fromCacheValue(fieldDef.DefaultValue)
+		}
+	}
+
+	for _, fieldDef := range project.LevelFieldDefinitions {
+		fieldDef.DefaultValue = fromCacheValue(fieldDef.DefaultValue)
+	}
+
+	return true
+
+}
+
+// OpenCached loads the LDtk project from the filepath specified using the file system provided, like Open. However, if the binary cache file
+// at cachePath (on the OS file system) was written from the same contents of the project file, the Project is read from the cache instead,
+// which is much faster. Otherwise, the project file (and any external level files) is parsed and the cache is (re)written. Writing the cache is done on a best-effort basis;
+// if the cache can't be written (i.e. on platforms without a writable file system), the Project is still returned. As reading the cache
+// decodes every Level, the cache isn't used if the LazyLevels option is given.
+func OpenCached(filepath string, fileSystem fs.FS, cachePath string, options ...LoadOption) (*Project, error) {
+
+	if newLoadConfig(options).lazyLevels {
+		return Open(filepath, fileSystem, options...)
+	}
+
+	data, err := fs.ReadFile(fileSystem, filepath)
+
+	if err != nil {
+		return nil, err
+	}
+
+	hash := sha256.Sum256(data)
+
+	if cacheFile, err := os.Open(cachePath); err == nil {
+
+		project, err := ReadCache(cacheFile)
+		cacheFile.Close()
+
+		if err == nil && project.sourceHash == hash {
+			config := newLoadConfig(options)
+			project.UsePropertyDefaults = config.usePropertyDefaults
+			if config.allocator != nil {
+				project.allocator = config.allocator
+				for _, level := range project.Levels {
+					project.allocateLevel(level)
+				}
+				project.resolveReferences()
+			}
+			project.fileSystem = fileSystem
+			project.dir = path.Dir(filepath)
+			return project, nil
+		}
+
+	}
+
+	project, err := Read(data, options...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	project.sourceHash = hash
+	project.Path = filepath
+	project.fileSystem = fileSystem
+	project.dir = path.Dir(filepath)
+
+	if err := project.loadExternalLevels(context.Background(), fileSystem); err != nil {
+		return nil, err
+	}
+
+	if cacheFile, err := os.Create(cachePath); err == nil {
+		if err := project.WriteCache(cacheFile); err != nil {
+			cacheFile.Close()
+			os.Remove(cachePath)
+		} else if err := cacheFile.Close(); err != nil {
+			os.Remove(cachePath)
+		}
+	}
+
+	return project, nil
+
+}
+
+// cacheData returns the data to encode in a cache for the Project.
+func (project *Project) cacheData() *cacheData {
+
+	data := &cacheData{
+		Project:   project.cacheCopy(),
+		Tilesets:  make([]cacheTileset, len(project.Tilesets)),
+		TOCFields: make([][]cacheMap, len(project.TableOfContents)),
+		Worlds:    make([]int, len(project.Levels)),
+	}
+
+	worldIndices := map[*World]int{}
+	for i, world := range project.Worlds {
+		worldIndices[world] = i
+	}
+
+	for i, level := range project.Levels {
+		if index, exists := worldIndices[level.world]; exists {
+			data.Worlds[i] = index
+		} else {
+			data.Worlds[i] = -1
+		}
+	}
+
+	for i, tileset := range project.Tilesets {
+
+		cached := cacheTileset{}
+
+		for _, id := range tileset.CustomDataTileIDs() {
+			cached.CustomDataIDs = append(cached.CustomDataIDs, id)
+			cached.CustomData = append(cached.CustomData, tileset.CustomData[id])
+		}
+
+		for _, id := range tileset.EnumTileIDs() {
+			cached.EnumIDs = append(cached.EnumIDs, id)
+			cached.Enums = append(cached.Enums, tileset.Enums[id])
+		}
+
+		data.Tilesets[i] = cached
+
+	}
+
+	for i, entry := range project.TableOfContents {
+		data.TOCFields[i] = make([]cacheMap, len(entry.Instances))
+		for j, instance := range entry.Instances {
+			if instance.Fields != nil {
+				data.TOCFields[i][j], _ = toCacheValue(instance.Fields).(cacheMap)
+			}
+		}
+	}
+
+	return data
+
+}
+
+// cacheCopy returns a shallow copy of the Project suitable for encoding; pointers back up the hierarchy (which would otherwise form cycles),
+// shared Tileset pointers, values that are reconstructed after loading, and maps (which are stored separately in the cacheData) are left out.
+func (project *Project) cacheCopy() *Project {
+
+	cached := *project
+	cached.BGColor = nil
+	cached.Properties = cacheProperties(project.Properties)
+	cached.LevelFieldDefinitions = cacheFieldDefinitions(project.LevelFieldDefinitions)
+
+	cached.Tilesets = make([]*Tileset, len(project.Tilesets))
+
+	for i, tileset := range project.Tilesets {
+		tilesetCopy := *tileset
+		tilesetCopy.CustomData = nil
+		tilesetCopy.Enums = nil
+		cached.Tilesets[i] = &tilesetCopy
+	}
+
+	if project.TableOfContents != nil {
+		cached.TableOfContents = make([]*TOCEntry, len(project.TableOfContents))
+		for i, entry := range project.TableOfContents {
+			entryCopy := *entry
+			entryCopy.Instances = make([]*TOCInstance, len(entry.Instances))
+			for j, instance := range entry.Instances {
+				instanceCopy := *instance
+				instanceCopy.Fields = nil
+				entryCopy.Instances[j] = &instanceCopy
+			}
+			cached.TableOfContents[i] = &entryCopy
+		}
+	}
+
+	cached.EntityDefinitions = make([]*EntityDefinition, len(project.EntityDefinitions))
+
+	for i, def := range project.EntityDefinitions {
+		defCopy := *def
+		defCopy.Color = nil
+		defCopy.TileRect = def.TileRect.cacheCopy()
+		defCopy.FieldDefinitions = cacheFieldDefinitions(def.FieldDefinitions)
+		cached.EntityDefinitions[i] = &defCopy
+	}
+
+	cached.LayerDefinitions = make([]*LayerDefinition, len(project.LayerDefinitions))
+
+	for i, def := range project.LayerDefinitions {
+		defCopy := *def
+		defCopy.IntGridValues = make([]*IntGridValueDefinition, len(def.IntGridValues))
+		for j, value := range def.IntGridValues {
+			valueCopy := *value
+			valueCopy.Color = nil
+			defCopy.IntGridValues[j] = &valueCopy
+		}
+		cached.LayerDefinitions[i] = &defCopy
+	}
+
+	cached.Levels = make([]*Level, len(project.Levels))
+
+	for i, level := range project.Levels {
+
+		levelCopy := *level
+		levelCopy.Project = nil
+		levelCopy.BGColor = nil
+		levelCopy.Properties = cacheProperties(level.Properties)
+		levelCopy.Layers = make([]*Layer, len(level.Layers))
+
+		for j, layer := range level.Layers {
+
+			layerCopy := *layer
+			layerCopy.Tileset = nil
+			layerCopy.Entities = make([]*Entity, len(layer.Entities))
+
+			for k, entity := range layer.Entities {
+				entityCopy := *entity
+				entityCopy.SmartColor = nil
+				entityCopy.Data = nil
+				entityCopy.TileRect = entity.TileRect.cacheCopy()
+				entityCopy.Properties = cacheProperties(entity.Properties)
+				layerCopy.Entities[k] = &entityCopy
+			}
+
+			levelCopy.Layers[j] = &layerCopy
+
+		}
+
+		cached.Levels[i] = &levelCopy
+
+	}
+
+	return &cached
+
+}
+
+// cacheProperties returns copies of the Properties given with the maps in their values replaced by cacheMaps.
+func cacheProperties(properties []*Property) []*Property {
+
+	if properties == nil {
+		return nil
+	}
+
+	cached := make([]*Property, len(properties))
+
+	for i, prop := range properties {
+		propCopy := *prop
+		propCopy.project = nil
+		propCopy.entityRefs = nil
+		propCopy.Value = toCacheValue(prop.Value)
+		cached[i] = &propCopy
+	}
+
+	return cached
+
+}
+
+// cacheFieldDefinitions returns copies of the field definitions given with the maps in their default values replaced by cacheMaps.
+func cacheFieldDefinitions(defs []*FieldDefinition) []*FieldDefinition {
+
+	if defs == nil {
+		return nil
+	}
+
+	cached := make([]*FieldDefinition, len(defs))
+
+	for i, def := range defs {
+		defCopy := *def
+		defCopy.DefaultValue = toCacheValue(def.DefaultValue)
+		cached[i] = &defCopy
+	}
+
+	return cached
+
+}
+
+// toCacheValue returns a copy of a value decoded from JSON (i.e. a Property's value) with any maps within it replaced by cacheMaps.
+func toCacheValue(value interface{}) interface{} {
+
+	switch v := value.(type) {
+
+	case []interface{}:
+		if v == nil {
+			return v
+		}
+		converted := make([]interface{}, len(v))
+		for i, element := range v {
+			converted[i] = toCacheValue(element)
+		}
+		return converted
+
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		converted := make(cacheMap, len(keys))
+		for i, key := range keys {
+			converted[i] = cacheMapEntry{Key: key, Value: toCacheValue(v[key])}
+		}
+		return converted
+
+	}
+
+	return value
+
+}
+
+// fromCacheValue reverses toCacheValue, replacing the cacheMaps within a value read from a cache with maps.
+func fromCacheValue(value interface{}) interface{} {
+
+	switch v := value.(type) {
+
+	case []interface{}:
+		for i, element := range v {
+			v[i] = fromCacheValue(element)
+		}
+		return v
+
+	case cacheMap:
+		converted := make(map[string]interface{}, len(v))
+		for _, entry := range v {
+			converted[entry.Key] = fromCacheValue(entry.Value)
+		}
+		return converted
+
+	}
+
+	return value
+
+}
+
+func (tileRect *TileRect) cacheCopy() *TileRect {
+	if tileRect == nil {
+		return nil
+	}
+	rectCopy := *tileRect
+	rectCopy.Tileset = nil
+	return &rectCopy
+}
@@ -0,0 +1,173 @@
+package camera
+
+// camera is a small, engine-agnostic camera for scrolling around LDtk Levels. It follows a target (i.e. the player) with an optional dead
+// zone, keeps its view within a Level's bounds (centering Levels that are smaller than the screen), and produces the offset and zoom to draw
+// the Level with; the Ebitengine renderer can turn a Camera into draw options directly (see ebitengine.DrawOptions.ApplyCamera):
+//
+//	cam := camera.New(320, 240)
+//	cam.ClampToLevel(level)
+//	...
+//	cam.Follow(player.X, player.Y)
+//	offsetX, offsetY := cam.Offset()
+
+import (
+	"image"
+	"math"
+
+	"github.com/solarlune/ldtkgo"
+)
+
+// Camera is a view onto a Level (or the world, when drawing several Levels at once). Its position is the center of its view, in the same
+// coordinates as its Bounds.
+type Camera struct {
+	X, Y           float64         // The position of the center of the Camera's view
+	Width, Height  float64         // The size of the screen (or viewport) the Camera's view is drawn to, in pixels
+	Zoom           float64         // How much the view is magnified; 2 shows half as much of the Level at twice the size. If 0, the view isn't magnified
+	Bounds         image.Rectangle // The area the Camera's view is kept within (i.e. a Level's area; see ClampToLevel); if empty, the Camera can move freely
+	DeadZoneWidth  float64         // The width of the area in the center of the view that the target of Follow can move within without the Camera moving
+	DeadZoneHeight float64         // The height of the area in the center of the view that the target of Follow can move within without the Camera moving
+	Snap           bool            // Whether to round the Camera's Offset to whole pixels (at the Camera's Zoom), which avoids seams between tiles and shimmering in pixel art games
+}
+
+// New creates a new Camera for a screen (or viewport) of the size given.
+func New(width, height float64) *Camera {
+	return &Camera{
+		Width:  width,
+		Height: height,
+		Zoom:   1,
+	}
+}
+
+// ClampToLevel keeps the Camera's view within the Level, in level coordinates (from 0, 0 to the Level's size), which is how the renderers
+// draw a single Level (i.e. with Renderer.Render). When drawing Levels at their positions in the world, use ClampToLevelInWorld instead.
+func (camera *Camera) ClampToLevel(level *ldtkgo.Level) {
+	camera.Bounds = image.Rect(0, 0, level.Width, level.Height)
+	camera.Clamp()
+}
+
+// ClampToLevelInWorld keeps the Camera's view within the Level's WorldBounds, in world coordinates, for when Levels are drawn at their
+// positions in the world (i.e. with Renderer.RenderWorld). To keep the view within several Levels, set the Camera's Bounds to the union of
+// their WorldBounds instead (or leave it empty).
+func (camera *Camera) ClampToLevelInWorld(level *ldtkgo.Level) {
+	camera.Bounds = level.WorldBounds()
+	camera.Clamp()
+}
+
+// CenterOn moves the Camera to center its view on the position given (as far as its Bounds allow), ignoring the dead zone; this is useful
+// to jump to the player when entering a Level.
+func (camera *Camera) CenterOn(x, y float64) {
+	camera.X = x
+	camera.Y = y
+	camera.Clamp()
+}
+
+// Follow moves the Camera just enough to keep the position given (i.e. the player's) within its dead zone, and then within its Bounds.
+// With no dead zone, the Camera stays centered on the position.
+func (camera *Camera) Follow(x, y float64) {
+	camera.X = follow(camera.X, x, camera.DeadZoneWidth/2)
+	camera.Y = follow(camera.Y, y, camera.DeadZoneHeight/2)
+	camera.Clamp()
+}
+
+// Clamp moves the Camera so that its view is within its Bounds. If the Bounds are smaller than the view along an axis, the view is centered
+// on the Bounds along that axis instead.
+func (camera *Camera) Clamp() {
+
+	if camera.Bounds.Empty() {
+		return
+	}
+
+	viewWidth, viewHeight := camera.ViewSize()
+
+	camera.X = clamp(camera.X, float64(camera.Bounds.Min.X), float64(camera.Bounds.Max.X), viewWidth)
+	camera.Y = clamp(camera.Y, float64(camera.Bounds.Min.Y), float64(camera.Bounds.Max.Y), viewHeight)
+
+}
+
+// ViewSize returns the size of the area the Camera sees, which is the size of the screen divided by the Camera's Zoom.
+func (camera *Camera) ViewSize() (float64, float64) {
+	zoom := camera.zoom()
+	return camera.Width / zoom, camera.Height / zoom
+}
+
+// Position returns the position of the top-left corner of the Camera's view.
+func (camera *Camera) Position() (float64, float64) {
+
+	viewWidth, viewHeight := camera.ViewSize()
+
+	x := camera.X - viewWidth/2
+	y := camera.Y - viewHeight/2
+
+	if camera.Snap {
+		zoom := camera.zoom()
+		x = math.Round(x*zoom) / zoom
+		y = math.Round(y*zoom) / zoom
+	}
+
+	return x, y
+
+}
+
+// Offset returns how far to move the Level when drawing it (before scaling it by the Camera's Zoom) to draw the Camera's view to the screen.
+func (camera *Camera) Offset() (float64, float64) {
+	x, y := camera.Position()
+	return -x, -y
+}
+
+// View returns the area the Camera sees, rounded outwards to whole pixels; when drawing several Levels, only the ones that overlap it need
+// to be drawn.
+func (camera *Camera) View() image.Rectangle {
+
+	x, y := camera.Position()
+	viewWidth, viewHeight := camera.ViewSize()
+
+	return image.Rect(int(math.Floor(x)), int(math.Floor(y)), int(math.Ceil(x+viewWidth)), int(math.Ceil(y+viewHeight)))
+
+}
+
+// WorldToScreen converts a position in the Camera's coordinates (i.e. in a Level) to a position on the screen.
+func (camera *Camera) WorldToScreen(x, y float64) (float64, float64) {
+	left, top := camera.Position()
+	zoom := camera.zoom()
+	return (x - left) * zoom, (y - top) * zoom
+}
+
+// ScreenToWorld converts a position on the screen (i.e. the mouse cursor's) to a position in the Camera's coordinates.
+func (camera *Camera) ScreenToWorld(x, y float64) (float64, float64) {
+	left, top := camera.Position()
+	zoom := camera.zoom()
+	return x/zoom + left, y/zoom + top
+}
+
+func (camera *Camera) zoom() float64 {
+	if camera.Zoom <= 0 {
+		return 1
+	}
+	return camera.Zoom
+}
+
+// follow returns the position of the center of a view along an axis after following the target given with a dead zone that extends halfDeadZone
+// from the center.
+func follow(center, target, halfDeadZone float64) float64 {
+
+	if target < center-halfDeadZone {
+		return target + halfDeadZone
+	} else if target > center+halfDeadZone {
+		return target - halfDeadZone
+	}
+
+	return center
+
+}
+
+// clamp returns the position of the center of a view of the size given along an axis, clamped so the view is within min and max (or centered
+// between them if the view is larger).
+func clamp(center, min, max, size float64) float64 {
+
+	if max-min <= size {
+		return (min + max) / 2
+	}
+
+	return math.Max(min+size/2, math.Min(center, max-size/2))
+
+}
@@ -0,0 +1,49 @@
+package camera
+
+import (
+	"image"
+	"os"
+	"testing"
+
+	"github.com/solarlune/ldtkgo"
+)
+
+func TestClampToLevel(t *testing.T) {
+
+	project, err := ldtkgo.Open("example.ldtk", os.DirFS("../testdata/example"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The example's Levels are at -1, -1 in the world; the other Level is far from the origin.
+	levels := append([]*ldtkgo.Level{}, project.Levels...)
+	levels = append(levels, &ldtkgo.Level{Identifier: "Far", WorldX: 512, WorldY: -256, Width: 320, Height: 240})
+
+	for _, level := range levels {
+
+		cam := New(160, 120)
+		cam.CenterOn(-1000, -1000)
+		cam.ClampToLevel(level)
+
+		if want := image.Rect(0, 0, 160, 120); cam.View() != want {
+			t.Errorf("level %s: view clamped to the top-left is %v, not %v", level.Identifier, cam.View(), want)
+		}
+
+		cam.CenterOn(1e6, 1e6)
+
+		if want := image.Rect(level.Width-160, level.Height-120, level.Width, level.Height); cam.View() != want {
+			t.Errorf("level %s: view clamped to the bottom-right is %v, not %v", level.Identifier, cam.View(), want)
+		}
+
+		cam = New(160, 120)
+		cam.CenterOn(-1000, -1000)
+		cam.ClampToLevelInWorld(level)
+
+		if want := image.Rect(level.WorldX, level.WorldY, level.WorldX+160, level.WorldY+120); cam.View() != want {
+			t.Errorf("level %s: view clamped in the world is %v, not %v", level.Identifier, cam.View(), want)
+		}
+
+	}
+
+}
@@ -0,0 +1,473 @@
+package ldtkgo
+
+// cloner deep-copies parts of a Project, keeping track of which clone each original Level, Layer, Entity, and Tileset was copied to so that
+// pointers between them (like resolved entity references) can be pointed at the clones afterwards.
+type cloner struct {
+	project    *Project // The Project the clones belong to
+	levels     map[*Level]*Level
+	layers     map[*Layer]*Layer
+	entities   map[*Entity]*Entity
+	tilesets   map[*Tileset]*Tileset
+	properties []*Property
+}
+
+func newCloner(project *Project) *cloner {
+	return &cloner{
+		project:  project,
+		levels:   map[*Level]*Level{},
+		layers:   map[*Layer]*Layer{},
+		entities: map[*Entity]*Entity{},
+		tilesets: map[*Tileset]*Tileset{},
+	}
+}
+
+// Clone returns a deep copy of the Project, including its definitions and Levels. Nothing is shared between the Project and its clone (other
+// than the file system it was loaded from and the values of Entities' Data fields), so the clone can be modified freely; this allows a pristine
+// copy of the Project to be kept as a template (i.e. for restarting the game).
+func (project *Project) Clone() *Project {
+
+	clone := *project
+	c := newCloner(&clone)
+
+	clone.Tilesets = make([]*Tileset, len(project.Tilesets))
+	for i, tileset := range project.Tilesets {
+		clone.Tilesets[i] = c.cloneTileset(tileset)
+	}
+
+	clone.IntGridNames = copyStrings(project.IntGridNames)
+
+	clone.EntityDefinitions = make([]*EntityDefinition, len(project.EntityDefinitions))
+	for i, def := range project.EntityDefinitions {
+		defCopy := *def
+		defCopy.Tags = copyStrings(def.Tags)
+		defCopy.TileRect = c.cloneTileRect(def.TileRect)
+		defCopy.FieldDefinitions = cloneFieldDefinitions(def.FieldDefinitions)
+		clone.EntityDefinitions[i] = &defCopy
+	}
+
+	clone.LayerDefinitions = make([]*LayerDefinition, len(project.LayerDefinitions))
+	for i, def := range project.LayerDefinitions {
+		clone.LayerDefinitions[i] = def.clone()
+	}
+
+	if project.TableOfContents != nil {
+		clone.TableOfContents = make([]*TOCEntry, len(project.TableOfContents))
+		for i, entry := range project.TableOfContents {
+			entryCopy := *entry
+			entryCopy.Instances = make([]*TOCInstance, len(entry.Instances))
+			for j, instance := range entry.Instances {
+				instanceCopy := *instance
+				if instance.Fields != nil {
+					instanceCopy.Fields, _ = cloneValue(instance.Fields).(map[string]interface{})
+				}
+				entryCopy.Instances[j] = &instanceCopy
+			}
+			clone.TableOfContents[i] = &entryCopy
+		}
+	}
+
+	if project.CustomCommands != nil {
+		clone.CustomCommands = make([]*CustomCommand, len(project.CustomCommands))
+		for i, command := range project.CustomCommands {
+			commandCopy := *command
+			clone.CustomCommands[i] = &commandCopy
+		}
+	}
+
+	clone.LevelFieldDefinitions = cloneFieldDefinitions(project.LevelFieldDefinitions)
+
+	clone.Properties = c.cloneProperties(project.Properties)
+	clone.Flags = copyStrings(project.Flags)
+	clone.ExternalEnumPaths = copyStrings(project.ExternalEnumPaths)
+
+	clone.Levels = make([]*Level, len(project.Levels))
+	for i, level := range project.Levels {
+		clone.Levels[i] = c.cloneLevel(level)
+	}
+
+	if project.Worlds != nil {
+		clone.Worlds = make([]*World, len(project.Worlds))
+		for i, world := range project.Worlds {
+			worldCopy := *world
+			worldCopy.levels = make([]*Level, 0, len(world.levels))
+			for _, level := range world.levels {
+				if levelClone := c.levels[level]; levelClone != nil {
+					levelClone.world = &worldCopy
+					worldCopy.levels = append(worldCopy.levels, levelClone)
+				}
+			}
+			clone.Worlds[i] = &worldCopy
+		}
+	}
+
+	clone.setupDefinitions()
+
+	c.relink()
+
+	return &clone
+
+}
+
+// Clone returns a deep copy of the Level, including its Layers, Entities, Tiles, and Properties. The clone still belongs to the same Project,
+// but isn't added to the Project's Levels. Entity references between Entities in the Level point to the cloned Entities, while references to
+// Entities in other Levels are left as-is. This allows a pristine copy of the Level to be kept as a template (i.e. for respawning collected pickups
+// when the Level is re-entered).
+func (level *Level) Clone() *Level {
+	c := newCloner(level.Project)
+	clone := c.cloneLevel(level)
+	c.relink()
+	return clone
+}
+
+// Clone returns a deep copy of the Layer, including its Entities, Tiles, and IntGrid values. The clone still belongs to the same Level, but isn't
+// added to the Level's Layers. Entity references between Entities in the Layer point to the cloned Entities.
+func (layer *Layer) Clone() *Layer {
+	c := newCloner(nil)
+	clone := c.cloneLayer(layer, layer.level)
+	c.relink()
+	return clone
+}
+
+// Clone returns a deep copy of the Entity, including its Properties. The clone still belongs to the same Layer and Level, but isn't added to the
+// Layer's Entities. Note that the Entity's Data is copied as-is, rather than deeply.
+func (entity *Entity) Clone() *Entity {
+	c := newCloner(nil)
+	clone := c.cloneEntity(entity, entity.level, entity.layer)
+	c.relink()
+	return clone
+}
+
+func (c *cloner) cloneLevel(level *Level) *Level {
+
+	clone := *level
+	c.levels[level] = &clone
+
+	if c.project != nil {
+		clone.Project = c.project
+	}
+
+	if level.BGImage != nil {
+		bgImage := *level.BGImage
+		bgImage.CropRect = copyFloats(level.BGImage.CropRect)
+		clone.BGImage = &bgImage
+	}
+
+	clone.Properties = c.cloneProperties(level.Properties)
+
+	if level.Neighbours != nil {
+		clone.Neighbours = append([]Neighbour{}, level.Neighbours...)
+	}
+
+	if level.Layers != nil {
+		clone.Layers = make([]*Layer, len(level.Layers))
+		for i, layer := range level.Layers {
+			clone.Layers[i] = c.cloneLayer(layer, &clone)
+		}
+	}
+
+	return &clone
+
+}
+
+func (c *cloner) cloneLayer(layer *Layer, level *Level) *Layer {
+
+	clone := *layer
+	c.layers[layer] = &clone
+
+	clone.memory = nil // The clone's contents are allocated below, not from an Allocator
+
+	clone.level = level
+	clone.Tileset = c.tileset(layer.Tileset)
+	clone.OptionalRules = copyInts(layer.OptionalRules)
+
+	if layer.IntGrid != nil {
+		integers := make([]Integer, len(layer.IntGrid))
+		clone.IntGrid = make([]*Integer, len(layer.IntGrid))
+		for i, integer := range layer.IntGrid {
+			integers[i] = *integer
+			integers[i].Position = copyInts(integer.Position)
+			clone.IntGrid[i] = &integers[i]
+		}
+	}
+
+	clone.Tiles = cloneTiles(layer.Tiles, &clone)
+	clone.AutoTiles = cloneTiles(layer.AutoTiles, &clone)
+
+	if layer.Entities != nil {
+		clone.Entities = make([]*Entity, len(layer.Entities))
+		for i, entity := range layer.Entities {
+			clone.Entities[i] = c.cloneEntity(entity, level, &clone)
+		}
+	}
+
+	return &clone
+
+}
+
+func cloneTiles(tiles []*Tile, layer *Layer) []*Tile {
+
+	if tiles == nil {
+		return nil
+	}
+
+	// The Tiles are allocated together, like they are when they're loaded.
+	values := make([]Tile, len(tiles))
+	clones := make([]*Tile, len(tiles))
+
+	for i, tile := range tiles {
+		values[i] = *tile
+		values[i].Position = copyInts(tile.Position)
+		values[i].Src = copyInts(tile.Src)
+		values[i].layer = layer
+		clones[i] = &values[i]
+	}
+
+	return clones
+
+}
+
+func (c *cloner) cloneEntity(entity *Entity, level *Level, layer *Layer) *Entity {
+
+	clone := *entity
+	c.entities[entity] = &clone
+
+	clone.level = level
+	clone.layer = layer
+	clone.Position = copyInts(entity.Position)
+	clone.GridPosition = copyInts(entity.GridPosition)
+	clone.Tags = copyStrings(entity.Tags)
+	clone.TileRect = c.cloneTileRect(entity.TileRect)
+	clone.Properties = c.cloneProperties(entity.Properties)
+
+	if entity.Pivot != nil {
+		clone.Pivot = append([]float32{}, entity.Pivot...)
+	}
+
+	return &clone
+
+}
+
+func (c *cloner) cloneProperties(properties []*Property) []*Property {
+
+	if properties == nil {
+		return nil
+	}
+
+	clones := make([]*Property, len(properties))
+
+	for i, prop := range properties {
+
+		clone := *prop
+		clone.Value = cloneValue(prop.Value)
+
+		if c.project != nil {
+			clone.project = c.project
+		}
+
+		if prop.entityRefs != nil {
+			clone.entityRefs = append([]ResolvedEntityRef{}, prop.entityRefs...)
+		}
+
+		clones[i] = &clone
+		c.properties = append(c.properties, &clone)
+
+	}
+
+	return clones
+
+}
+
+func (c *cloner) cloneTileRect(tileRect *TileRect) *TileRect {
+	if tileRect == nil {
+		return nil
+	}
+	clone := *tileRect
+	clone.Tileset = c.tileset(tileRect.Tileset)
+	return &clone
+}
+
+func (c *cloner) cloneTileset(tileset *Tileset) *Tileset {
+
+	clone := *tileset
+	c.tilesets[tileset] = &clone
+
+	clone.Tags = copyStrings(tileset.Tags)
+	clone.TagsSourceEnumUID = copyIntPointer(tileset.TagsSourceEnumUID)
+
+	if tileset.CustomData != nil {
+		clone.CustomData = make(map[int]string, len(tileset.CustomData))
+		for id, data := range tileset.CustomData {
+			clone.CustomData[id] = data
+		}
+	}
+
+	if tileset.Enums != nil {
+		clone.Enums = make(map[int]EnumSet, len(tileset.Enums))
+		for id, enums := range tileset.Enums {
+			clone.Enums[id] = EnumSet(copyStrings(enums))
+		}
+	}
+
+	return &clone
+
+}
+
+// tileset returns the clone of the Tileset given, or the Tileset itself if it wasn't cloned (i.e. when cloning a Level within the same Project).
+func (c *cloner) tileset(tileset *Tileset) *Tileset {
+	if clone, exists := c.tilesets[tileset]; exists {
+		return clone
+	}
+	return tileset
+}
+
+// relink points the resolved entity references of the cloned Properties at the clones of the Entities, Layers, and Levels they referred to.
+func (c *cloner) relink() {
+	for _, prop := range c.properties {
+		for i, ref := range prop.entityRefs {
+			if clone, exists := c.entities[ref.Entity]; exists {
+				prop.entityRefs[i].Entity = clone
+			}
+			if clone, exists := c.layers[ref.Layer]; exists {
+				prop.entityRefs[i].Layer = clone
+			}
+			if clone, exists := c.levels[ref.Level]; exists {
+				prop.entityRefs[i].Level = clone
+			}
+		}
+	}
+}
+
+func (def *LayerDefinition) clone() *LayerDefinition {
+
+	clone := *def
+
+	if def.IntGridValues != nil {
+		clone.IntGridValues = make([]*IntGridValueDefinition, len(def.IntGridValues))
+		for i, value := range def.IntGridValues {
+			valueCopy := *value
+			clone.IntGridValues[i] = &valueCopy
+		}
+	}
+
+	if def.AutoRuleGroups != nil {
+		clone.AutoRuleGroups = make([]*AutoRuleGroup, len(def.AutoRuleGroups))
+		for i, group := range def.AutoRuleGroups {
+			groupCopy := *group
+			if group.Rules != nil {
+				groupCopy.Rules = make([]*AutoRule, len(group.Rules))
+				for j, rule := range group.Rules {
+					ruleCopy := *rule
+					ruleCopy.Pattern = copyInts(rule.Pattern)
+					if rule.TileRectIDs != nil {
+						ruleCopy.TileRectIDs = make([][]int, len(rule.TileRectIDs))
+						for k, ids := range rule.TileRectIDs {
+							ruleCopy.TileRectIDs[k] = copyInts(ids)
+						}
+					}
+					if rule.OutOfBoundsValue != nil {
+						value := *rule.OutOfBoundsValue
+						ruleCopy.OutOfBoundsValue = &value
+					}
+					groupCopy.Rules[j] = &ruleCopy
+				}
+			}
+			clone.AutoRuleGroups[i] = &groupCopy
+		}
+	}
+
+	return &clone
+
+}
+
+func cloneFieldDefinitions(defs []*FieldDefinition) []*FieldDefinition {
+
+	if defs == nil {
+		return nil
+	}
+
+	clones := make([]*FieldDefinition, len(defs))
+
+	for i, def := range defs {
+		clone := *def
+		clone.ArrayMinLength = copyIntPointer(def.ArrayMinLength)
+		clone.ArrayMaxLength = copyIntPointer(def.ArrayMaxLength)
+		clone.Min = copyFloatPointer(def.Min)
+		clone.Max = copyFloatPointer(def.Max)
+		clone.AcceptFileTypes = copyStrings(def.AcceptFileTypes)
+		clone.AllowedRefTags = copyStrings(def.AllowedRefTags)
+		clone.AllowedRefsEntityUID = copyIntPointer(def.AllowedRefsEntityUID)
+		clone.TilesetUID = copyIntPointer(def.TilesetUID)
+		clones[i] = &clone
+	}
+
+	return clones
+
+}
+
+// cloneValue deep-copies a value decoded from JSON (i.e. a Property's value).
+func cloneValue(value interface{}) interface{} {
+
+	switch v := value.(type) {
+
+	case []interface{}:
+		if v == nil {
+			return v
+		}
+		clone := make([]interface{}, len(v))
+		for i, element := range v {
+			clone[i] = cloneValue(element)
+		}
+		return clone
+
+	case map[string]interface{}:
+		if v == nil {
+			return v
+		}
+		clone := make(map[string]interface{}, len(v))
+		for key, element := range v {
+			clone[key] = cloneValue(element)
+		}
+		return clone
+
+	}
+
+	return value
+
+}
+
+func copyInts(values []int) []int {
+	if values == nil {
+		return nil
+	}
+	return append([]int{}, values...)
+}
+
+func copyFloats(values []float64) []float64 {
+	if values == nil {
+		return nil
+	}
+	return append([]float64{}, values...)
+}
+
+func copyStrings(values []string) []string {
+	if values == nil {
+		return nil
+	}
+	return append([]string{}, values...)
+}
+
+func copyIntPointer(value *int) *int {
+	if value == nil {
+		return nil
+	}
+	v := *value
+	return &v
+}
+
+func copyFloatPointer(value *float64) *float64 {
+	if value == nil {
+		return nil
+	}
+	v := *value
+	return &v
+}
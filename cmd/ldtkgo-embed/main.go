@@ -0,0 +1,186 @@
+// ldtkgo-embed generates a Go file that embeds an LDtk project and every file it references (tileset and background images, external level
+// files, external enum files, and the files of FilePath fields; see Project.AssetPaths) using //go:embed directives, along with a function
+// returning the files as an fs.FS that the project can be opened from. This avoids listing the assets by hand (and missing some, which only
+// shows up when the game is built for the web or shipped). It's meant to be run using go:generate from the package the file is written to:
+//
+//	//go:generate go run github.com/solarlune/ldtkgo/cmd/ldtkgo-embed -o ldtk_embed.go assets/world.ldtk
+//
+// The generated file declares LDtkFS (returning the file system, with the project's directory as its root if the project doesn't reference any
+// files outside of it) and LDtkProjectPath (the path of the project file within it), so the project can be loaded using:
+//
+//	project, err := ldtkgo.Open(LDtkProjectPath, LDtkFS())
+//
+// The prefix of the names can be changed using -name. As with any //go:embed directive, the project and its assets have to be within the
+// package's directory.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/solarlune/ldtkgo"
+)
+
+func main() {
+
+	output := flag.String("o", "ldtk_embed.go", "path of the Go file to write")
+	pkg := flag.String("pkg", os.Getenv("GOPACKAGE"), "name of the package of the generated file; defaults to the package go:generate is run from")
+	name := flag.String("name", "LDtk", "prefix of the names declared in the generated file")
+
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: ldtkgo-embed [flags] project.ldtk\n")
+		flag.PrintDefaults()
+	}
+
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if *pkg == "" {
+		log.Fatal("the package name couldn't be determined; set it using -pkg")
+	}
+
+	// Embedded paths are relative to the directory of the generated file.
+	projectPath, err := embedPath(filepath.Dir(*output), flag.Arg(0))
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	projectDir := path.Dir(projectPath)
+
+	project, err := ldtkgo.Open(path.Base(projectPath), os.DirFS(filepath.Join(filepath.Dir(*output), filepath.FromSlash(projectDir))))
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	files := []string{projectPath}
+
+	for _, assetPath := range project.AssetPaths() {
+
+		file := path.Join(projectDir, assetPath)
+
+		if file == ".." || strings.HasPrefix(file, "../") {
+			log.Fatalf("%s is outside of the directory of %s, so it can't be embedded", assetPath, *output)
+		}
+
+		if _, err := os.Stat(filepath.Join(filepath.Dir(*output), filepath.FromSlash(file))); err != nil {
+			log.Printf("warning: %s is referenced by the project, but doesn't exist, so it isn't embedded", file)
+			continue
+		}
+
+		files = append(files, file)
+
+	}
+
+	// The file system is rooted at the project's directory, unless the project references files outside of it.
+	root := projectDir
+
+	for _, file := range files {
+		if root != "." && !strings.HasPrefix(file, root+"/") {
+			root = "."
+		}
+	}
+
+	projectFile := projectPath
+
+	if root != "." {
+		projectFile = path.Base(projectPath)
+	}
+
+	source, err := generate(*pkg, *name, root, projectFile, files)
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := os.WriteFile(*output, source, 0644); err != nil {
+		log.Fatal(err)
+	}
+
+	log.Printf("embedded %s and %d referenced files in %s", projectPath, len(files)-1, *output)
+
+}
+
+// embedPath returns the path of the file given relative to the directory given, slash-separated, or an error if it's outside of the directory.
+func embedPath(dir, file string) (string, error) {
+
+	absDir, err := filepath.Abs(dir)
+
+	if err != nil {
+		return "", err
+	}
+
+	absFile, err := filepath.Abs(file)
+
+	if err != nil {
+		return "", err
+	}
+
+	rel, err := filepath.Rel(absDir, absFile)
+
+	if err != nil {
+		return "", err
+	}
+
+	rel = filepath.ToSlash(rel)
+
+	if rel == ".." || strings.HasPrefix(rel, "../") {
+		return "", fmt.Errorf("%s is outside of the directory of the generated file (%s), so it can't be embedded", file, dir)
+	}
+
+	return rel, nil
+
+}
+
+// generate returns the formatted source of the Go file embedding the files given.
+func generate(pkg, name, root, projectFile string, files []string) ([]byte, error) {
+
+	buffer := &bytes.Buffer{}
+
+	fmt.Fprintf(buffer, "// Code generated by ldtkgo-embed; DO NOT EDIT.\n\n")
+	fmt.Fprintf(buffer, "package %s\n\n", pkg)
+	fmt.Fprintf(buffer, "import (\n\t\"embed\"\n\t\"io/fs\"\n)\n\n")
+
+	fmt.Fprintf(buffer, "// %sProjectPath is the path of the LDtk project file in the file system returned by %sFS.\n", name, name)
+	fmt.Fprintf(buffer, "const %sProjectPath = %s\n\n", name, strconv.Quote(projectFile))
+
+	for _, file := range files {
+		// Paths with spaces (or other special characters) have to be quoted.
+		if strings.ContainsAny(file, " \t\"`'") {
+			file = strconv.Quote(file)
+		}
+		fmt.Fprintf(buffer, "//go:embed %s\n", file)
+	}
+
+	fmt.Fprintf(buffer, "var embedded%sFiles embed.FS\n\n", name)
+
+	fmt.Fprintf(buffer, "// %sFS returns the file system holding the LDtk project and the files it references, so the project can be loaded using\n", name)
+	fmt.Fprintf(buffer, "// ldtkgo.Open(%sProjectPath, %sFS()).\n", name, name)
+	fmt.Fprintf(buffer, "func %sFS() fs.FS {\n", name)
+
+	if root == "." {
+		fmt.Fprintf(buffer, "\treturn embedded%sFiles\n", name)
+	} else {
+		fmt.Fprintf(buffer, "\tfileSystem, err := fs.Sub(embedded%sFiles, %s)\n", name, strconv.Quote(root))
+		fmt.Fprintf(buffer, "\tif err != nil {\n\t\tpanic(err)\n\t}\n")
+		fmt.Fprintf(buffer, "\treturn fileSystem\n")
+	}
+
+	fmt.Fprintf(buffer, "}\n")
+
+	return format.Source(buffer.Bytes())
+
+}
@@ -0,0 +1,60 @@
+// ldtkgo-gen generates large LDtk projects (with as many Levels, Tiles, IntGrid cells, and Entities as desired) for measuring the performance of
+// LDtk-Go outside of its benchmarks (i.e. in a game, or with other tools). The generated projects are deterministic for a given seed, so
+// measurements can be compared between changes:
+//
+//	go run github.com/solarlune/ldtkgo/cmd/ldtkgo-gen -levels 2000 -o big.ldtk
+//
+// Without -o, the project is written to standard output. The benchmarks of LDtk-Go generate the same projects directly.
+package main
+
+import (
+	"flag"
+	"io"
+	"log"
+	"os"
+
+	"github.com/solarlune/ldtkgo/internal/gen"
+)
+
+func main() {
+
+	defaults := gen.DefaultOptions()
+
+	levels := flag.Int("levels", defaults.Levels, "number of levels")
+	width := flag.Int("width", defaults.Width, "width of each level in cells")
+	height := flag.Int("height", defaults.Height, "height of each level in cells")
+	entities := flag.Int("entities", defaults.Entities, "number of entities in each level")
+	gridSize := flag.Int("grid", defaults.GridSize, "grid size of the layers in pixels")
+	seed := flag.Int64("seed", defaults.Seed, "random seed")
+	output := flag.String("o", "", "file to write the project to (standard output if empty)")
+	flag.Parse()
+
+	data, err := gen.Project(gen.Options{
+		Levels:   *levels,
+		Width:    *width,
+		Height:   *height,
+		Entities: *entities,
+		GridSize: *gridSize,
+		Seed:     *seed,
+	})
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var out io.Writer = os.Stdout
+
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	if _, err := out.Write(data); err != nil {
+		log.Fatal(err)
+	}
+
+}
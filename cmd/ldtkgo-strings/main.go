@@ -0,0 +1,86 @@
+// ldtkgo-strings extracts the text of an LDtk project (the values of String and Multilines fields on Levels and Entities) into a keyed
+// localization file for translators, using the localize package. The translated file can then be loaded at runtime using localize.ReadJSON,
+// localize.ReadCSV, or localize.ReadPO, and applied to the project using Table.Apply:
+//
+//	go run github.com/solarlune/ldtkgo/cmd/ldtkgo-strings -format po -o strings.pot assets/world.ldtk
+//
+// The format is json (the default), csv, or po. Without -o, the file is written to standard output.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/solarlune/ldtkgo"
+	"github.com/solarlune/ldtkgo/localize"
+)
+
+func main() {
+
+	format := flag.String("format", "json", "format of the output file: json, csv, or po")
+	output := flag.String("o", "", "path of the output file; if blank, the output is written to standard output")
+
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: ldtkgo-strings [flags] project.ldtk\n")
+		flag.PrintDefaults()
+	}
+
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var write func(io.Writer, []localize.Entry) error
+
+	switch *format {
+	case "json":
+		write = localize.WriteJSON
+	case "csv":
+		write = localize.WriteCSV
+	case "po":
+		write = localize.WritePO
+	default:
+		log.Fatalf("unknown format %q", *format)
+	}
+
+	path := flag.Arg(0)
+
+	project, err := ldtkgo.Open(filepath.ToSlash(filepath.Base(path)), os.DirFS(filepath.Dir(path)))
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	entries := localize.Extract(project)
+
+	writer := io.Writer(os.Stdout)
+
+	if *output != "" {
+
+		file, err := os.Create(*output)
+
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		defer file.Close()
+
+		writer = file
+
+	}
+
+	if err := write(writer, entries); err != nil {
+		log.Fatal(err)
+	}
+
+	if *output != "" {
+		log.Printf("wrote %d strings to %s", len(entries), *output)
+	}
+
+}
@@ -0,0 +1,232 @@
+package collision
+
+// collision converts the contents of LDtk Levels (IntGrid cells, tiles tagged with enums, and Entities) into collision shapes, so that setting
+// up collision for a Level with a physics or collision library (i.e. resolv) only needs a loop over the shapes returned by LevelShapes:
+//
+//	for _, shape := range collision.LevelShapes(level, nil) {
+//		r := shape.Rect
+//		space.Add(resolv.NewRectangle(float64(r.Min.X), float64(r.Min.Y), float64(r.Dx()), float64(r.Dy())))
+//	}
+
+import (
+	"image"
+
+	"github.com/solarlune/ldtkgo"
+)
+
+// Kind indicates how a Shape should be collided with.
+type Kind int
+
+const (
+	KindSolid     Kind = iota // The Shape is solid from all sides
+	KindOneWay                // The Shape is only solid from above (i.e. a platform that can be jumped through from below)
+	KindSlopeUp               // The Shape is a right triangle whose slope rises from its bottom-left corner to its top-right corner
+	KindSlopeDown             // The Shape is a right triangle whose slope falls from its top-left corner to its bottom-right corner
+	KindEntity                // The Shape is the bounding box of an Entity
+)
+
+// DefaultTileEnums are the tile enum values that LevelShapes turns into Shapes by default, following common platformer conventions.
+var DefaultTileEnums = map[string]Kind{
+	"Solid":     KindSolid,
+	"OneWay":    KindOneWay,
+	"SlopeUp":   KindSlopeUp,
+	"SlopeDown": KindSlopeDown,
+}
+
+// Shape represents a collision shape created from a Level.
+type Shape struct {
+	Kind   Kind
+	Rect   image.Rectangle // The bounds of the Shape in pixels; for slopes, this is the rectangle that contains the triangle
+	Value  int             // For Shapes made from IntGrid cells, the IntGrid value of the cells
+	Tile   *ldtkgo.Tile    // For Shapes made from tiles, the Tile the Shape was made from
+	Entity *ldtkgo.Entity  // For Shapes made from Entities, the Entity the Shape was made from
+}
+
+// Points returns the corners of the Shape's outline in clockwise order: three for slopes, and four for other Shapes.
+func (shape Shape) Points() []image.Point {
+
+	r := shape.Rect
+
+	switch shape.Kind {
+	case KindSlopeUp:
+		return []image.Point{{r.Max.X, r.Min.Y}, {r.Max.X, r.Max.Y}, {r.Min.X, r.Max.Y}}
+	case KindSlopeDown:
+		return []image.Point{{r.Min.X, r.Min.Y}, {r.Max.X, r.Max.Y}, {r.Min.X, r.Max.Y}}
+	}
+
+	return []image.Point{r.Min, {r.Max.X, r.Min.Y}, r.Max, {r.Min.X, r.Max.Y}}
+
+}
+
+// Options customizes which parts of a Level LevelShapes turns into Shapes.
+type Options struct {
+	IntGridLayers []string        // The identifiers of the IntGrid layers whose cells become Shapes; if empty, all IntGrid layers are used
+	SolidValues   []int           // The IntGrid values whose cells become solid Shapes; if empty, all non-empty cells do
+	TileEnums     map[string]Kind // The tile enum values whose tiles become Shapes, and the Kind of Shape for each; if nil, DefaultTileEnums is used
+	EntityTags    []string        // The tags of the Entities whose bounding boxes become Shapes; Entities with none of these tags are skipped
+	World         bool            // If true, the Shapes are positioned in world space (offset by the Level's position); otherwise, they're relative to the Level
+}
+
+// LevelShapes returns the collision Shapes of the Level given: a solid Shape for each rectangle of IntGrid cells with the same value (cells
+// are merged into as few rectangles as possible), a Shape for each tile tagged with one of the tile enums (horizontally flipped slopes
+// face the other way), and a Shape for each Entity with one of the Entity tags. If options is nil, the default Options are used.
+func LevelShapes(level *ldtkgo.Level, options *Options) []Shape {
+
+	if options == nil {
+		options = &Options{}
+	}
+
+	tileEnums := options.TileEnums
+	if tileEnums == nil {
+		tileEnums = DefaultTileEnums
+	}
+
+	offset := image.Point{}
+	if options.World {
+		offset = image.Pt(level.WorldX, level.WorldY)
+	}
+
+	shapes := []Shape{}
+
+	for _, layer := range level.Layers {
+
+		if layer.Type == ldtkgo.LayerTypeIntGrid && (len(options.IntGridLayers) == 0 || contains(options.IntGridLayers, layer.Identifier)) {
+			shapes = append(shapes, intGridShapes(layer, options.SolidValues, offset)...)
+		}
+
+		layer.ForEachTile(func(tile *ldtkgo.Tile) {
+
+			for _, enum := range tile.Enums() {
+
+				kind, exists := tileEnums[enum]
+
+				if !exists {
+					continue
+				}
+
+				if tile.FlipX() {
+					switch kind {
+					case KindSlopeUp:
+						kind = KindSlopeDown
+					case KindSlopeDown:
+						kind = KindSlopeUp
+					}
+				}
+
+				min := image.Pt(tile.Position[0]+layer.OffsetX, tile.Position[1]+layer.OffsetY).Add(offset)
+
+				shapes = append(shapes, Shape{
+					Kind: kind,
+					Rect: image.Rectangle{min, min.Add(image.Pt(layer.GridSize, layer.GridSize))},
+					Tile: tile,
+				})
+
+				break
+
+			}
+
+		})
+
+		for _, entity := range layer.Entities {
+			for _, tag := range options.EntityTags {
+				if entity.HasTag(tag) {
+					shapes = append(shapes, Shape{
+						Kind:   KindEntity,
+						Rect:   entity.Bounds().Add(offset),
+						Entity: entity,
+					})
+					break
+				}
+			}
+		}
+
+	}
+
+	return shapes
+
+}
+
+// intGridShapes returns solid Shapes covering the cells of the IntGrid layer given with the values given (or any value, if none are given),
+// merging neighbouring cells with the same value into rectangles.
+func intGridShapes(layer *ldtkgo.Layer, values []int, offset image.Point) []Shape {
+
+	rows := layer.IntGridCSV()
+	used := make([][]bool, len(rows))
+	for y := range used {
+		used[y] = make([]bool, len(rows[y]))
+	}
+
+	free := func(x, y, value int) bool {
+		return rows[y][x] == value && !used[y][x]
+	}
+
+	shapes := []Shape{}
+
+	for y, row := range rows {
+
+		for x, value := range row {
+
+			if value == 0 || used[y][x] || (len(values) > 0 && !containsInt(values, value)) {
+				continue
+			}
+
+			// Grow the rectangle to the right as far as possible, and then downwards for as long as every cell of the next row matches.
+			width := 1
+			for x+width < len(row) && free(x+width, y, value) {
+				width++
+			}
+
+			height := 1
+			for y+height < len(rows) {
+				matches := true
+				for i := 0; i < width; i++ {
+					if !free(x+i, y+height, value) {
+						matches = false
+						break
+					}
+				}
+				if !matches {
+					break
+				}
+				height++
+			}
+
+			for j := 0; j < height; j++ {
+				for i := 0; i < width; i++ {
+					used[y+j][x+i] = true
+				}
+			}
+
+			min := image.Pt(x*layer.GridSize+layer.OffsetX, y*layer.GridSize+layer.OffsetY).Add(offset)
+
+			shapes = append(shapes, Shape{
+				Kind:  KindSolid,
+				Rect:  image.Rectangle{min, min.Add(image.Pt(width*layer.GridSize, height*layer.GridSize))},
+				Value: value,
+			})
+
+		}
+
+	}
+
+	return shapes
+
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func containsInt(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
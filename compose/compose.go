@@ -0,0 +1,391 @@
+package compose
+
+// compose assembles runtime worlds out of LDtk Levels, treating each Level as a prefab "room" that can be placed anywhere in the world (i.e.
+// for roguelikes that generate dungeons out of hand-made chunks).
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"image"
+
+	"github.com/solarlune/ldtkgo"
+)
+
+var ErrorNoPlacements = "no levels have been placed"
+var ErrorGridSizeMismatch = "placed layers with the same identifier have different grid sizes"
+var ErrorTilesetMismatch = "placed layers with the same identifier use different tilesets"
+var ErrorUnalignedPlacement = "placement isn't aligned to the layer's grid"
+
+// Errors returned by WorldBuilders that can be checked for using errors.Is; their messages are the Error strings.
+var (
+	ErrNoPlacements       = errors.New(ErrorNoPlacements)
+	ErrGridSizeMismatch   = errors.New(ErrorGridSizeMismatch)
+	ErrTilesetMismatch    = errors.New(ErrorTilesetMismatch)
+	ErrUnalignedPlacement = errors.New(ErrorUnalignedPlacement)
+)
+
+// Placement represents a Level placed in the world by a WorldBuilder.
+type Placement struct {
+	Source *ldtkgo.Level // The Level that was placed
+	X, Y   int           // Position of the Level's top-left corner in the world, in pixels
+
+	iids     map[string]string
+	entities []*ldtkgo.Entity
+}
+
+// Bounds returns the rectangle the placed Level covers in the world, in pixels.
+func (placement *Placement) Bounds() image.Rectangle {
+	return image.Rect(placement.X, placement.Y, placement.X+placement.Source.Width, placement.Y+placement.Source.Height)
+}
+
+// IID returns the IID that the Entity, Layer, or Level with the IID given in the source Level has in the built world, or an empty string if
+// the IID doesn't belong to the placed Level. As a Level can be placed several times, each placement gets its own IIDs. The IIDs are only
+// available once the world has been built.
+func (placement *Placement) IID(sourceIID string) string {
+	return placement.iids[sourceIID]
+}
+
+// WorldBuilder assembles a world out of Levels placed at arbitrary positions. When built, the placed Levels' Layers are stitched together by
+// identifier into a single Level, with their tiles, IntGrid values, and Entities offset to their placements.
+type WorldBuilder struct {
+	Identifier string // Identifier (name) of the built Level; defaults to "World"
+	placements []*Placement
+}
+
+// NewWorldBuilder creates a new, empty WorldBuilder.
+func NewWorldBuilder() *WorldBuilder {
+	return &WorldBuilder{
+		Identifier: "World",
+		placements: []*Placement{},
+	}
+}
+
+// Place places the Level given with its top-left corner at the position given in the world, in pixels. Levels can be placed any number of
+// times, and can come from different Projects. Placements that overlap are drawn in the order they were placed. The Placement is returned
+// so that the Entities of the placed Level can be found in the built world.
+func (builder *WorldBuilder) Place(level *ldtkgo.Level, x, y int) *Placement {
+	placement := &Placement{
+		Source: level,
+		X:      x,
+		Y:      y,
+		iids:   map[string]string{},
+	}
+	builder.placements = append(builder.placements, placement)
+	return placement
+}
+
+// Placements returns the Levels placed in the WorldBuilder so far, in the order they were placed.
+func (builder *WorldBuilder) Placements() []*Placement {
+	return builder.placements
+}
+
+// Build stitches the placed Levels together into a new Project containing a single Level that covers all of them. The Project contains the
+// Tilesets and definitions used by the placed Levels; Tilesets' paths are resolved relative to the root of the file system their Projects
+// were loaded from, and UIDs are remapped wherever different Projects' definitions collide. Entity references between Entities in the same
+// placement are remapped to the placed copies. The source Levels aren't modified.
+func (builder *WorldBuilder) Build() (*ldtkgo.Project, error) {
+
+	if len(builder.placements) == 0 {
+		return nil, ErrNoPlacements
+	}
+
+	bounds := builder.placements[0].Bounds()
+	for _, placement := range builder.placements[1:] {
+		bounds = bounds.Union(placement.Bounds())
+	}
+
+	first := builder.placements[0].Source
+
+	project := &ldtkgo.Project{
+		WorldLayout:       ldtkgo.WorldLayoutFree,
+		BGColorString:     first.BGColorString,
+		BGColor:           first.BGColor,
+		Levels:            []*ldtkgo.Level{},
+		Tilesets:          []*ldtkgo.Tileset{},
+		IntGridNames:      []string{},
+		EntityDefinitions: []*ldtkgo.EntityDefinition{},
+		LayerDefinitions:  []*ldtkgo.LayerDefinition{},
+		TableOfContents:   []*ldtkgo.TOCEntry{},
+		CustomCommands:    []*ldtkgo.CustomCommand{},
+	}
+
+	if first.Project != nil {
+		project.JSONVersion = first.Project.JSONVersion
+		project.BGColorString = first.Project.BGColorString
+		project.BGColor = first.Project.BGColor
+	}
+
+	defs := newDefinitions(project)
+
+	level := &ldtkgo.Level{
+		Identifier:    builder.Identifier,
+		WorldX:        bounds.Min.X,
+		WorldY:        bounds.Min.Y,
+		Width:         bounds.Dx(),
+		Height:        bounds.Dy(),
+		IID:           newIID(),
+		BGColorString: first.BGColorString,
+		Layers:        []*ldtkgo.Layer{},
+		Properties:    []*ldtkgo.Property{},
+		Neighbours:    []ldtkgo.Neighbour{},
+	}
+
+	for _, placement := range builder.placements {
+
+		placement.iids = map[string]string{placement.Source.IID: level.IID}
+		placement.entities = []*ldtkgo.Entity{}
+
+		offsetX := placement.X - bounds.Min.X
+		offsetY := placement.Y - bounds.Min.Y
+
+		for _, source := range placement.Source.Layers {
+
+			layer, err := builder.layerFor(level, placement, source, defs)
+			if err != nil {
+				return nil, err
+			}
+
+			placement.iids[source.IID] = layer.IID
+
+			if err := stitchLayer(layer, source, offsetX, offsetY, placement, defs); err != nil {
+				return nil, fmt.Errorf("%w (layer %s, level %s)", err, source.Identifier, placement.Source.Identifier)
+			}
+
+		}
+
+	}
+
+	// Entity references can only be remapped once every Entity in a placement has been given its new IID.
+	for _, placement := range builder.placements {
+		for _, entity := range placement.entities {
+			for _, prop := range entity.Properties {
+				remapEntityRefs(prop, placement, level)
+			}
+		}
+	}
+
+	project.AddLevel(level)
+
+	return project, nil
+
+}
+
+// layerFor returns the Layer in the built Level that the source Layer given is stitched into, creating it if it doesn't exist yet.
+func (builder *WorldBuilder) layerFor(level *ldtkgo.Level, placement *Placement, source *ldtkgo.Layer, defs *definitions) (*ldtkgo.Layer, error) {
+
+	tilesetUID := defs.tilesetUID(placement.Source.Project, source.Tileset, source.TilesetUID)
+
+	for _, layer := range level.Layers {
+
+		if layer.Identifier != source.Identifier {
+			continue
+		}
+
+		if layer.GridSize != source.GridSize {
+			return nil, fmt.Errorf("%w: [%s]", ErrGridSizeMismatch, source.Identifier)
+		}
+
+		if layer.TilesetUID != tilesetUID && source.TileCount() > 0 {
+			if layer.TileCount() > 0 {
+				return nil, fmt.Errorf("%w: [%s]", ErrTilesetMismatch, source.Identifier)
+			}
+			layer.TilesetUID = tilesetUID
+		}
+
+		return layer, nil
+
+	}
+
+	layer := &ldtkgo.Layer{
+		Identifier:    source.Identifier,
+		IID:           newIID(),
+		GridSize:      source.GridSize,
+		OffsetX:       source.OffsetX,
+		OffsetY:       source.OffsetY,
+		Type:          source.Type,
+		TilesetUID:    tilesetUID,
+		IntGrid:       []*ldtkgo.Integer{},
+		AutoTiles:     []*ldtkgo.Tile{},
+		Tiles:         []*ldtkgo.Tile{},
+		Entities:      []*ldtkgo.Entity{},
+		Visible:       source.Visible,
+		DefUID:        defs.layerDefUID(placement.Source.Project, source.DefUID),
+		Seed:          source.Seed,
+		OptionalRules: append([]int{}, source.OptionalRules...),
+	}
+
+	if layer.GridSize > 0 {
+		layer.CellWidth = (level.Width + layer.GridSize - 1) / layer.GridSize
+		layer.CellHeight = (level.Height + layer.GridSize - 1) / layer.GridSize
+	}
+
+	level.Layers = append(level.Layers, layer)
+
+	return layer, nil
+
+}
+
+// stitchLayer copies the contents of the source Layer into the Layer given, offset by the position given in pixels.
+func stitchLayer(layer, source *ldtkgo.Layer, offsetX, offsetY int, placement *Placement, defs *definitions) error {
+
+	copyTiles := func(tiles []*ldtkgo.Tile) []*ldtkgo.Tile {
+		copied := make([]*ldtkgo.Tile, 0, len(tiles))
+		for _, tile := range tiles {
+			copied = append(copied, &ldtkgo.Tile{
+				Position: []int{tile.Position[0] + offsetX, tile.Position[1] + offsetY},
+				Src:      append([]int{}, tile.Src...),
+				Flip:     tile.Flip,
+				ID:       tile.ID,
+				RuleUID:  tile.RuleUID,
+			})
+		}
+		return copied
+	}
+
+	layer.Tiles = append(layer.Tiles, copyTiles(source.Tiles)...)
+	layer.AutoTiles = append(layer.AutoTiles, copyTiles(source.AutoTiles)...)
+
+	if len(source.IntGrid) > 0 {
+
+		if offsetX%layer.GridSize != 0 || offsetY%layer.GridSize != 0 {
+			return ErrUnalignedPlacement
+		}
+
+		cellX, cellY := offsetX/layer.GridSize, offsetY/layer.GridSize
+
+		// Cells from later placements replace any cells already stitched into the same position.
+		existing := map[int]*ldtkgo.Integer{}
+		for _, integer := range layer.IntGrid {
+			existing[integer.ID] = integer
+		}
+
+		for _, integer := range source.IntGrid {
+
+			x := integer.ID%source.CellWidth + cellX
+			y := integer.ID/source.CellWidth + cellY
+			id := y*layer.CellWidth + x
+
+			if current, exists := existing[id]; exists {
+				current.Value = integer.Value
+				continue
+			}
+
+			copied := &ldtkgo.Integer{
+				Position: []int{x * layer.GridSize, y * layer.GridSize},
+				Value:    integer.Value,
+				ID:       id,
+			}
+
+			existing[id] = copied
+			layer.IntGrid = append(layer.IntGrid, copied)
+
+		}
+
+	}
+
+	for _, entity := range source.Entities {
+
+		copied := *entity
+		copied.IID = newIID()
+		copied.Position = []int{entity.Position[0] + offsetX, entity.Position[1] + offsetY}
+		copied.GridPosition = nil
+		copied.Pivot = append([]float32{}, entity.Pivot...)
+		copied.Tags = append([]string{}, entity.Tags...)
+		copied.DefUID = defs.entityDefUID(placement.Source.Project, entity)
+
+		if entity.TileRect != nil {
+			tileRect := *entity.TileRect
+			tileRect.TilesetUID = defs.tilesetUID(placement.Source.Project, entity.TileRect.Tileset, entity.TileRect.TilesetUID)
+			copied.TileRect = &tileRect
+		}
+
+		copied.Properties = make([]*ldtkgo.Property, 0, len(entity.Properties))
+		for _, prop := range entity.Properties {
+			copied.Properties = append(copied.Properties, &ldtkgo.Property{
+				Identifier: prop.Identifier,
+				Type:       prop.Type,
+				Value:      copyValue(prop.Value),
+			})
+		}
+
+		placement.iids[entity.IID] = copied.IID
+		placement.entities = append(placement.entities, &copied)
+
+		layer.Entities = append(layer.Entities, &copied)
+
+	}
+
+	return nil
+
+}
+
+// remapEntityRefs points the EntityRef values of the Property given (which belongs to an Entity copied by the placement) that refer to
+// Entities in the placement's source Level to their copies.
+func remapEntityRefs(prop *ldtkgo.Property, placement *Placement, level *ldtkgo.Level) {
+
+	if prop.LDtkType() != ldtkgo.PropertyTypeEntityRef {
+		return
+	}
+
+	values := []interface{}{prop.Value}
+	if array, ok := prop.Value.([]interface{}); ok {
+		values = array
+	}
+
+	for _, value := range values {
+
+		ref, ok := value.(map[string]interface{})
+		if !ok || ref["levelIid"] != placement.Source.IID {
+			continue
+		}
+
+		entityIID, _ := ref["entityIid"].(string)
+		layerIID, _ := ref["layerIid"].(string)
+
+		if placement.iids[entityIID] == "" {
+			continue
+		}
+
+		ref["entityIid"] = placement.iids[entityIID]
+		ref["layerIid"] = placement.iids[layerIID]
+		ref["levelIid"] = level.IID
+		delete(ref, "worldIid")
+
+	}
+
+}
+
+// copyValue deep-copies a Property value as decoded from JSON.
+func copyValue(value interface{}) interface{} {
+
+	switch v := value.(type) {
+
+	case []interface{}:
+		copied := make([]interface{}, len(v))
+		for i, element := range v {
+			copied[i] = copyValue(element)
+		}
+		return copied
+
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(v))
+		for key, element := range v {
+			copied[key] = copyValue(element)
+		}
+		return copied
+
+	}
+
+	return value
+
+}
+
+// newIID returns a new random IID in the same format as LDtk's (a version 4 UUID).
+func newIID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
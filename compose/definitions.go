@@ -0,0 +1,174 @@
+package compose
+
+import (
+	"github.com/solarlune/ldtkgo"
+)
+
+// definitions collects the Tilesets and definitions used by placed Levels into the built Project. As the placed Levels can come from different
+// Projects, definitions whose UIDs collide with ones that have already been collected are given new UIDs.
+type definitions struct {
+	project  *ldtkgo.Project
+	tilesets map[*ldtkgo.Tileset]int
+	entities map[*ldtkgo.EntityDefinition]int
+	layers   map[*ldtkgo.LayerDefinition]int
+}
+
+func newDefinitions(project *ldtkgo.Project) *definitions {
+	return &definitions{
+		project:  project,
+		tilesets: map[*ldtkgo.Tileset]int{},
+		entities: map[*ldtkgo.EntityDefinition]int{},
+		layers:   map[*ldtkgo.LayerDefinition]int{},
+	}
+}
+
+// tilesetUID returns the UID the Tileset given has in the built Project, adding it to the Project if necessary. The Tileset's path is resolved
+// using the Project it was loaded from, so that it stays valid in the built Project; Tilesets from different Projects that use the same image
+// are merged. If the Tileset is nil, the UID given is returned as-is.
+func (defs *definitions) tilesetUID(source *ldtkgo.Project, tileset *ldtkgo.Tileset, uid int) int {
+
+	if tileset == nil {
+		return uid
+	}
+
+	if existing, exists := defs.tilesets[tileset]; exists {
+		return existing
+	}
+
+	copied := *tileset
+
+	if source != nil && copied.Path != "" {
+		copied.Path = source.ResolvePath(copied.Path)
+	}
+
+	used := map[int]bool{}
+
+	for _, t := range defs.project.Tilesets {
+		// Tilesets from different Projects that use the same image in the same way are merged.
+		if t.Path != "" && t.Path == copied.Path && t.GridSize == copied.GridSize && t.Spacing == copied.Spacing && t.Padding == copied.Padding {
+			defs.tilesets[tileset] = t.ID
+			return t.ID
+		}
+		used[t.ID] = true
+	}
+
+	copied.ID = freeUID(tileset.ID, used)
+
+	defs.project.Tilesets = append(defs.project.Tilesets, &copied)
+	defs.tilesets[tileset] = copied.ID
+
+	return copied.ID
+
+}
+
+// entityDefUID returns the UID the definition of the Entity given has in the built Project, adding the definition to the Project if necessary.
+func (defs *definitions) entityDefUID(source *ldtkgo.Project, entity *ldtkgo.Entity) int {
+
+	def := entity.Definition()
+
+	if def == nil {
+		return entity.DefUID
+	}
+
+	if existing, exists := defs.entities[def]; exists {
+		return existing
+	}
+
+	copied := *def
+	copied.Tags = append([]string{}, def.Tags...)
+
+	if def.TileRect != nil {
+		tileRect := *def.TileRect
+		tileRect.TilesetUID = defs.tilesetUID(source, def.TileRect.Tileset, def.TileRect.TilesetUID)
+		copied.TileRect = &tileRect
+	}
+
+	used := map[int]bool{}
+	for _, d := range defs.project.EntityDefinitions {
+		used[d.UID] = true
+	}
+	copied.UID = freeUID(def.UID, used)
+
+	defs.project.EntityDefinitions = append(defs.project.EntityDefinitions, &copied)
+	defs.entities[def] = copied.UID
+
+	return copied.UID
+
+}
+
+// layerDefUID returns the UID the LayerDefinition with the UID given has in the built Project, adding the definition (along with the IntGrid
+// definition it's based on, for AutoLayers) to the Project if necessary.
+func (defs *definitions) layerDefUID(source *ldtkgo.Project, uid int) int {
+
+	if source == nil {
+		return uid
+	}
+
+	def := source.LayerDefinitionByUID(uid)
+
+	if def == nil {
+		return uid
+	}
+
+	if existing, exists := defs.layers[def]; exists {
+		return existing
+	}
+
+	copied := *def
+	copied.IntGridValues = append([]*ldtkgo.IntGridValueDefinition{}, def.IntGridValues...)
+	copied.AutoRuleGroups = append([]*ldtkgo.AutoRuleGroup{}, def.AutoRuleGroups...)
+
+	used := map[int]bool{}
+	for _, d := range defs.project.LayerDefinitions {
+		used[d.UID] = true
+	}
+	copied.UID = freeUID(def.UID, used)
+
+	// The definition is registered before its references are remapped, as an IntGrid layer's auto-layer rules can use the layer itself as the source.
+	defs.project.LayerDefinitions = append(defs.project.LayerDefinitions, &copied)
+	defs.layers[def] = copied.UID
+
+	if def.TilesetUID != 0 {
+		copied.TilesetUID = defs.tilesetUID(source, tilesetByUID(source, def.TilesetUID), def.TilesetUID)
+	}
+
+	if def.AutoSourceLayerDefUID != 0 {
+		copied.AutoSourceLayerDefUID = defs.layerDefUID(source, def.AutoSourceLayerDefUID)
+	}
+
+	if def.Type == ldtkgo.LayerTypeIntGrid {
+		for _, value := range def.IntGridValues {
+			defs.project.IntGridNames = append(defs.project.IntGridNames, value.Identifier)
+		}
+	}
+
+	return copied.UID
+
+}
+
+// tilesetByUID returns the Tileset with the UID given in the Project, or nil if one isn't found.
+func tilesetByUID(project *ldtkgo.Project, uid int) *ldtkgo.Tileset {
+	for _, tileset := range project.Tilesets {
+		if tileset.ID == uid {
+			return tileset
+		}
+	}
+	return nil
+}
+
+// freeUID returns the UID given if it isn't used yet, or otherwise a new UID that's greater than all of the used ones.
+func freeUID(uid int, used map[int]bool) int {
+
+	if !used[uid] {
+		return uid
+	}
+
+	for existing := range used {
+		if existing >= uid {
+			uid = existing + 1
+		}
+	}
+
+	return uid
+
+}
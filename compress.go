@@ -0,0 +1,146 @@
+package ldtkgo
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"sync"
+)
+
+// Decompressor is a function that returns a reader of the decompressed contents of the compressed data read from the reader given.
+type Decompressor func(reader io.Reader) (io.Reader, error)
+
+type decompressor struct {
+	magic      []byte
+	decompress Decompressor
+}
+
+// zstdMagic is the magic number at the start of zstd-compressed data, which is recognized so that a helpful error can be returned if no zstd
+// Decompressor has been registered.
+var zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+
+var decompressorsMutex = sync.RWMutex{}
+
+var decompressors = []decompressor{
+	{
+		magic: []byte{0x1f, 0x8b},
+		decompress: func(reader io.Reader) (io.Reader, error) {
+			return gzip.NewReader(reader)
+		},
+	},
+}
+
+// RegisterDecompressor registers a Decompressor for data that starts with the magic bytes given, so that project and level files compressed in
+// that format are decompressed transparently when they're loaded (by Open, Read, ReadFrom, OpenAll, etc). gzip-compressed data is supported
+// out of the box. To keep LDtk-Go free of dependencies, zstd isn't, but a zstd Decompressor can be registered using any zstd package, i.e.
+// github.com/klauspost/compress/zstd:
+//
+//	ldtkgo.RegisterDecompressor([]byte{0x28, 0xb5, 0x2f, 0xfd}, func(reader io.Reader) (io.Reader, error) {
+//		return zstd.NewReader(reader)
+//	})
+//
+// A Decompressor registered for the same magic bytes as an earlier one replaces it.
+func RegisterDecompressor(magic []byte, decompress Decompressor) {
+
+	decompressorsMutex.Lock()
+	defer decompressorsMutex.Unlock()
+
+	for i, d := range decompressors {
+		if bytes.Equal(d.magic, magic) {
+			decompressors[i].decompress = decompress
+			return
+		}
+	}
+
+	decompressors = append(decompressors, decompressor{magic: append([]byte{}, magic...), decompress: decompress})
+
+}
+
+// findDecompressor returns the Decompressor for data starting with the bytes given, or nil if the data isn't compressed in a registered format.
+// An error is returned if the data is zstd-compressed, but no zstd Decompressor has been registered.
+func findDecompressor(header []byte) (Decompressor, error) {
+
+	decompressorsMutex.RLock()
+	defer decompressorsMutex.RUnlock()
+
+	for _, d := range decompressors {
+		if len(d.magic) > 0 && bytes.HasPrefix(header, d.magic) {
+			return d.decompress, nil
+		}
+	}
+
+	if bytes.HasPrefix(header, zstdMagic) {
+		return nil, errors.New("data is zstd-compressed, but no zstd decompressor is registered (see RegisterDecompressor)")
+	}
+
+	return nil, nil
+
+}
+
+// maxMagicLength returns the length of the longest registered magic number.
+func maxMagicLength() int {
+
+	decompressorsMutex.RLock()
+	defer decompressorsMutex.RUnlock()
+
+	length := len(zstdMagic)
+
+	for _, d := range decompressors {
+		if len(d.magic) > length {
+			length = len(d.magic)
+		}
+	}
+
+	return length
+
+}
+
+// decompressReader returns a reader of the decompressed contents of the reader given if its data is compressed in a registered format (and
+// true), or a reader of its data as-is otherwise.
+func decompressReader(reader io.Reader) (io.Reader, bool, error) {
+
+	buffered := bufio.NewReader(reader)
+
+	// Peek returns an error (along with fewer bytes) if the data is shorter than the magic number, which is fine.
+	header, _ := buffered.Peek(maxMagicLength())
+
+	decompress, err := findDecompressor(header)
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	if decompress == nil {
+		return buffered, false, nil
+	}
+
+	decompressed, err := decompress(buffered)
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	return decompressed, true, nil
+
+}
+
+// decompressBytes returns the decompressed contents of the data given if it's compressed in a registered format, or the data as-is otherwise.
+func decompressBytes(data []byte) ([]byte, error) {
+
+	decompress, err := findDecompressor(data)
+
+	if err != nil || decompress == nil {
+		return data, err
+	}
+
+	decompressed, err := decompress(bytes.NewReader(data))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return io.ReadAll(decompressed)
+
+}
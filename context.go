@@ -0,0 +1,195 @@
+package ldtkgo
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// OpenContext loads the LDtk project from the filepath specified using the file system provided, like Open. If the context given is
+// cancelled before the project has been read, loading stops and the context's error is returned.
+func OpenContext(ctx context.Context, filepath string, fileSystem fs.FS, options ...LoadOption) (*Project, error) {
+
+	project, err := openProject(ctx, filepath, fileSystem, newLoadConfig(options))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := project.loadExternalLevels(ctx, project.fileSystem); err != nil {
+		return nil, err
+	}
+
+	return project, nil
+
+}
+
+// openProject reads the LDtk project file at the filepath given, without loading any external level files. If the file system is nil,
+// the file is opened from the OS file system.
+func openProject(ctx context.Context, filePath string, fileSystem fs.FS, config *loadConfig) (*Project, error) {
+
+	projectPath := filePath
+
+	if fileSystem == nil {
+		fileSystem = os.DirFS(filepath.Dir(filePath))
+		projectPath = filepath.Base(filePath)
+		filePath = filepath.ToSlash(filePath)
+	}
+
+	file, err := fileSystem.Open(projectPath)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	project, err := readContext(ctx, file, readerSize(file), config)
+
+	if err != nil {
+		return nil, err
+	}
+
+	project.Path = filePath
+	project.fileSystem = fileSystem
+	project.dir = path.Dir(projectPath)
+
+	return project, nil
+
+}
+
+// ReadContext reads the LDtk project from the io.Reader given, like ReadFrom. If the context given is cancelled before the reader has been
+// read, loading stops and the context's error is returned.
+func ReadContext(ctx context.Context, reader io.Reader, options ...LoadOption) (*Project, error) {
+	return readContext(ctx, reader, readerSize(reader), newLoadConfig(options))
+}
+
+// OpenURL loads the LDtk project from the URL specified using an HTTP GET request (which is made using the Fetch API when running in a browser
+// through WebAssembly). The project is read as it's downloaded, so OnProgress can be used to display a loading screen, and the request can be
+// cancelled using the context given. Note that external level files aren't downloaded.
+func OpenURL(ctx context.Context, url string, options ...LoadOption) (*Project, error) {
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := http.DefaultClient.Do(request)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unable to fetch LDtk project from %s: %s", url, response.Status)
+	}
+
+	return readContext(ctx, response.Body, response.ContentLength, newLoadConfig(options))
+
+}
+
+func readContext(ctx context.Context, reader io.Reader, total int64, config *loadConfig) (*Project, error) {
+
+	reader = &progressReader{ctx: ctx, reader: reader, total: total, onProgress: config.onProgress}
+
+	reader, compressed, err := decompressReader(reader)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// The size of the decompressed data isn't known ahead of time (OnProgress still reports the compressed data read, out of its total size).
+	if compressed {
+		total = -1
+	}
+
+	if config.streamed() {
+
+		// Projects saved with older versions of LDtk have to be migrated before they're decoded, which needs all of their data.
+		buffered := bufio.NewReaderSize(reader, versionPeekSize)
+		header, _ := buffered.Peek(versionPeekSize)
+
+		if !needsMigration(peekJSONVersion(header)) {
+			return readStream(buffered, total, config)
+		}
+
+		reader = buffered
+
+	}
+
+	buffer := &bytes.Buffer{}
+
+	if total > 0 {
+		buffer.Grow(int(total) + bytes.MinRead)
+	}
+
+	if _, err := buffer.ReadFrom(reader); err != nil {
+		return nil, err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return read(buffer.Bytes(), config)
+
+}
+
+// versionPeekSize is how much of a project is read ahead when streaming it to find the version of LDtk it was saved with (see migrate.go).
+const versionPeekSize = 4096
+
+// readerSize returns the size of the data in the io.Reader given, or -1 if it can't be determined.
+func readerSize(reader io.Reader) int64 {
+
+	switch r := reader.(type) {
+
+	case interface{ Stat() (fs.FileInfo, error) }:
+		if info, err := r.Stat(); err == nil && info.Mode().IsRegular() {
+			return info.Size()
+		}
+
+	case interface{ Len() int }:
+		return int64(r.Len())
+
+	}
+
+	return -1
+
+}
+
+// progressReader wraps an io.Reader, reporting how much has been read and stopping once the context is done.
+type progressReader struct {
+	ctx        context.Context
+	reader     io.Reader
+	read       int64
+	total      int64
+	onProgress func(bytesRead, total int64)
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	n, err := r.reader.Read(p)
+
+	r.read += int64(n)
+
+	if n > 0 && r.onProgress != nil {
+		r.onProgress(r.read, r.total)
+	}
+
+	return n, err
+
+}
@@ -0,0 +1,556 @@
+package ldtkgo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+)
+
+// projectDefinitions represents the "defs" section of an LDtk project, which contains the definitions of the Project's tilesets, entities,
+// and layers.
+type projectDefinitions struct {
+	Tilesets      []*Tileset          `json:"tilesets"`
+	Entities      []*EntityDefinition `json:"entities"`
+	Layers        []*LayerDefinition  `json:"layers"`
+	Levels        []*FieldDefinition  `json:"levelFields"`
+	ExternalEnums []enumDefinition    `json:"externalEnums"`
+}
+
+// enumDefinition is the part of an enum definition that's read; only the paths of the files external enums are imported from are needed.
+type enumDefinition struct {
+	ExternalPath string `json:"externalRelPath"`
+}
+
+// The types below are what the types with UnmarshalJSON methods are decoded into; each embeds an alias of the type (so that its fields are
+// decoded as usual) along with the JSON values that need to be converted before they're stored.
+
+type projectAlias Project
+
+type projectJSON struct {
+	*projectAlias
+	Defs *projectDefinitions `json:"defs"`
+}
+
+type tilesetAlias Tileset
+
+type tilesetJSON struct {
+	*tilesetAlias
+	EnumTags []struct {
+		EnumValueID string `json:"enumValueId"`
+		TileIDs     []int  `json:"tileIds"`
+	} `json:"enumTags"`
+	CustomData []struct {
+		TileID int    `json:"tileId"`
+		Data   string `json:"data"`
+	} `json:"customData"`
+}
+
+type levelAlias Level
+
+type levelJSON struct {
+	*levelAlias
+	BGRelPath string  `json:"bgRelPath"`
+	BGPos     string  `json:"bgPos"`
+	BGPivotX  float64 `json:"bgPivotX"`
+	BGPivotY  float64 `json:"bgPivotY"`
+	BGPosData *struct {
+		TopLeftPx []float64 `json:"topLeftPx"`
+		Scale     []float64 `json:"scale"`
+		CropRect  []float64 `json:"cropRect"`
+	} `json:"__bgPos"`
+}
+
+type layerAlias Layer
+
+type layerJSON struct {
+	*layerAlias
+	IntGridCSV []int      `json:"intGridCsv"`
+	Tiles      []tileData `json:"gridTiles"`
+	AutoTiles  []tileData `json:"autoLayerTiles"`
+	Entities   []Entity   `json:"entityInstances"`
+}
+
+type propertyAlias Property
+
+type propertyJSON struct {
+	*propertyAlias
+	Value json.RawMessage `json:"__value"`
+}
+
+type worldAlias World
+
+type worldJSON struct {
+	*worldAlias
+	Levels []*Level `json:"levels"`
+}
+
+type tocEntryAlias TOCEntry
+
+type tocEntryJSON struct {
+	*tocEntryAlias
+	LegacyInstances []EntityReference `json:"instances"`
+}
+
+// UnmarshalJSON decodes a Project from LDtk JSON, including its definitions.
+func (project *Project) UnmarshalJSON(data []byte) error {
+
+	aux := projectJSON{projectAlias: (*projectAlias)(project)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	return project.finishDecoding(aux.Defs)
+
+}
+
+// unmarshalParallel decodes the project's JSON like UnmarshalJSON, but decodes its Levels concurrently using the number of workers given.
+// Each Level's JSON is independent of the others, so they're first split out as raw JSON, and then decoded into their own Levels.
+func (project *Project) unmarshalParallel(data []byte, workers int) error {
+
+	return project.unmarshalLevelsWith(data, func(levelData []json.RawMessage) ([]*Level, error) {
+
+		levels := make([]*Level, len(levelData))
+
+		err := runWorkerPool(len(levels), workers, func(index int) error {
+
+			if string(bytes.TrimSpace(levelData[index])) == "null" {
+				return nullEntryError("levels", index)
+			}
+
+			level := &Level{}
+
+			if err := json.Unmarshal(levelData[index], level); err != nil {
+				return err
+			}
+
+			levels[index] = level
+
+			return nil
+
+		})
+
+		return levels, err
+
+	})
+
+}
+
+// unmarshalLevelsWith decodes the project's JSON like UnmarshalJSON, but splits out the JSON of its Levels (and of the Levels of its Worlds)
+// as raw JSON, and decodes them using the function given.
+func (project *Project) unmarshalLevelsWith(data []byte, decodeLevels func(levelData []json.RawMessage) ([]*Level, error)) error {
+
+	aux := struct {
+		projectJSON
+		Levels []json.RawMessage `json:"levels"` // Shadows the Project's Levels, which are decoded below
+		Worlds []json.RawMessage `json:"worlds"` // Shadows the Project's Worlds, which are decoded below
+	}{projectJSON: projectJSON{projectAlias: (*projectAlias)(project)}}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	if aux.Levels != nil {
+
+		levels, err := decodeLevels(aux.Levels)
+
+		if err != nil {
+			return err
+		}
+
+		project.Levels = levels
+
+	}
+
+	if aux.Worlds != nil {
+
+		project.Worlds = make([]*World, len(aux.Worlds))
+
+		for i, worldData := range aux.Worlds {
+
+			if string(bytes.TrimSpace(worldData)) == "null" {
+				return nullEntryError("worlds", i)
+			}
+
+			world := &World{}
+
+			worldAux := struct {
+				*worldAlias
+				Levels []json.RawMessage `json:"levels"`
+			}{worldAlias: (*worldAlias)(world)}
+
+			if err := json.Unmarshal(worldData, &worldAux); err != nil {
+				return err
+			}
+
+			if worldAux.Levels != nil {
+				levels, err := decodeLevels(worldAux.Levels)
+				if err != nil {
+					return fmt.Errorf("world %s: %w", world.Identifier, err)
+				}
+				world.levels = levels
+			}
+
+			project.Worlds[i] = world
+
+		}
+
+	}
+
+	return project.finishDecoding(aux.Defs)
+
+}
+
+// finishDecoding checks the decoded Project for null entries and applies its definitions.
+func (project *Project) finishDecoding(defs *projectDefinitions) error {
+
+	for i, level := range project.Levels {
+		if level == nil {
+			return nullEntryError("levels", i)
+		}
+	}
+
+	for i, entry := range project.TableOfContents {
+		if entry == nil {
+			return nullEntryError("toc", i)
+		}
+	}
+
+	for i, command := range project.CustomCommands {
+		if command == nil {
+			return nullEntryError("customCommands", i)
+		}
+	}
+
+	for i, prop := range project.Properties {
+		if prop == nil {
+			return nullEntryError("fieldInstances", i)
+		}
+	}
+
+	if err := project.validateWorlds(); err != nil {
+		return err
+	}
+
+	project.addWorldLevels()
+
+	if defs != nil {
+		if err := defs.validate(); err != nil {
+			return err
+		}
+		project.applyDefinitions(defs)
+	}
+
+	return nil
+
+}
+
+// validate returns an error if any of the definitions are null, which LDtk never exports, but would otherwise cause panics later on.
+func (defs *projectDefinitions) validate() error {
+
+	for i, tileset := range defs.Tilesets {
+		if tileset == nil {
+			return nullEntryError("defs.tilesets", i)
+		}
+	}
+
+	for i, entity := range defs.Entities {
+		if entity == nil {
+			return nullEntryError("defs.entities", i)
+		}
+		for j, field := range entity.FieldDefinitions {
+			if field == nil {
+				return nullEntryError(fmt.Sprintf("defs.entities[%d].fieldDefs", i), j)
+			}
+		}
+	}
+
+	for i, field := range defs.Levels {
+		if field == nil {
+			return nullEntryError("defs.levelFields", i)
+		}
+	}
+
+	for i, layer := range defs.Layers {
+
+		if layer == nil {
+			return nullEntryError("defs.layers", i)
+		}
+
+		for j, value := range layer.IntGridValues {
+			if value == nil {
+				return nullEntryError(fmt.Sprintf("defs.layers[%d].intGridValues", i), j)
+			}
+		}
+
+		for j, group := range layer.AutoRuleGroups {
+			if group == nil {
+				return nullEntryError(fmt.Sprintf("defs.layers[%d].autoRuleGroups", i), j)
+			}
+			for k, rule := range group.Rules {
+				if rule == nil {
+					return nullEntryError(fmt.Sprintf("defs.layers[%d].autoRuleGroups[%d].rules", i, j), k)
+				}
+			}
+		}
+
+	}
+
+	return nil
+
+}
+
+// nullEntryError returns an error indicating that the entry at the index given in the JSON array given is null.
+func nullEntryError(array string, index int) error {
+	return fmt.Errorf("invalid project: %s[%d] is null", array, index)
+}
+
+// UnmarshalJSON decodes a Tileset from LDtk JSON, including its enum tags and custom tile data.
+func (tileset *Tileset) UnmarshalJSON(data []byte) error {
+
+	aux := tilesetJSON{tilesetAlias: (*tilesetAlias)(tileset)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	tileset.Path = filepath.FromSlash(tileset.Path)
+
+	tileset.Enums = map[int]EnumSet{}
+	for _, enumSet := range aux.EnumTags {
+		for _, id := range enumSet.TileIDs {
+			tileset.Enums[id] = append(tileset.Enums[id], enumSet.EnumValueID)
+		}
+	}
+
+	tileset.CustomData = map[int]string{}
+	for _, customData := range aux.CustomData {
+		tileset.CustomData[customData.TileID] = customData.Data
+	}
+
+	return nil
+
+}
+
+// UnmarshalJSON decodes a World from LDtk JSON, including its Levels.
+func (world *World) UnmarshalJSON(data []byte) error {
+
+	aux := worldJSON{worldAlias: (*worldAlias)(world)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	world.levels = aux.Levels
+
+	return nil
+
+}
+
+// UnmarshalJSON decodes a Level from LDtk JSON, including its background image.
+func (level *Level) UnmarshalJSON(data []byte) error {
+	return level.decode(data, false)
+}
+
+// skippedJSON is a JSON value that's skipped over when decoding, without allocating anything for it.
+type skippedJSON struct{}
+
+func (skippedJSON) UnmarshalJSON(data []byte) error {
+	return nil
+}
+
+// decode decodes a Level from LDtk JSON. If skipLayers is true, the Level's layer instances are skipped, leaving its Layers nil.
+func (level *Level) decode(data []byte, skipLayers bool) error {
+
+	aux := levelJSON{levelAlias: (*levelAlias)(level)}
+
+	var err error
+
+	if skipLayers {
+		err = json.Unmarshal(data, &struct {
+			*levelJSON
+			Layers skippedJSON `json:"layerInstances"` // Shadows the Level's Layers
+		}{levelJSON: &aux})
+	} else {
+		err = json.Unmarshal(data, &aux)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	for i, layer := range level.Layers {
+		if layer == nil {
+			return nullEntryError("layerInstances", i)
+		}
+	}
+
+	for i, prop := range level.Properties {
+		if prop == nil {
+			return nullEntryError("fieldInstances", i)
+		}
+	}
+
+	if aux.BGRelPath != "" {
+
+		level.BGImage = &BGImage{
+			Path:     aux.BGRelPath,
+			CropRect: make([]float64, 4),
+			PivotX:   aux.BGPivotX,
+			PivotY:   aux.BGPivotY,
+			Mode:     aux.BGPos,
+		}
+
+		if pos := aux.BGPosData; pos != nil {
+			if len(pos.Scale) >= 2 {
+				level.BGImage.ScaleX, level.BGImage.ScaleY = pos.Scale[0], pos.Scale[1]
+			}
+			if len(pos.TopLeftPx) >= 2 {
+				level.BGImage.TopLeftX, level.BGImage.TopLeftY = pos.TopLeftPx[0], pos.TopLeftPx[1]
+			}
+			copy(level.BGImage.CropRect, pos.CropRect)
+		}
+
+	}
+
+	return nil
+
+}
+
+// UnmarshalJSON decodes a Layer from LDtk JSON, including its IntGrid values.
+func (layer *Layer) UnmarshalJSON(data []byte) error {
+
+	aux := layerJSON{layerAlias: (*layerAlias)(layer)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	if layer.GridSize <= 0 && (len(aux.Tiles) > 0 || len(aux.AutoTiles) > 0 || len(aux.IntGridCSV) > 0) {
+		return fmt.Errorf("layer %s: invalid grid size %d", layer.Identifier, layer.GridSize)
+	}
+
+	layer.Tiles = newTiles(aux.Tiles, false)
+	layer.AutoTiles = newTiles(aux.AutoTiles, true)
+
+	layer.Entities = nil
+	if aux.Entities != nil {
+		layer.Entities = make([]*Entity, len(aux.Entities))
+		for i := range aux.Entities {
+			entity := &aux.Entities[i]
+			// Entities without a position are placed at the origin, but partial positions are rejected.
+			if entity.Position == nil {
+				entity.Position = []int{0, 0}
+			} else if len(entity.Position) < 2 {
+				return fmt.Errorf("entity %s: px has %d values, not 2", entity.Identifier, len(entity.Position))
+			}
+			for j, prop := range entity.Properties {
+				if prop == nil {
+					return nullEntryError(fmt.Sprintf("entityInstances[%d].fieldInstances", i), j)
+				}
+			}
+			layer.Entities[i] = entity
+		}
+	}
+
+	layer.setIntGridCSV(aux.IntGridCSV)
+
+	return nil
+
+}
+
+// tileData is a Tile as it's stored in LDtk JSON; fixed-size arrays are used so that decoding a Tile doesn't allocate.
+type tileData struct {
+	Position [2]int   `json:"px"`
+	Src      [2]int   `json:"src"`
+	Flip     TileFlip `json:"f"`
+	ID       int      `json:"t"`
+	Data     [2]int   `json:"d"` // LDtk's internal data; for auto-layer tiles, the UID of the rule that placed the tile and the ID of its cell
+}
+
+// newTiles creates Tiles from the decoded tile data, in the same order. The Tiles (and their positions) are stored in flat, contiguous slices,
+// so that each Tile doesn't have to be allocated and tracked individually by the garbage collector. autoTiles indicates whether the tiles were
+// placed by auto-layer rules.
+func newTiles(data []tileData, autoTiles bool) []*Tile {
+
+	if data == nil {
+		return nil
+	}
+
+	backing := make([]Tile, len(data))
+	ints := make([]int, len(data)*4)
+	tiles := make([]*Tile, len(data))
+
+	for i, d := range data {
+
+		tile := &backing[i]
+		tile.Flip = d.Flip
+		tile.ID = d.ID
+
+		if autoTiles {
+			tile.RuleUID = d.Data[0]
+		}
+
+		tile.Position = ints[i*4 : i*4+2 : i*4+2]
+		copy(tile.Position, d.Position[:])
+
+		tile.Src = ints[i*4+2 : i*4+4 : i*4+4]
+		copy(tile.Src, d.Src[:])
+
+		tiles[i] = tile
+
+	}
+
+	return tiles
+
+}
+
+// UnmarshalJSON decodes a Property from LDtk JSON, keeping the precision of integers too large for a float64 to hold exactly (see decodeValue).
+func (p *Property) UnmarshalJSON(data []byte) error {
+
+	aux := propertyJSON{propertyAlias: (*propertyAlias)(p)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	p.Value = nil
+
+	if len(aux.Value) > 0 {
+		value, err := decodeValue(aux.Value)
+		if err != nil {
+			return err
+		}
+		p.Value = value
+	}
+
+	return nil
+
+}
+
+// UnmarshalJSON decodes a table of contents entry from LDtk JSON. Projects exported with the ExportOldTableOfContentData flag (or from LDtk 1.4.0)
+// only list the IIDs of each instance, so in that case, the instances are created from those IIDs.
+func (entry *TOCEntry) UnmarshalJSON(data []byte) error {
+
+	aux := tocEntryJSON{tocEntryAlias: (*tocEntryAlias)(entry)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	for i, instance := range entry.Instances {
+		if instance == nil {
+			return nullEntryError("instancesData", i)
+		}
+	}
+
+	if entry.Instances == nil {
+		for _, ref := range aux.LegacyInstances {
+			entry.Instances = append(entry.Instances, &TOCInstance{IIDs: ref})
+		}
+	}
+
+	return nil
+
+}
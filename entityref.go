@@ -0,0 +1,65 @@
+package ldtkgo
+
+// ResolvedEntityRef represents a reference from an EntityRef Property to an Entity, along with the Entity, Layer, and Level it points to.
+// If the reference couldn't be resolved (i.e. the Entity lives in an external level that wasn't loaded), the pointers are nil.
+type ResolvedEntityRef struct {
+	EntityReference
+	Entity *Entity
+	Layer  *Layer
+	Level  *Level
+}
+
+// Resolved returns if the reference points to an Entity that was found in the Project.
+func (ref ResolvedEntityRef) Resolved() bool {
+	return ref.Entity != nil
+}
+
+// AsEntityRefs returns the Entities referenced by an EntityRef or Array<EntityRef> Property (i.e. a "patrol path" made of several points).
+// A single EntityRef Property returns a slice of one element, while a null Property returns an empty slice. Null elements within an array
+// are skipped. References are resolved when the Project is loaded; unresolvable references are still returned, but with nil pointers.
+func (p *Property) AsEntityRefs() []ResolvedEntityRef {
+	if p.entityRefs == nil {
+		p.resolveEntityRefs()
+	}
+	return p.entityRefs
+}
+
+func (p *Property) resolveEntityRefs() {
+
+	p.entityRefs = []ResolvedEntityRef{}
+
+	if p.LDtkType() != PropertyTypeEntityRef {
+		return
+	}
+
+	values := []interface{}{p.Value}
+	if array, ok := p.Value.([]interface{}); ok {
+		values = array
+	}
+
+	for _, v := range values {
+
+		ref, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		resolved := ResolvedEntityRef{}
+		resolved.EntityIID, _ = ref["entityIid"].(string)
+		resolved.LayerIID, _ = ref["layerIid"].(string)
+		resolved.LevelIID, _ = ref["levelIid"].(string)
+		resolved.WorldIID, _ = ref["worldIid"].(string)
+
+		if p.project != nil {
+			if resolved.Level = p.project.LevelByIID(resolved.LevelIID); resolved.Level != nil {
+				if resolved.Layer = resolved.Level.LayerByIID(resolved.LayerIID); resolved.Layer != nil {
+					resolved.Entity = resolved.Layer.EntityByIID(resolved.EntityIID)
+				}
+			}
+		}
+
+		p.entityRefs = append(p.entityRefs, resolved)
+
+	}
+
+}
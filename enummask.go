@@ -0,0 +1,164 @@
+package ldtkgo
+
+import (
+	"fmt"
+	"sort"
+)
+
+// maxEnumMaskValues is the number of distinct enum values an EnumMask can hold.
+const maxEnumMaskValues = 64
+
+// EnumMask is a set of enum values represented as a bitmask, for checking the enums tiles are tagged with without comparing strings (i.e. in
+// collision checks made every frame). EnumMasks are created using an EnumIndex; masks from different EnumIndexes can't be compared.
+type EnumMask uint64
+
+// ContainsAny returns true if the EnumMask contains any of the values in the other EnumMask given.
+func (mask EnumMask) ContainsAny(other EnumMask) bool {
+	return mask&other != 0
+}
+
+// ContainsAll returns true if the EnumMask contains all of the values in the other EnumMask given.
+func (mask EnumMask) ContainsAll(other EnumMask) bool {
+	return mask&other == other
+}
+
+// Intersect returns an EnumMask of the values that are in both the EnumMask and the other EnumMask given.
+func (mask EnumMask) Intersect(other EnumMask) EnumMask {
+	return mask & other
+}
+
+// Union returns an EnumMask of the values that are in either the EnumMask or the other EnumMask given.
+func (mask EnumMask) Union(other EnumMask) EnumMask {
+	return mask | other
+}
+
+// IsEmpty returns true if the EnumMask contains no values.
+func (mask EnumMask) IsEmpty() bool {
+	return mask == 0
+}
+
+// EnumIndex assigns a bit to each enum value the Project's Tilesets tag tiles with, and holds the EnumMask of each tagged tile, so that tiles'
+// enums can be checked using EnumMasks:
+//
+//	index, err := ldtkgo.NewEnumIndex(project)
+//	solid := index.Mask("Solid", "OneWay")
+//	...
+//	if index.TileMask(tile).ContainsAny(solid) { ... }
+//
+// An EnumIndex is built from the Project as it is when NewEnumIndex is called, so it has to be built again if the Project's Tilesets' enums change.
+type EnumIndex struct {
+	values []string
+	bits   map[string]EnumMask
+	tiles  map[int][]EnumMask // Key: Tileset UID, Value: the EnumMask of each tile, by tile ID
+}
+
+// NewEnumIndex creates an EnumIndex from the enum values the Project's Tilesets tag tiles with. Values are assigned bits in alphabetical order,
+// so the same Project always produces the same EnumMasks. An error is returned if the Tilesets use more than 64 distinct enum values.
+func NewEnumIndex(project *Project) (*EnumIndex, error) {
+
+	index := &EnumIndex{
+		bits:  map[string]EnumMask{},
+		tiles: map[int][]EnumMask{},
+	}
+
+	for _, tileset := range project.Tilesets {
+		for _, enums := range tileset.Enums {
+			for _, enum := range enums {
+				if _, exists := index.bits[enum]; !exists {
+					index.bits[enum] = 0
+					index.values = append(index.values, enum)
+				}
+			}
+		}
+	}
+
+	if len(index.values) > maxEnumMaskValues {
+		return nil, fmt.Errorf("tilesets use %d distinct enum values, but an EnumIndex can hold at most %d", len(index.values), maxEnumMaskValues)
+	}
+
+	sort.Strings(index.values)
+
+	for i, value := range index.values {
+		index.bits[value] = 1 << uint(i)
+	}
+
+	for _, tileset := range project.Tilesets {
+
+		maxID := -1
+
+		for id := range tileset.Enums {
+			if id > maxID {
+				maxID = id
+			}
+		}
+
+		if maxID < 0 {
+			continue
+		}
+
+		masks := make([]EnumMask, maxID+1)
+
+		for id, enums := range tileset.Enums {
+			if id >= 0 {
+				masks[id] = index.MaskOf(enums)
+			}
+		}
+
+		index.tiles[tileset.ID] = masks
+
+	}
+
+	return index, nil
+
+}
+
+// Mask returns an EnumMask of the enum values given. Values that no Tileset tags tiles with are ignored, as no tile can have them.
+func (index *EnumIndex) Mask(enums ...string) EnumMask {
+	mask := EnumMask(0)
+	for _, enum := range enums {
+		mask |= index.bits[enum]
+	}
+	return mask
+}
+
+// MaskOf returns an EnumMask of the enum values in the EnumSet given.
+func (index *EnumIndex) MaskOf(set EnumSet) EnumMask {
+	return index.Mask(set...)
+}
+
+// TileMask returns the EnumMask of the enums the Tile is tagged with in its Tileset, or an empty EnumMask if it has none.
+func (index *EnumIndex) TileMask(tile *Tile) EnumMask {
+
+	tileset := tile.Tileset()
+
+	if tileset == nil {
+		return 0
+	}
+
+	return index.TilesetMask(tileset, tile.ID)
+
+}
+
+// TilesetMask returns the EnumMask of the enums the tile of the ID given is tagged with in the Tileset given, or an empty EnumMask if it has none.
+func (index *EnumIndex) TilesetMask(tileset *Tileset, tileID int) EnumMask {
+
+	masks := index.tiles[tileset.ID]
+
+	if tileID < 0 || tileID >= len(masks) {
+		return 0
+	}
+
+	return masks[tileID]
+
+}
+
+// Values returns the enum values in the EnumMask given as an EnumSet, in alphabetical order.
+func (index *EnumIndex) Values(mask EnumMask) EnumSet {
+	set := EnumSet{}
+	for i, value := range index.values {
+		if mask&(1<<uint(i)) != 0 {
+			set = append(set, value)
+		}
+	}
+	return set
+}
@@ -0,0 +1,34 @@
+package ldtkgo
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors that can be checked for using errors.Is; the errors returned by LDtk-Go wrap these with more details (i.e. the identifier of the
+// Property or the IID of the Level that couldn't be found).
+var (
+	ErrLevelNotFound      = errors.New("level not found")             // A Level (i.e. the Level of an entity reference) isn't in the Project
+	ErrLayerNotFound      = errors.New("layer not found")             // A Layer (i.e. the Layer of an entity reference) isn't in its Level
+	ErrEntityNotFound     = errors.New("entity not found")            // An Entity (i.e. the target of an entity reference) isn't in its Layer
+	ErrTilesetNotFound    = errors.New("tileset not found")           // A Tileset (or its image, for renderers) couldn't be found
+	ErrNullValue          = errors.New("value is null")               // A Property's value is null, so it can't be returned as the type asked for
+	ErrUnsupportedVersion = errors.New("unsupported version of LDtk") // The project was saved with a version of LDtk too old to be loaded
+)
+
+// FieldTypeError is returned when the value of a Property (or an element of an array Property) isn't of the type it was asked for as, i.e.
+// when calling IntValue on a String Property.
+type FieldTypeError struct {
+	Field string // The identifier of the Property
+	Type  string // The LDtk type of the Property (i.e. "Array<Int>")
+	Index int    // The index of the element that's the wrong type in an array Property, or -1 for the Property's value as a whole
+	Want  string // A description of the type that was asked for (i.e. "a number")
+	Got   string // The Go type of the value (i.e. "string")
+}
+
+func (err *FieldTypeError) Error() string {
+	if err.Index >= 0 {
+		return fmt.Sprintf("property %s (%s): element %d is %s, not %s", err.Field, err.Type, err.Index, err.Got, err.Want)
+	}
+	return fmt.Sprintf("property %s (%s): value is %s, not %s", err.Field, err.Type, err.Got, err.Want)
+}
@@ -3,7 +3,6 @@ package main
 import (
 	"embed"
 	"fmt"
-	"image"
 	"io/fs"
 	"log"
 
@@ -128,12 +127,7 @@ func (g *Game) Draw(screen *ebiten.Image) {
 
 		for _, entity := range layer.Entities {
 
-			if entity.TileRect != nil {
-
-				tileset := g.Renderer.Tilesets[entity.TileRect.Tileset.Path]
-
-				tileRect := entity.TileRect
-				tile := tileset.SubImage(image.Rect(tileRect.X, tileRect.Y, tileRect.X+tileRect.W, tileRect.Y+tileRect.H)).(*ebiten.Image)
+			if tile := g.Renderer.EntityTileImage(entity); tile != nil {
 
 				opt := &ebiten.DrawImageOptions{}
 				opt.GeoM.Translate(float64(entity.Position[0]), float64(entity.Position[1]))
@@ -0,0 +1,303 @@
+package ldtkgo
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// FieldDefinition represents the definition of a custom field of Entities or Levels, as created in LDtk; each Property on an Entity or Level
+// is an instance of one.
+type FieldDefinition struct {
+	Identifier           string      `json:"identifier"`           // Name of the field
+	UID                  int         `json:"uid"`                  // Unique ID of the field
+	Type                 string      `json:"__type"`               // Type of the field, in the same form as Property.Type (i.e. "Int", "Array<String>", or "LocalEnum.Goodness")
+	Doc                  string      `json:"doc"`                  // Documentation of the field written in LDtk
+	IsArray              bool        `json:"isArray"`              // Whether the field holds an array of values
+	CanBeNull            bool        `json:"canBeNull"`            // Whether the field's value can be null
+	DefaultValue         interface{} `json:"-"`                    // Default value of the field set in LDtk, in the same form as Property.Value (for Arrays, this is the default of each element); nil if the field has no default
+	ArrayMinLength       *int        `json:"arrayMinLength"`       // For Arrays, the minimum number of elements; nil if there's no minimum
+	ArrayMaxLength       *int        `json:"arrayMaxLength"`       // For Arrays, the maximum number of elements; nil if there's no maximum
+	Min                  *float64    `json:"min"`                  // For Int and Float fields, the minimum value; nil if there's no minimum
+	Max                  *float64    `json:"max"`                  // For Int and Float fields, the maximum value; nil if there's no maximum
+	Regex                string      `json:"regex"`                // For String fields, the JavaScript-style regular expression values must match (i.e. "/^[a-z]+$/g"); empty if there isn't one
+	AcceptFileTypes      []string    `json:"acceptFileTypes"`      // For FilePath fields, the file extensions that are accepted; empty if any file is accepted
+	AllowedRefs          string      `json:"allowedRefs"`          // For EntityRef fields, which Entities can be referred to ("Any", "OnlySame", "OnlyTags", or "OnlySpecificEntity")
+	AllowedRefTags       []string    `json:"allowedRefTags"`       // For EntityRef fields using "OnlyTags", the tags the referred Entities must have
+	AllowedRefsEntityUID *int        `json:"allowedRefsEntityUid"` // For EntityRef fields using "OnlySpecificEntity", the UID of the EntityDefinition the referred Entities must be instances of
+	AllowOutOfLevelRef   bool        `json:"allowOutOfLevelRef"`   // For EntityRef fields, whether Entities in other Levels can be referred to
+	AutoChainRef         bool        `json:"autoChainRef"`         // For EntityRef fields, whether new Entities are automatically referred to when placed in LDtk
+	SymmetricalRef       bool        `json:"symmetricalRef"`       // For EntityRef fields, whether the referred Entity refers back to the Entity
+	TilesetUID           *int        `json:"tilesetUid"`           // For Tile fields, the UID of the Tileset tiles are picked from; nil if any Tileset can be used
+	TextLanguageMode     string      `json:"textLanguageMode"`     // For Multilines fields, the language used for syntax highlighting in LDtk (i.e. "LangLua"); empty if there isn't one
+	UseForSmartColor     bool        `json:"useForSmartColor"`     // Whether the field's (Color) value is used as the smart color of Entities
+	ExportToTOC          bool        `json:"exportToToc"`          // Whether the field's value is exported to the Project's TableOfContents
+	Searchable           bool        `json:"searchable"`           // Whether the field's value can be searched for in LDtk
+	EditorDisplayMode    string      `json:"editorDisplayMode"`    // How the field is displayed in LDtk (i.e. "Hidden", "ValueOnly", or "NameAndValue")
+	EditorDisplayPos     string      `json:"editorDisplayPos"`     // Where the field is displayed in LDtk ("Above", "Center", or "Beneath")
+	EditorDisplayScale   float64     `json:"editorDisplayScale"`   // Scale the field is displayed at in LDtk
+	EditorDisplayColor   string      `json:"editorDisplayColor"`   // Color the field is displayed in LDtk as a hex string; empty if the default is used
+	EditorLinkStyle      string      `json:"editorLinkStyle"`      // How EntityRefs and Points are drawn in LDtk (i.e. "StraightArrow", "CurvedArrow", or "ZigZag")
+	EditorAlwaysShow     bool        `json:"editorAlwaysShow"`     // Whether the field is displayed in LDtk even when the Entity isn't selected
+	EditorShowInWorld    bool        `json:"editorShowInWorld"`    // Whether the field is displayed in LDtk's world view
+	EditorCutLongValues  bool        `json:"editorCutLongValues"`  // Whether long values are cut off when displayed in LDtk
+	EditorTextPrefix     string      `json:"editorTextPrefix"`     // Text displayed before the field's value in LDtk
+	EditorTextSuffix     string      `json:"editorTextSuffix"`     // Text displayed after the field's value in LDtk
+}
+
+// Field reference constants for FieldDefinition.AllowedRefs.
+const (
+	AllowedRefsAny                = "Any"
+	AllowedRefsOnlySame           = "OnlySame"
+	AllowedRefsOnlyTags           = "OnlyTags"
+	AllowedRefsOnlySpecificEntity = "OnlySpecificEntity"
+)
+
+type fieldDefinitionAlias FieldDefinition
+
+// fieldDefinitionJSON is what a FieldDefinition is decoded into (see decode.go).
+type fieldDefinitionJSON struct {
+	*fieldDefinitionAlias
+	DefaultOverride *struct {
+		ID     string        `json:"id"`
+		Params []interface{} `json:"params"`
+	} `json:"defaultOverride"`
+}
+
+func (def *FieldDefinition) UnmarshalJSON(data []byte) error {
+
+	raw := fieldDefinitionJSON{fieldDefinitionAlias: (*fieldDefinitionAlias)(def)}
+
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	def.DefaultValue = nil
+
+	if raw.DefaultOverride == nil || len(raw.DefaultOverride.Params) == 0 {
+		return nil
+	}
+
+	// Only plain values are converted; other kinds of defaults (i.e. for Tiles) are left nil.
+	switch raw.DefaultOverride.ID {
+
+	case "V_Int", "V_Float", "V_Bool", "V_String":
+
+		value := raw.DefaultOverride.Params[0]
+
+		// Colors are stored as integers in the definition, but as hex strings in Properties.
+		if number, ok := value.(float64); ok && strings.TrimSuffix(strings.TrimPrefix(def.Type, "Array<"), ">") == "Color" {
+			value = fmt.Sprintf("#%06X", int(number))
+		}
+
+		def.DefaultValue = value
+
+	}
+
+	return nil
+
+}
+
+// defaultProperty returns a Property holding the field's default value, for fields that haven't been set; Arrays default to being empty.
+func (def *FieldDefinition) defaultProperty(project *Project) *Property {
+
+	prop := &Property{
+		Identifier: def.Identifier,
+		Type:       def.Type,
+		Value:      def.DefaultValue,
+		project:    project,
+	}
+
+	if def.IsArray {
+		prop.Value = []interface{}{}
+	}
+
+	return prop
+
+}
+
+// fieldDefinitionByIdentifier returns the FieldDefinition with the Identifier given out of those provided, or nil if one isn't found.
+func fieldDefinitionByIdentifier(defs []*FieldDefinition, identifier string) *FieldDefinition {
+	for _, def := range defs {
+		if def.Identifier == identifier {
+			return def
+		}
+	}
+	return nil
+}
+
+// propertyWithDefault returns the Property given, falling back to the default value of its field's definition (if the Project uses
+// Property defaults) when the Property wasn't set (i.e. it's missing or null).
+func propertyWithDefault(project *Project, prop *Property, defs []*FieldDefinition, identifier string) *Property {
+
+	if project == nil || !project.UsePropertyDefaults || (prop != nil && prop.Value != nil) {
+		return prop
+	}
+
+	def := fieldDefinitionByIdentifier(defs, identifier)
+
+	if def == nil || (def.DefaultValue == nil && !def.IsArray) {
+		return prop
+	}
+
+	return def.defaultProperty(project)
+
+}
+
+// Validate checks the value of the Property given (an instance of the field) against the constraints set on the field in LDtk: whether it can
+// be null, the number of elements of Arrays, the minimum and maximum of numbers, the regular expression of Strings, and the file types accepted
+// by FilePaths. An error describing the first constraint that isn't met is returned, or nil if the value is valid; a nil Property is treated as
+// a null value. This allows tools that modify Levels to enforce the same constraints as LDtk does.
+func (def *FieldDefinition) Validate(prop *Property) error {
+
+	var value interface{}
+
+	if prop != nil {
+		value = prop.Value
+	}
+
+	if !def.IsArray {
+		return def.validateValue(value, -1)
+	}
+
+	values, ok := value.([]interface{})
+
+	if !ok && value != nil {
+		return def.typeError(-1, value, "an array")
+	}
+
+	if def.ArrayMinLength != nil && len(values) < *def.ArrayMinLength {
+		return fmt.Errorf("field %s: array has %d elements, fewer than the minimum of %d", def.Identifier, len(values), *def.ArrayMinLength)
+	}
+
+	if def.ArrayMaxLength != nil && len(values) > *def.ArrayMaxLength {
+		return fmt.Errorf("field %s: array has %d elements, more than the maximum of %d", def.Identifier, len(values), *def.ArrayMaxLength)
+	}
+
+	for i, v := range values {
+		if err := def.validateValue(v, i); err != nil {
+			// FieldTypeErrors already hold the index of the element.
+			if _, ok := err.(*FieldTypeError); ok {
+				return err
+			}
+			return fmt.Errorf("%w (element %d)", err, i)
+		}
+	}
+
+	return nil
+
+}
+
+// validateValue checks a single (non-Array) value of the field against the field's constraints; index is the index of the value in an Array
+// field, or -1 otherwise.
+func (def *FieldDefinition) validateValue(value interface{}, index int) error {
+
+	if value == nil {
+		if !def.CanBeNull {
+			return fmt.Errorf("field %s: value can't be null", def.Identifier)
+		}
+		return nil
+	}
+
+	switch PropertyType(strings.TrimSuffix(strings.TrimPrefix(def.Type, "Array<"), ">")) {
+
+	case PropertyTypeInt, PropertyTypeFloat:
+
+		number, ok := value.(float64)
+
+		if !ok {
+			return def.typeError(index, value, "a number")
+		}
+
+		if def.Min != nil && number < *def.Min {
+			return fmt.Errorf("field %s: value %v is less than the minimum of %v", def.Identifier, number, *def.Min)
+		}
+
+		if def.Max != nil && number > *def.Max {
+			return fmt.Errorf("field %s: value %v is more than the maximum of %v", def.Identifier, number, *def.Max)
+		}
+
+	case PropertyTypeString:
+
+		if def.Regex == "" {
+			return nil
+		}
+
+		text, ok := value.(string)
+
+		if !ok {
+			return def.typeError(index, value, "a string")
+		}
+
+		pattern, err := compileJSRegexp(def.Regex)
+
+		if err != nil {
+			return fmt.Errorf("field %s: %w", def.Identifier, err)
+		}
+
+		if !pattern.MatchString(text) {
+			return fmt.Errorf("field %s: value %q doesn't match %s", def.Identifier, text, def.Regex)
+		}
+
+	case PropertyTypeFilePath:
+
+		if len(def.AcceptFileTypes) == 0 {
+			return nil
+		}
+
+		filePath, ok := value.(string)
+
+		if !ok {
+			return def.typeError(index, value, "a string")
+		}
+
+		ext := strings.TrimPrefix(path.Ext(filePath), ".")
+
+		for _, fileType := range def.AcceptFileTypes {
+			if strings.EqualFold(strings.TrimPrefix(fileType, "."), ext) {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("field %s: file %q isn't one of the accepted types %v", def.Identifier, filePath, def.AcceptFileTypes)
+
+	}
+
+	return nil
+
+}
+
+// typeError returns a *FieldTypeError for a value of the field (or an element of an Array field, if index isn't -1) that isn't of the type
+// expected.
+func (def *FieldDefinition) typeError(index int, value interface{}, expected string) error {
+	return &FieldTypeError{Field: def.Identifier, Type: def.Type, Index: index, Want: expected, Got: fmt.Sprintf("%T", value)}
+}
+
+// compileJSRegexp compiles a regular expression in the JavaScript form LDtk stores them in ("/pattern/flags"), supporting the i, m, and s
+// flags.
+func compileJSRegexp(expression string) (*regexp.Regexp, error) {
+
+	pattern := expression
+
+	if end := strings.LastIndex(expression, "/"); strings.HasPrefix(expression, "/") && end > 0 {
+
+		pattern = expression[1:end]
+
+		flags := ""
+		for _, flag := range expression[end+1:] {
+			if strings.ContainsRune("ims", flag) && !strings.ContainsRune(flags, flag) {
+				flags += string(flag)
+			}
+		}
+
+		if flags != "" {
+			pattern = "(?" + flags + ")" + pattern
+		}
+
+	}
+
+	return regexp.Compile(pattern)
+
+}
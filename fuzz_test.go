@@ -0,0 +1,59 @@
+package ldtkgo
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// FuzzRead checks that no input makes Read (or its low-memory and lazy decoders) panic. It's seeded with the projects under testdata, and
+// the corpus in testdata/fuzz/FuzzRead holds inputs that the decoder has to reject, like Layers with tiles but no grid size, and Entities
+// with partial positions:
+//
+//	go test -fuzz FuzzRead
+func FuzzRead(f *testing.F) {
+
+	paths, err := filepath.Glob(filepath.Join("testdata", "*", "*.ldtk"))
+
+	if err != nil {
+		f.Fatal(err)
+	}
+
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(data)
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+
+		for _, options := range [][]LoadOption{nil, {LowMemory()}, {LazyLevels()}} {
+
+			project, err := Read(data, options...)
+
+			if err != nil {
+				continue
+			}
+
+			// Walk what was decoded, so that Projects that load but can't be used are caught too.
+			for _, level := range project.Levels {
+				if err := level.EnsureLoaded(); err != nil {
+					continue
+				}
+				for _, layer := range level.Layers {
+					for _, tile := range layer.AllTiles() {
+						tile.SrcRect(layer)
+					}
+					for _, entity := range layer.Entities {
+						entity.WorldPosition()
+					}
+				}
+			}
+
+		}
+
+	})
+
+}
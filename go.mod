@@ -1,7 +1,3 @@
 module github.com/solarlune/ldtkgo
 
 go 1.16
-
-require (
-	github.com/tidwall/gjson v1.9.3
-)
@@ -0,0 +1,264 @@
+package ldtkgo
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// update rewrites the golden files instead of comparing against them: go test -run TestGolden -update
+var update = flag.Bool("update", false, "write the golden files instead of comparing against them")
+
+const (
+	goldenSuffix      = ".golden"     // The suffix of the file holding a project's decoded dump
+	goldenImageSuffix = ".golden.png" // The suffix of the file holding a project's minimap render, for projects that have one
+)
+
+// TestGolden loads every LDtk project under testdata (including one project saved by each supported version of LDtk, in testdata/versions),
+// and compares a dump of what was decoded against the golden file saved next to the project. Projects with a golden image also have their
+// minimap rendered and compared against it.
+func TestGolden(t *testing.T) {
+
+	paths := []string{}
+
+	err := filepath.WalkDir("testdata", func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() && entry.Name() == "fuzz" {
+			return filepath.SkipDir
+		}
+		if !entry.IsDir() && filepath.Ext(path) == ".ldtk" {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range paths {
+
+		path := path
+
+		t.Run(filepath.ToSlash(path), func(t *testing.T) {
+
+			dir, name := filepath.Split(path)
+			fileSystem := os.DirFS(filepath.Clean(dir))
+
+			project, err := Open(name, fileSystem)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			checkGolden(t, path+goldenSuffix, dumpProject(project))
+
+			if _, err := os.Stat(path + goldenImageSuffix); err == nil {
+				checkGoldenImage(t, path+goldenImageSuffix, project, fileSystem)
+			}
+
+		})
+
+	}
+
+}
+
+// checkGolden compares the dump given against the golden file at the path given, or writes the file if -update is set.
+func checkGolden(t *testing.T, goldenPath string, dump []byte) {
+
+	t.Helper()
+
+	if *update {
+		if err := os.WriteFile(goldenPath, dump, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	golden, err := os.ReadFile(goldenPath)
+
+	if err != nil {
+		t.Fatalf("no golden file (run with -update to create one): %v", err)
+	}
+
+	if !bytes.Equal(dump, golden) {
+
+		dumpLines := strings.Split(string(dump), "\n")
+		goldenLines := strings.Split(string(golden), "\n")
+
+		for i := 0; i < len(dumpLines) || i < len(goldenLines); i++ {
+			got, want := "", ""
+			if i < len(dumpLines) {
+				got = dumpLines[i]
+			}
+			if i < len(goldenLines) {
+				want = goldenLines[i]
+			}
+			if got != want {
+				t.Fatalf("decoded project differs from %s at line %d:\n got: %s\nwant: %s", goldenPath, i+1, got, want)
+			}
+		}
+
+	}
+
+}
+
+// checkGoldenImage renders the project's minimap and compares it against the golden image at the path given, or writes the image if -update
+// is set.
+func checkGoldenImage(t *testing.T, goldenPath string, project *Project, fileSystem fs.FS) {
+
+	t.Helper()
+
+	atlases, err := project.OpenTilesetAtlases(fileSystem)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	render := project.GenerateMinimap(1, &MinimapOptions{Atlases: atlases})
+
+	if *update {
+		buffer := &bytes.Buffer{}
+		if err := png.Encode(buffer, render); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(goldenPath, buffer.Bytes(), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	file, err := os.Open(goldenPath)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer file.Close()
+
+	golden, err := png.Decode(file)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if render.Bounds() != golden.Bounds() {
+		t.Fatalf("render is %v, but golden image is %v", render.Bounds(), golden.Bounds())
+	}
+
+	if differences := countDifferences(render, golden); differences > 0 {
+		t.Fatalf("%d pixels differ from golden image", differences)
+	}
+
+}
+
+// countDifferences returns the number of pixels that differ between the two images, which must have the same bounds.
+func countDifferences(a, b image.Image) int {
+
+	differences := 0
+	bounds := a.Bounds()
+
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			if color.NRGBAModel.Convert(a.At(x, y)) != color.NRGBAModel.Convert(b.At(x, y)) {
+				differences++
+			}
+		}
+	}
+
+	return differences
+
+}
+
+// dumpProject writes what was decoded from a project as text, one line per definition, Level, Layer, Tile, IntGrid cell, Entity, and
+// Property, so that differences between the dump and its golden file are easy to read.
+func dumpProject(project *Project) []byte {
+
+	out := &bytes.Buffer{}
+
+	fmt.Fprintf(out, "project version=%s layout=%s bg=%s\n", project.JSONVersion, project.WorldLayout, dumpColor(project.BGColor))
+
+	for _, tileset := range project.Tilesets {
+		fmt.Fprintf(out, "tileset %d %s path=%s size=%dx%d grid=%d spacing=%d padding=%d\n", tileset.ID, tileset.Identifier,
+			filepath.ToSlash(tileset.Path), tileset.Width, tileset.Height, tileset.GridSize, tileset.Spacing, tileset.Padding)
+	}
+
+	for _, def := range project.LayerDefinitions {
+		fmt.Fprintf(out, "layerdef %d %s type=%s grid=%d tileset=%d\n", def.UID, def.Identifier, def.Type, def.GridSize, def.TilesetUID)
+		for _, value := range def.IntGridValues {
+			fmt.Fprintf(out, "  intgridvalue %d %s color=%s\n", value.Value, value.Identifier, dumpColor(value.Color))
+		}
+	}
+
+	for _, def := range project.EntityDefinitions {
+		fmt.Fprintf(out, "entitydef %d %s size=%dx%d color=%s tags=%v tile=%s\n", def.UID, def.Identifier, def.Width, def.Height,
+			dumpColor(def.Color), def.Tags, dumpTileRect(def.TileRect))
+		for _, field := range def.FieldDefinitions {
+			fmt.Fprintf(out, "  fielddef %d %s type=%s\n", field.UID, field.Identifier, field.Type)
+		}
+	}
+
+	for _, level := range project.Levels {
+
+		fmt.Fprintf(out, "level %s iid=%s world=(%d,%d) size=%dx%d depth=%d bg=%s\n", level.Identifier, level.IID, level.WorldX, level.WorldY,
+			level.Width, level.Height, level.WorldDepth, dumpColor(level.BGColor))
+
+		dumpProperties(out, "  ", level.Properties)
+
+		for _, layer := range level.Layers {
+
+			fmt.Fprintf(out, "  layer %s iid=%s type=%s grid=%d cells=%dx%d offset=(%d,%d) tileset=%d visible=%t\n", layer.Identifier, layer.IID,
+				layer.Type, layer.GridSize, layer.CellWidth, layer.CellHeight, layer.OffsetX, layer.OffsetY, layer.TilesetUID, layer.Visible)
+
+			for _, tile := range layer.AllTiles() {
+				fmt.Fprintf(out, "    tile %d px=%v src=%v flip=%d\n", tile.ID, tile.Position, tile.Src, tile.Flip)
+			}
+
+			for _, integer := range layer.IntGrid {
+				fmt.Fprintf(out, "    int %d px=%v\n", integer.Value, integer.Position)
+			}
+
+			for _, entity := range layer.Entities {
+				fmt.Fprintf(out, "    entity %s iid=%s px=%v grid=%v size=%dx%d pivot=%v tags=%v tile=%s\n", entity.Identifier, entity.IID,
+					entity.Position, entity.GridPosition, entity.Width, entity.Height, entity.Pivot, entity.Tags, dumpTileRect(entity.TileRect))
+				dumpProperties(out, "      ", entity.Properties)
+			}
+
+		}
+
+	}
+
+	return out.Bytes()
+
+}
+
+func dumpProperties(out *bytes.Buffer, indent string, properties []*Property) {
+	for _, p := range properties {
+		fmt.Fprintf(out, "%sproperty %s type=%s value=%v\n", indent, p.Identifier, p.Type, p.Value)
+	}
+}
+
+func dumpColor(c color.Color) string {
+	if c == nil {
+		return "none"
+	}
+	rgba := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return fmt.Sprintf("#%02X%02X%02X%02X", rgba.R, rgba.G, rgba.B, rgba.A)
+}
+
+func dumpTileRect(rect *TileRect) string {
+	if rect == nil {
+		return "none"
+	}
+	return fmt.Sprintf("%d:(%d,%d,%d,%d)", rect.TilesetUID, rect.X, rect.Y, rect.W, rect.H)
+}
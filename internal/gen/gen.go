@@ -0,0 +1,226 @@
+package gen
+
+// gen generates large LDtk projects (with as many Levels, Tiles, IntGrid cells, and Entities as desired) for measuring the performance of
+// LDtk-Go, both in its benchmarks and using the ldtkgo-gen command. The generated projects are deterministic for a given seed, so
+// measurements can be compared between changes. The JSON is built directly (rather than using LDtk-Go's types and constants), so that
+// LDtk-Go's own tests can use it.
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+)
+
+const (
+	tilesetUID      = 1
+	entityLayerUID  = 2
+	tileLayerUID    = 3
+	intGridLayerUID = 4
+	enemyUID        = 5
+	healthUID       = 6
+	tilesetColumns  = 16
+)
+
+// Options control the size of a generated project.
+type Options struct {
+	Levels   int   // Number of Levels
+	Width    int   // Width of each Level in cells
+	Height   int   // Height of each Level in cells
+	Entities int   // Number of Entities in each Level
+	GridSize int   // Grid size of the layers in pixels
+	Seed     int64 // Random seed; the same seed always generates the same project
+}
+
+// DefaultOptions returns the Options of a large project: 1000 Levels of 64x36 cells, each with 20 Entities.
+func DefaultOptions() Options {
+	return Options{
+		Levels:   1000,
+		Width:    64,
+		Height:   36,
+		Entities: 20,
+		GridSize: 16,
+		Seed:     1,
+	}
+}
+
+// Project generates the JSON of a project with an Entity layer, a Tile layer, and an IntGrid layer in each Level, filled randomly.
+func Project(options Options) ([]byte, error) {
+
+	gen := &generator{
+		random:   rand.New(rand.NewSource(options.Seed)),
+		gridSize: options.GridSize,
+		width:    options.Width,
+		height:   options.Height,
+		entities: options.Entities,
+	}
+
+	return json.Marshal(gen.project(options.Levels))
+
+}
+
+type generator struct {
+	random   *rand.Rand
+	gridSize int
+	width    int // Width and height of each Level in cells
+	height   int
+	entities int // Number of Entities in each Level
+}
+
+type object map[string]interface{}
+
+func (gen *generator) project(levelCount int) object {
+
+	levels := make([]object, levelCount)
+
+	// Levels are laid out in a square-ish grid in the world.
+	columns := 1
+	for columns*columns < levelCount {
+		columns++
+	}
+
+	for i := range levels {
+		levels[i] = gen.level(i, (i%columns)*gen.width*gen.gridSize, (i/columns)*gen.height*gen.gridSize)
+	}
+
+	return object{
+		"jsonVersion":         "1.5.3",
+		"worldLayout":         "Free",
+		"worldGridWidth":      gen.width * gen.gridSize,
+		"worldGridHeight":     gen.height * gen.gridSize,
+		"defaultLevelBgColor": "#40465B",
+		"flags":               []string{},
+		"toc":                 []object{},
+		"defs": object{
+			"tilesets": []object{{
+				"identifier":   "Tiles",
+				"uid":          tilesetUID,
+				"relPath":      "tiles.png",
+				"pxWid":        tilesetColumns * gen.gridSize,
+				"pxHei":        tilesetColumns * gen.gridSize,
+				"tileGridSize": gen.gridSize,
+				"spacing":      0,
+				"padding":      0,
+				"enumTags":     []object{},
+				"customData":   []object{},
+			}},
+			"layers": []object{
+				{"identifier": "Entities", "uid": entityLayerUID, "type": "Entities", "gridSize": gen.gridSize},
+				{"identifier": "Tiles", "uid": tileLayerUID, "type": "Tiles", "gridSize": gen.gridSize, "tilesetDefUid": tilesetUID},
+				{"identifier": "Collision", "uid": intGridLayerUID, "type": "IntGrid", "gridSize": gen.gridSize, "intGridValues": []object{
+					{"value": 1, "identifier": "Solid", "color": "#FFFFFF"},
+					{"value": 2, "identifier": "Hazard", "color": "#FF0000"},
+				}},
+			},
+			"entities": []object{{
+				"identifier": "Enemy",
+				"uid":        enemyUID,
+				"width":      gen.gridSize,
+				"height":     gen.gridSize,
+				"color":      "#BE4A2F",
+				"tags":       []string{"enemy"},
+				"fieldDefs": []object{{
+					"identifier":      "Health",
+					"uid":             healthUID,
+					"__type":          "Int",
+					"defaultOverride": object{"id": "V_Int", "params": []int{3}},
+				}},
+			}},
+			"levelFields": []object{},
+		},
+		"levels": levels,
+	}
+
+}
+
+func (gen *generator) level(index, worldX, worldY int) object {
+
+	cells := gen.width * gen.height
+
+	intGrid := make([]int, cells)
+	tiles := []object{}
+
+	for i := range intGrid {
+
+		if gen.random.Intn(10) < 3 {
+			intGrid[i] = 1 + gen.random.Intn(2)
+		}
+
+		id := gen.random.Intn(tilesetColumns * tilesetColumns)
+		tiles = append(tiles, object{
+			"px":  []int{(i % gen.width) * gen.gridSize, (i / gen.width) * gen.gridSize},
+			"src": []int{(id % tilesetColumns) * gen.gridSize, (id / tilesetColumns) * gen.gridSize},
+			"f":   gen.random.Intn(4),
+			"t":   id,
+		})
+
+	}
+
+	entities := make([]object, gen.entities)
+
+	for i := range entities {
+		x, y := gen.random.Intn(gen.width), gen.random.Intn(gen.height)
+		entities[i] = object{
+			"__identifier": "Enemy",
+			"iid":          gen.iid(),
+			"defUid":       enemyUID,
+			"px":           []int{x * gen.gridSize, y * gen.gridSize},
+			"__grid":       []int{x, y},
+			"__pivot":      []float64{0, 0},
+			"__tags":       []string{"enemy"},
+			"width":        gen.gridSize,
+			"height":       gen.gridSize,
+			"fieldInstances": []object{
+				{"__identifier": "Health", "__type": "Int", "__value": 1 + gen.random.Intn(5), "defUid": healthUID},
+			},
+		}
+	}
+
+	layer := func(identifier, layerType string, defUID int) object {
+		return object{
+			"__identifier":     identifier,
+			"__type":           layerType,
+			"__cWid":           gen.width,
+			"__cHei":           gen.height,
+			"__gridSize":       gen.gridSize,
+			"__pxTotalOffsetX": 0,
+			"__pxTotalOffsetY": 0,
+			"iid":              gen.iid(),
+			"layerDefUid":      defUID,
+			"visible":          true,
+			"intGridCsv":       []int{},
+			"gridTiles":        []object{},
+			"autoLayerTiles":   []object{},
+			"entityInstances":  []object{},
+		}
+	}
+
+	entityLayer := layer("Entities", "Entities", entityLayerUID)
+	entityLayer["entityInstances"] = entities
+
+	tileLayer := layer("Tiles", "Tiles", tileLayerUID)
+	tileLayer["__tilesetDefUid"] = tilesetUID
+	tileLayer["gridTiles"] = tiles
+
+	intGridLayer := layer("Collision", "IntGrid", intGridLayerUID)
+	intGridLayer["intGridCsv"] = intGrid
+
+	return object{
+		"identifier":     fmt.Sprintf("Level_%d", index),
+		"iid":            gen.iid(),
+		"worldX":         worldX,
+		"worldY":         worldY,
+		"pxWid":          gen.width * gen.gridSize,
+		"pxHei":          gen.height * gen.gridSize,
+		"__bgColor":      "#40465B",
+		"fieldInstances": []object{},
+		"__neighbours":   []object{},
+		// Like in LDtk, the top-most layer comes first.
+		"layerInstances": []object{entityLayer, tileLayer, intGridLayer},
+	}
+
+}
+
+// iid returns a random IID in the same format as LDtk's (a version 4 UUID).
+func (gen *generator) iid() string {
+	return fmt.Sprintf("%08x-%04x-4%03x-%04x-%012x", gen.random.Uint32(), gen.random.Intn(1<<16), gen.random.Intn(1<<12), 0x8000|gen.random.Intn(1<<14), gen.random.Int63n(1<<48))
+}
@@ -0,0 +1,360 @@
+package ldtkgo
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// IntGridCSV returns the values of the Layer's IntGrid as rows of cells (indexed by [y][x]), with 0 for empty cells, in the same layout as
+// LDtk's own CSV export.
+func (layer *Layer) IntGridCSV() [][]int {
+
+	if layer.CellWidth <= 0 || layer.CellHeight <= 0 {
+		return [][]int{}
+	}
+
+	cells := layer.intGridCells()
+
+	rows := make([][]int, layer.CellHeight)
+
+	for y := range rows {
+		rows[y] = cells[y*layer.CellWidth : (y+1)*layer.CellWidth : (y+1)*layer.CellWidth]
+	}
+
+	return rows
+
+}
+
+// ExportIntGridCSV writes the values of the Layer's IntGrid to the io.Writer given as CSV, in the same format as LDtk's own CSV export
+// (one row of cells per line, with each value followed by a comma).
+func (layer *Layer) ExportIntGridCSV(writer io.Writer) error {
+
+	buffered := bufio.NewWriter(writer)
+
+	for _, row := range layer.IntGridCSV() {
+		for _, value := range row {
+			buffered.WriteString(strconv.Itoa(value))
+			buffered.WriteByte(',')
+		}
+		buffered.WriteByte('\n')
+	}
+
+	return buffered.Flush()
+
+}
+
+// SetIntGridCSV replaces the values of the Layer's IntGrid with the rows of cells given (indexed by [y][x], with 0 for empty cells), i.e. to
+// restore a snapshot taken using IntGridCSV. The rows must match the Layer's size in cells.
+func (layer *Layer) SetIntGridCSV(rows [][]int) error {
+
+	if len(rows) != layer.CellHeight {
+		return fmt.Errorf("IntGrid CSV has %d rows, but layer %s is %d cells high", len(rows), layer.Identifier, layer.CellHeight)
+	}
+
+	cells := make([]int, 0, layer.CellWidth*layer.CellHeight)
+
+	for y, row := range rows {
+		if len(row) != layer.CellWidth {
+			return fmt.Errorf("IntGrid CSV row %d has %d cells, but layer %s is %d cells wide", y, len(row), layer.Identifier, layer.CellWidth)
+		}
+		cells = append(cells, row...)
+	}
+
+	layer.setIntGridCSV(cells)
+
+	return nil
+
+}
+
+// ImportIntGridCSV reads CSV in the format written by ExportIntGridCSV (or LDtk's own CSV export) from the io.Reader given, and replaces the
+// values of the Layer's IntGrid with it. The CSV must match the Layer's size in cells.
+func (layer *Layer) ImportIntGridCSV(reader io.Reader) error {
+
+	rows := [][]int{}
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	for scanner.Scan() {
+
+		line := strings.TrimSpace(scanner.Text())
+
+		if line == "" {
+			continue
+		}
+
+		row := []int{}
+
+		for _, field := range strings.Split(line, ",") {
+
+			field = strings.TrimSpace(field)
+
+			if field == "" {
+				continue
+			}
+
+			value, err := strconv.Atoi(field)
+
+			if err != nil {
+				return fmt.Errorf("invalid IntGrid CSV value on row %d: %w", len(rows), err)
+			}
+
+			row = append(row, value)
+
+		}
+
+		rows = append(rows, row)
+
+	}
+
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	return layer.SetIntGridCSV(rows)
+
+}
+
+// setIntGridCSV replaces the Layer's IntGrid with the values given, which are in the same format as LDtk's intGridCsv (a flat slice of cells,
+// row by row, with 0 for empty cells).
+func (layer *Layer) setIntGridCSV(csv []int) {
+
+	layer.IntGrid = nil
+
+	if layer.CellWidth <= 0 {
+		return
+	}
+
+	// Allocate the Integers (and their positions) together rather than one at a time, as an IntGrid can be quite large.
+	count := 0
+	for _, value := range csv {
+		if value != 0 {
+			count++
+		}
+	}
+
+	integers := make([]Integer, 0, count)
+	positions := make([]int, 0, count*2)
+	layer.IntGrid = make([]*Integer, 0, count)
+
+	for i, value := range csv {
+
+		if value == 0 {
+			continue
+		}
+
+		integers = append(integers, Integer{Value: value, ID: i})
+		newI := &integers[len(integers)-1]
+
+		y := i / layer.CellWidth
+		x := i - y*layer.CellWidth
+		positions = append(positions, x*layer.GridSize, y*layer.GridSize)
+		newI.Position = positions[len(positions)-2 : len(positions) : len(positions)]
+
+		layer.IntGrid = append(layer.IntGrid, newI)
+
+	}
+
+}
+
+// intGridCells returns the values of the Layer's IntGrid as a flat slice of cells, row by row (indexed by y*CellWidth+x), with 0 for empty
+// cells; the slice is empty if the Layer has no size.
+func (layer *Layer) intGridCells() []int {
+
+	if layer.CellWidth <= 0 || layer.CellHeight <= 0 {
+		return []int{}
+	}
+
+	cells := make([]int, layer.CellWidth*layer.CellHeight)
+
+	for _, integer := range layer.IntGrid {
+		if integer.ID >= 0 && integer.ID < len(cells) {
+			cells[integer.ID] = integer.Value
+		}
+	}
+
+	return cells
+
+}
+
+// RaycastHit is the result of a ray cast against a Layer's IntGrid using Layer.Raycast.
+type RaycastHit struct {
+	GridX, GridY int     // Position of the cell that was hit on the Layer's grid
+	X, Y         float64 // Position at which the ray entered the cell that was hit, in pixels relative to the Layer
+	Value        int     // Value of the cell that was hit (0 for empty cells)
+	Distance     float64 // Distance from the start of the ray to the point it hit, in pixels
+}
+
+// Raycast casts a ray from (x0, y0) to (x1, y1) across the Layer's IntGrid, returning the first cell it passes through whose value is solid
+// according to the function given (i.e. func(value int) bool { return value == 1 }), or nil if there isn't one (i.e. there's line-of-sight
+// between the two points). The solid function is called with 0 for empty cells; cells outside of the Layer are never solid. The positions are
+// in pixels relative to the Layer, like Integer.Position; see Layer.ToGridPositionWorld for converting from world positions. If the starting
+// position is itself within a solid cell, that cell is returned, with the hit at the starting position.
+func (layer *Layer) Raycast(x0, y0, x1, y1 float64, solid func(value int) bool) *RaycastHit {
+
+	if layer.GridSize <= 0 || layer.CellWidth <= 0 || layer.CellHeight <= 0 {
+		return nil
+	}
+
+	cells := layer.intGridCells()
+	size := float64(layer.GridSize)
+
+	gridX := int(math.Floor(x0 / size))
+	gridY := int(math.Floor(y0 / size))
+
+	dx := x1 - x0
+	dy := y1 - y0
+	length := math.Hypot(dx, dy)
+
+	hitAt := func(t float64) *RaycastHit {
+		if gridX < 0 || gridY < 0 || gridX >= layer.CellWidth || gridY >= layer.CellHeight {
+			return nil
+		}
+		value := cells[gridY*layer.CellWidth+gridX]
+		if !solid(value) {
+			return nil
+		}
+		return &RaycastHit{
+			GridX:    gridX,
+			GridY:    gridY,
+			X:        x0 + dx*t,
+			Y:        y0 + dy*t,
+			Value:    value,
+			Distance: length * t,
+		}
+	}
+
+	if hit := hitAt(0); hit != nil {
+		return hit
+	}
+
+	// The ray is stepped through the grid one cell at a time using a digital differential analyzer (DDA), where t is how far along the ray
+	// (from 0 to 1) each cell boundary is crossed.
+	stepX, stepY := 0, 0
+	nextX, nextY := math.Inf(1), math.Inf(1)
+	deltaX, deltaY := math.Inf(1), math.Inf(1)
+
+	if dx > 0 {
+		stepX = 1
+		nextX = (float64(gridX+1)*size - x0) / dx
+		deltaX = size / dx
+	} else if dx < 0 {
+		stepX = -1
+		nextX = (float64(gridX)*size - x0) / dx
+		deltaX = -size / dx
+	}
+
+	if dy > 0 {
+		stepY = 1
+		nextY = (float64(gridY+1)*size - y0) / dy
+		deltaY = size / dy
+	} else if dy < 0 {
+		stepY = -1
+		nextY = (float64(gridY)*size - y0) / dy
+		deltaY = -size / dy
+	}
+
+	for {
+
+		var t float64
+
+		if nextX < nextY {
+			t = nextX
+			gridX += stepX
+			nextX += deltaX
+		} else {
+			t = nextY
+			gridY += stepY
+			nextY += deltaY
+		}
+
+		if t > 1 || math.IsInf(t, 1) {
+			return nil
+		}
+
+		if hit := hitAt(t); hit != nil {
+			return hit
+		}
+
+	}
+
+}
+
+// Cell is the position of a cell on a Layer's grid.
+type Cell struct {
+	X, Y int
+}
+
+// FloodFill returns the cells connected to the cell at the grid position given that have the same IntGrid value (including 0, for empty
+// cells), in the order they're reached from the starting cell; cells are connected to the ones above, below, and to either side of them. This
+// can be used to find reachable areas or bodies of water, for example. If the position is outside of the Layer, an empty slice is returned.
+func (layer *Layer) FloodFill(gridX, gridY int) []Cell {
+
+	if gridX < 0 || gridY < 0 || gridX >= layer.CellWidth || gridY >= layer.CellHeight {
+		return []Cell{}
+	}
+
+	cells := layer.intGridCells()
+	visited := make([]bool, len(cells))
+
+	return layer.floodFill(cells, visited, gridX, gridY)
+
+}
+
+// Regions returns each group of connected cells in the Layer's IntGrid that have the value given (see FloodFill), i.e. to identify separate
+// rooms or bodies of water. The regions are ordered by their first cell, row by row.
+func (layer *Layer) Regions(value int) [][]Cell {
+
+	regions := [][]Cell{}
+
+	cells := layer.intGridCells()
+	visited := make([]bool, len(cells))
+
+	for i, v := range cells {
+		if v == value && !visited[i] {
+			regions = append(regions, layer.floodFill(cells, visited, i%layer.CellWidth, i/layer.CellWidth))
+		}
+	}
+
+	return regions
+
+}
+
+// floodFill returns the cells connected to the cell at the grid position given that have the same value, marking them as visited. The cells
+// are visited breadth-first, so the returned slice doubles as the queue.
+func (layer *Layer) floodFill(cells []int, visited []bool, gridX, gridY int) []Cell {
+
+	width, height := layer.CellWidth, layer.CellHeight
+	value := cells[gridY*width+gridX]
+
+	visited[gridY*width+gridX] = true
+	region := []Cell{{gridX, gridY}}
+
+	for i := 0; i < len(region); i++ {
+
+		cell := region[i]
+
+		for _, neighbor := range [4]Cell{{cell.X, cell.Y - 1}, {cell.X - 1, cell.Y}, {cell.X + 1, cell.Y}, {cell.X, cell.Y + 1}} {
+
+			if neighbor.X < 0 || neighbor.Y < 0 || neighbor.X >= width || neighbor.Y >= height {
+				continue
+			}
+
+			index := neighbor.Y*width + neighbor.X
+
+			if !visited[index] && cells[index] == value {
+				visited[index] = true
+				region = append(region, neighbor)
+			}
+
+		}
+
+	}
+
+	return region
+
+}
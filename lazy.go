@@ -0,0 +1,133 @@
+package ldtkgo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+)
+
+// LazyLevels returns a LoadOption that only decodes the Levels' own data (their identifiers, positions, sizes, Properties, and so on) when the
+// Project is loaded, leaving their Layers to be decoded when they're first needed, so open-world games don't pay the time and memory to decode
+// Levels the player never visits. A Level's Layers are nil until Level.EnsureLoaded is called, and can be freed again using Level.Unload. The
+// JSON of each Level is kept in memory to decode it from; Levels saved in external level files are read from the Project's file system
+// instead (so they can only be loaded if the Project was loaded using Open or OpenContext). This takes precedence over streaming the JSON
+// (see LowMemory and OnStage).
+func LazyLevels() LoadOption {
+	return func(config *loadConfig) {
+		config.lazyLevels = true
+	}
+}
+
+// unmarshalLazy decodes the project's JSON like UnmarshalJSON, but skips the layer instances of its Levels, keeping the JSON of each Level
+// to decode them from later using Level.EnsureLoaded.
+func (project *Project) unmarshalLazy(data []byte) error {
+
+	return project.unmarshalLevelsWith(data, func(levelData []json.RawMessage) ([]*Level, error) {
+
+		levels := make([]*Level, len(levelData))
+
+		for i, data := range levelData {
+
+			if string(bytes.TrimSpace(data)) == "null" {
+				return nil, nullEntryError("levels", i)
+			}
+
+			level := &Level{lazy: true, pending: true}
+
+			if err := level.decode(data, true); err != nil {
+				return nil, err
+			}
+
+			// Levels stored in external level files are read from them instead.
+			if level.ExternalPath == "" {
+				level.lazyData = data
+			}
+
+			levels[i] = level
+
+		}
+
+		return levels, nil
+
+	})
+
+}
+
+// IsLoaded returns if the Level's Layers have been decoded. This is only false for Levels that haven't been loaded yet (or have been unloaded)
+// when the LazyLevels option is used, and for Levels that have been released (see Level.Release).
+func (level *Level) IsLoaded() bool {
+	return !level.pending
+}
+
+// EnsureLoaded decodes the Level's Layers if they haven't been yet (see the LazyLevels option), resolving entity references to and from the
+// Level's Entities afterwards. If the Level is already loaded, EnsureLoaded does nothing.
+func (level *Level) EnsureLoaded() error {
+
+	if !level.pending {
+		return nil
+	}
+
+	project := level.Project
+
+	if project == nil {
+		return fmt.Errorf("level %s: can't be loaded, as it isn't part of a Project", level.Identifier)
+	}
+
+	if level.lazyData != nil {
+
+		loaded := &Level{}
+
+		if err := json.Unmarshal(level.lazyData, loaded); err != nil {
+			return fmt.Errorf("level %s: %w", level.Identifier, err)
+		}
+
+		level.Layers = loaded.Layers
+		project.setupLoadedLevel(level)
+
+	} else {
+
+		if level.ExternalPath == "" {
+			return fmt.Errorf("level %s: can't be loaded again, as it was released and wasn't loaded lazily", level.Identifier)
+		}
+
+		if project.fileSystem == nil {
+			return fmt.Errorf("level %s: external level file can't be loaded, as the Project wasn't loaded from a file system", level.Identifier)
+		}
+
+		levelPath := path.Join(project.dir, filepath.ToSlash(level.ExternalPath))
+
+		data, err := fs.ReadFile(project.fileSystem, levelPath)
+
+		if err != nil {
+			return err
+		}
+
+		if err := project.readExternalLevel(level, data); err != nil {
+			return fmt.Errorf("%s: %w", levelPath, err)
+		}
+
+	}
+
+	level.pending = false
+
+	project.resolveReferences()
+
+	return nil
+
+}
+
+// Unload frees the Level's Layers if it was loaded lazily (see the LazyLevels option), so that they can be loaded again using EnsureLoaded
+// when they're next needed; this is the same as Level.Release. Levels that weren't loaded lazily are left as they are, as they can't be
+// loaded again.
+func (level *Level) Unload() {
+
+	if !level.lazy || level.pending {
+		return
+	}
+
+	level.Release()
+
+}
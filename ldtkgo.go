@@ -4,16 +4,22 @@
 package ldtkgo
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"image"
 	"image/color"
 	"io"
 	"io/fs"
+	"math"
+	"path"
 	"path/filepath"
-	"strconv"
-
-	"github.com/tidwall/gjson"
+	"reflect"
+	"sort"
+	"strings"
 )
 
 // LayerType constants indicating a Layer's type.
@@ -24,6 +30,25 @@ const (
 	LayerTypeEntity   = "Entities"
 )
 
+// EntityRenderMode constants indicating how an Entity is drawn in LDtk.
+const (
+	EntityRenderModeRectangle = "Rectangle"
+	EntityRenderModeEllipse   = "Ellipse"
+	EntityRenderModeTile      = "Tile"
+	EntityRenderModeCross     = "Cross"
+)
+
+// EntityTileRenderMode constants indicating how an Entity's tile is drawn in LDtk.
+const (
+	EntityTileRenderModeCover             = "Cover"
+	EntityTileRenderModeFitInside         = "FitInside"
+	EntityTileRenderModeRepeat            = "Repeat"
+	EntityTileRenderModeStretch           = "Stretch"
+	EntityTileRenderModeFullSizeCropped   = "FullSizeCropped"
+	EntityTileRenderModeFullSizeUncropped = "FullSizeUncropped"
+	EntityTileRenderModeNineSlice         = "NineSlice"
+)
+
 // WorldLayout constants indicating direction or layout system for Worlds.
 const (
 	WorldLayoutHorizontal = "LinearHorizontal"
@@ -32,22 +57,50 @@ const (
 	WorldLayoutGridVania  = "GridVania"
 )
 
+// ImageExportMode constants indicating which PNG images LDtk exports when saving a Project (see Project.ImageExportMode).
+const (
+	ImageExportModeNone             = "None"
+	ImageExportModeOneImagePerLayer = "OneImagePerLayer"
+	ImageExportModeOneImagePerLevel = "OneImagePerLevel"
+	ImageExportModeLayersAndLevels  = "LayersAndLevels"
+)
+
+// ProjectFlag constants indicating options enabled for a Project in LDtk (see Project.Flags).
+const (
+	ProjectFlagDiscardPreCsvIntGrid         = "DiscardPreCsvIntGrid"
+	ProjectFlagExportOldTableOfContentData  = "ExportOldTableOfContentData"
+	ProjectFlagExportPreCsvIntGridFormat    = "ExportPreCsvIntGridFormat"
+	ProjectFlagIgnoreBackupSuggest          = "IgnoreBackupSuggest"
+	ProjectFlagPrependIndexToLevelFileNames = "PrependIndexToLevelFileNames"
+	ProjectFlagMultiWorlds                  = "MultiWorlds"
+	ProjectFlagUseMultilinesType            = "UseMultilinesType"
+)
+
 // Property represents custom Properties created and customized on Entities.
 type Property struct {
 	Identifier string      `json:"__identifier"`
 	Type       string      `json:"__type"`  // The Type of the Property.
-	Value      interface{} `json:"__value"` // The value contained within the property.
+	Value      interface{} `json:"__value"` // The value contained within the property. Numbers are float64s, except for integers too large for a float64 to hold exactly, which are int64s.
 	project    *Project    `json:"-"`
+	entityRefs []ResolvedEntityRef
 }
 
 // AsInt returns a property's value as an int. Note that this function doesn't check to ensure the value is the specified type before returning it.
 func (p *Property) AsInt() int {
-	return int(p.AsFloat64())
+	return int(p.AsInt64())
 }
 
 // AsFloat64 returns a property's value as a float64. Note that this function doesn't check to ensure the value is the specified type before returning it.
 func (p *Property) AsFloat64() float64 {
-	return p.Value.(float64)
+	f, _ := numberValue(p.Value)
+	return f
+}
+
+// AsInt64 returns a property's value as an int64. Unlike AsInt, integers too large for a float64 to hold exactly (see Int64Value) are returned
+// without losing precision. Note that this function doesn't check to ensure the value is the specified type before returning it.
+func (p *Property) AsInt64() int64 {
+	i, _ := int64Value(p.Value)
+	return i
 }
 
 // AsString returns a property's value as a string. Can be used for strings, colors, enums, etc. Note that this function doesn't check to ensure the value is the specified type before returning it.
@@ -65,301 +118,1691 @@ func (p *Property) AsArray() []interface{} {
 	return p.Value.([]interface{})
 }
 
-// AsMap returns a property's value as a map of string to interface{} values. As an aside, the JSON deserialization process turns LDtk Points into Maps, where the key is "cx" or
-// "cy", and the value is the x and y position. Note that this function doesn't check to ensure the value is the specified type before returning it.
-func (p *Property) AsMap() map[string]interface{} {
-	return p.Value.(map[string]interface{})
+// AsStringArray returns a property's value as a slice of strings. Can be used for arrays of strings, multilines, colors, enums, file paths, etc.
+// Null elements are returned as blank strings. An error is returned if the value isn't an array or if an element isn't a string.
+func (p *Property) AsStringArray() ([]string, error) {
+	array, err := p.arrayValue()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]string, len(array))
+	for i, v := range array {
+		if v == nil {
+			continue
+		}
+		s, ok := v.(string)
+		if !ok {
+			return nil, p.elementTypeError(i, v, "a string")
+		}
+		out[i] = s
+	}
+	return out, nil
 }
 
-// AsEntityRef returns a proprety's value as an Entity reference.
-// Note that this function doesn't check to ensure the value is the specified type before returning it.
-func (p *Property) AsEntityRef() *Entity {
-	ref := p.AsMap()
-	return p.project.LevelByIID(ref["levelIid"].(string)).LayerByIID(ref["layerIid"].(string)).EntityByIID(ref["entityIid"].(string))
+// AsIntArray returns a property's value as a slice of ints. Null elements are returned as 0.
+// An error is returned if the value isn't an array or if an element isn't a number.
+func (p *Property) AsIntArray() ([]int, error) {
+	ints, err := p.AsInt64Array()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]int, len(ints))
+	for i, n := range ints {
+		out[i] = int(n)
+	}
+	return out, nil
 }
 
-func (p *Property) IsNull() bool {
-	return p.Value == nil
+// AsFloatArray returns a property's value as a slice of float64s. Null elements are returned as 0.
+// An error is returned if the value isn't an array or if an element isn't a number.
+func (p *Property) AsFloatArray() ([]float64, error) {
+	array, err := p.arrayValue()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]float64, len(array))
+	for i, v := range array {
+		if v == nil {
+			continue
+		}
+		f, ok := numberValue(v)
+		if !ok {
+			return nil, p.elementTypeError(i, v, "a number")
+		}
+		out[i] = f
+	}
+	return out, nil
 }
 
-// AsColor returns a property's value as a color.Color struct. Note that this function doesn't check to ensure the value is the specified type before returning it.
-func (p *Property) AsColor() color.Color {
-	color, _ := parseHexColorFast(p.AsString())
-	return color
+// AsInt64Array returns a property's value as a slice of int64s, without losing the precision of integers too large for a float64 to hold
+// exactly. Null elements are returned as 0. An error is returned if the value isn't an array or if an element isn't a number.
+func (p *Property) AsInt64Array() ([]int64, error) {
+	array, err := p.arrayValue()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]int64, len(array))
+	for i, v := range array {
+		if v == nil {
+			continue
+		}
+		n, ok := int64Value(v)
+		if !ok {
+			return nil, p.elementTypeError(i, v, "a number")
+		}
+		out[i] = n
+	}
+	return out, nil
 }
 
-// TileRect represents the rectangle from which an Entity tile is
-type TileRect struct {
-	X          int `json:"x`
-	Y          int `json:"y`
-	W          int `json:"w`
-	H          int `json:"h`
-	TilesetUID int `json:"tilesetUid"`
-	Tileset    *Tileset
+// AsBoolArray returns a property's value as a slice of bools. Null elements are returned as false.
+// An error is returned if the value isn't an array or if an element isn't a boolean.
+func (p *Property) AsBoolArray() ([]bool, error) {
+	array, err := p.arrayValue()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]bool, len(array))
+	for i, v := range array {
+		if v == nil {
+			continue
+		}
+		b, ok := v.(bool)
+		if !ok {
+			return nil, p.elementTypeError(i, v, "a boolean")
+		}
+		out[i] = b
+	}
+	return out, nil
 }
 
-// An Entity represents an Entitydefintion as defined in the entities.
-type EntityDefinition struct {
-	Identifier string    `json:"identifier"` // Name of the Entity
-	UID        int       `json:"uid"`        // IID of the Entity
-	Width      int       `json:"width"`      // Width  of the Entity in pixels
-	Height     int       `json:"height"`     // Height of the Entity in pixels
-	Tags       []string  `json:"tags"`       // Tags (categories) assigned to the Entity
-	TileRect   *TileRect `json:"tileRect"`
-	PivotX     float32   `json:"pivotX"`
-	PivotY     float32   `json:"pivotY"`
+// AsEnumValues returns the enum values set on an Enum or Array<Enum> property. A single Enum property returns a slice with one element, while
+// a null value returns an empty slice. Null elements within an array are skipped.
+func (p *Property) AsEnumValues() ([]string, error) {
+	switch value := p.Value.(type) {
+	case nil:
+		return []string{}, nil
+	case string:
+		return []string{value}, nil
+	case []interface{}:
+		out := make([]string, 0, len(value))
+		for i, v := range value {
+			if v == nil {
+				continue
+			}
+			s, ok := v.(string)
+			if !ok {
+				return nil, p.elementTypeError(i, v, "an enum value")
+			}
+			out = append(out, s)
+		}
+		return out, nil
+	}
+	return nil, p.typeError("an enum value")
 }
 
-// An Entity represents an Entity as placed in the LDtk level.
-type Entity struct {
-	Identifier string      `json:"__identifier"`   // Name of the Entity
-	IID        string      `json:"iid"`            // IID of the Entity
-	Position   []int       `json:"px"`             // Position of the Entity (x, y)
-	Width      int         `json:"width"`          // Width  of the Entity in pixels
-	Height     int         `json:"height"`         // Height of the Entity in pixels
-	Properties []*Property `json:"fieldInstances"` // The Properties defined on the Entity
-	Pivot      []float32   `json:"__pivot"`        // Pivot position of the Entity (a centered Pivot would be 0.5, 0.5)
-	Tags       []string    `json:"__tags"`         // Tags (categories) assigned to the Entity
-	TileRect   *TileRect   `json:"__tile"`
-	Data       interface{} `json:"-"` // Data allows you to attach key custom data to the entity post-parsing
-	level      *Level      `json:"-"`
-}
-
-// WorldX returns the X position of the Entity in world space, adding in the positioning of the Level.
-func (entity *Entity) WorldX() int {
-	return entity.Position[0] + entity.level.WorldX
+func (p *Property) arrayValue() ([]interface{}, error) {
+	if p.Value == nil {
+		return []interface{}{}, nil
+	}
+	array, ok := p.Value.([]interface{})
+	if !ok {
+		return nil, p.typeError("an array")
+	}
+	return array, nil
 }
 
-// WorldY returns the Y position of the Entity in world space, adding in the positioning of the Level.
-func (entity *Entity) WorldY() int {
-	return entity.Position[1] + entity.level.WorldY
+func (p *Property) elementTypeError(index int, value interface{}, expected string) error {
+	return &FieldTypeError{Field: p.Identifier, Type: p.Type, Index: index, Want: expected, Got: fmt.Sprintf("%T", value)}
 }
 
-// PropertyByIdentifier returns a Property by its Identifier string (name).
-func (entity *Entity) PropertyByIdentifier(id string) *Property {
+// AsMap returns a property's value as a map of string to interface{} values. As an aside, the JSON deserialization process turns LDtk Points into Maps, where the key is "cx" or
+// "cy", and the value is the x and y position. Note that this function doesn't check to ensure the value is the specified type before returning it.
+func (p *Property) AsMap() map[string]interface{} {
+	return p.Value.(map[string]interface{})
+}
 
-	for _, p := range entity.Properties {
-		if p.Identifier == id {
-			return p
+// AsEntityRef returns a proprety's value as an Entity reference. If the Property isn't an EntityRef, or the reference can't be resolved (i.e. the
+// Entity lives in an external level that wasn't loaded), nil is returned.
+func (p *Property) AsEntityRef() *Entity {
+	refs := p.AsEntityRefs()
+	if len(refs) == 0 {
+		return nil
+	}
+	return refs[0].Entity
+}
+
+// AsTileRect returns a Tile Property's value as a TileRect, with its Tileset filled in (if the Property belongs to a loaded Project). If the
+// Property isn't a Tile, or is null, nil is returned. For an Array<Tile> Property, the first Tile is returned.
+func (p *Property) AsTileRect() *TileRect {
+
+	if p.LDtkType() != PropertyTypeTile {
+		return nil
+	}
+
+	value := p.Value
+
+	if values, ok := value.([]interface{}); ok {
+		if len(values) == 0 {
+			return nil
 		}
+		value = values[0]
 	}
-	return nil
 
-}
+	return p.tileRect(value)
 
-// Integer indicates the value for an individual "Integer Object" on the IntGrid layer.
-type Integer struct {
-	Position []int `json:"-"`       // Not actually available from the LDtk file, but added in afterwards as a convenience; the position of the Integer in pixels.
-	Value    int   `json:"v"`       // The value of the Integer.
-	ID       int   `json:"coordID"` // The ID of the Integer on the IntGrid.
 }
 
-// EnumSet represents a set of Enums applied to tiles; this is just for convenience so you can see if a Tile contains an enum easily.
-type EnumSet []string
+// AsTileRects returns the values of an Array<Tile> Property as TileRects, in order, with their Tilesets filled in (if the Property belongs to
+// a loaded Project), i.e. the frames of a sprite animation authored in LDtk. Null elements are skipped. A single Tile Property returns a slice
+// with its Tile (or an empty slice if it's null), and nil is returned if the Property isn't a Tile.
+func (p *Property) AsTileRects() []TileRect {
 
-func (e EnumSet) Contains(enum string) bool {
-	for _, v := range e {
-		if v == enum {
-			return true
+	if p.LDtkType() != PropertyTypeTile {
+		return nil
+	}
+
+	values, ok := p.Value.([]interface{})
+
+	if !ok {
+		values = []interface{}{p.Value}
+	}
+
+	rects := make([]TileRect, 0, len(values))
+
+	for _, value := range values {
+		if rect := p.tileRect(value); rect != nil {
+			rects = append(rects, *rect)
 		}
 	}
-	return false
-}
 
-// Tile represents a graphical tile (whether automatic or manually placed).
-type Tile struct {
-	Position []int `json:"px"` // Position of the Tile in pixels (x, y)
-	Src      []int // The source position on the texture to draw this texture
-	Flip     byte  `json:"f"` // Flip bits - first bit is for X-flip, second is for Y. 0 = no flip, 1 = horizontal flip, 2 = vertical flip, 3 = both flipped
-	ID       int   `json:"t"` // The ID of the Tile (starting from 0).
-}
+	return rects
 
-// FlipX returns if the Tile is flipped horizontally.
-func (t *Tile) FlipX() bool {
-	return t.Flip&1 > 0
 }
 
-// FlipY returns if the Tile is flipped vertically.
-func (t *Tile) FlipY() bool {
-	return t.Flip&2 > 0
-}
+// tileRect converts the JSON object of a Tile value given into a TileRect, returning nil if it isn't one.
+func (p *Property) tileRect(value interface{}) *TileRect {
 
-// Layer represents a Layer, which can be of multiple types (Entity, AutoTile, Tile, or IntGrid).
-type Layer struct {
-	// The width and height of the layer
-	Identifier string   `json:"__identifier"`     // Identifier (name) of the Layer
-	IID        string   `json:"iid"`              // IID of the layer
-	GridSize   int      `json:"__gridsize"`       // Grid size of the Layer
-	OffsetX    int      `json:"__pxTotalOffsetX"` // The offset of the layer
-	OffsetY    int      `json:"__pxTotalOffsetY"`
-	CellWidth  int      `json:"__cWid"` // Overall width of the layer in cell count (i.e. a 160x80 level with 16x16 tiles would have a CellWidth and CellHeight of 10x5)
-	CellHeight int      `json:"__cHei"` // Overall height of the layer in cell count
-	Type       string   `json:"__type"` // Type of Layer. Can be compared using LayerType constants
-	Tileset    *Tileset `json:"-"`      // Reference to the Tileset used for this Layer (assuming the path is the same)
-	// TilesetPath string     `json:"__tilesetRelPath"` // Relative path to the tileset image; already is normalized using filepath.FromSlash().
-	TilesetUID int        `json:"__tilesetDefUid"` // The UID of the used tileset
-	IntGrid    []*Integer `json:"-"`
-	AutoTiles  []*Tile    `json:"autoLayerTiles"` // Automatically set if IntGrid has values
-	Tiles      []*Tile    `json:"gridTiles"`
-	Entities   []*Entity  `json:"entityInstances"`
-	Visible    bool       `json:"visible"` // Whether the layer is visible in LDtk
-	level      *Level     `json:"-"`
-}
+	object, ok := value.(map[string]interface{})
 
-// ForEachTile runs a callback for each tile in the Layer. This is to make it simpler to run a render loop regardless of if the Layer is composed of auto tiles or
-// manually placed tiles.
-func (layer *Layer) ForEachTile(function func(tile *Tile)) {
-	for _, tile := range layer.Tiles {
-		function(tile)
+	if !ok {
+		return nil
 	}
-	for _, tile := range layer.AutoTiles {
-		function(tile)
+
+	number := func(key string) int {
+		n, _ := object[key].(float64)
+		return int(n)
 	}
-}
 
-// EntityByIdentifier returns the Entity with the identifier (name) specified. If no Entity with the name is found, the function returns nil.
-func (layer *Layer) EntityByIdentifier(identifier string) *Entity {
-	for _, entity := range layer.Entities {
-		if entity.Identifier == identifier {
-			return entity
-		}
+	rect := &TileRect{
+		X:          number("x"),
+		Y:          number("y"),
+		W:          number("w"),
+		H:          number("h"),
+		TilesetUID: number("tilesetUid"),
 	}
-	return nil
+
+	if p.project != nil {
+		rect.Tileset = p.project.tilesetsByUID[rect.TilesetUID]
+	}
+
+	return rect
+
 }
 
-// EntityByIID returns the Entity with the IID specified. If no Entity with the name is found, the function returns nil.
-func (layer *Layer) EntityByIID(iid string) *Entity {
-	for _, entity := range layer.Entities {
-		if entity.IID == iid {
-			return entity
+// Equals returns if the Property's value is equal to the value given. Numeric values of any Go number type are compared against
+// the Property's numeric value, and nil matches a null Property. Arrays and maps are not comparable and always return false.
+func (p *Property) Equals(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return p.Value == nil
+	case int:
+		return p.Value == float64(v)
+	case int32:
+		return p.Value == float64(v)
+	case int64:
+		if i, ok := p.Value.(int64); ok {
+			return i == v
 		}
+		return p.Value == float64(v)
+	case float32:
+		return p.Value == float64(v)
+	case float64, string, bool:
+		return p.Value == v
 	}
-	return nil
+	return false
 }
 
-// ToGridPosition converts the specified position from a position in world space to a position on the Layer's grid. For example, if the layer were 128x128 and had 16x16 tiles, ToGridPosition(32, 16) would return (2, 1).
-func (layer *Layer) ToGridPosition(x, y int) (int, int) {
-	x /= layer.GridSize
-	y /= layer.GridSize
-	return x, y
+// IsNull returns if the property's value is null (unset in LDtk).
+func (p *Property) IsNull() bool {
+	return p.Value == nil
 }
 
-// FromGridPosition converts the specified position from a position on the Layer's grid to world space. For example, if the layer were 128x128 and had 16x16 tiles, FromGridPosition(3, 4) would return (48, 64).
-func (layer *Layer) FromGridPosition(x, y int) (int, int) {
-	x *= layer.GridSize
-	y *= layer.GridSize
-	return x, y
+// AsColor returns a property's value as a color.Color struct (a color.RGBA), ignoring any error; see AsColorRGBA.
+func (p *Property) AsColor() color.Color {
+	color, _ := p.AsColorRGBA()
+	return color
 }
 
-// TileAt returns the Tile at the specified grid (not world) X and Y position.
-// Note that this doesn't take into account the Layer's local Offset values (so a tile at 3, 4
-// on a layer with an offset of 64, 64 would still be found at 3, 4).
-func (layer *Layer) TileAt(x, y int) *Tile {
+// AsColorRGBA returns a property's value as a color.RGBA. Both hex strings (i.e. "#FF8800", as LDtk exports Color fields) and integers
+// (i.e. 0xFF8800, as LDtk stores colors elsewhere) are accepted. An error is returned if the value is null or isn't a valid color.
+func (p *Property) AsColorRGBA() (color.RGBA, error) {
+	if p.Value == nil {
+		return color.RGBA{}, p.nullError()
+	}
+	c, err := colorValue(p.Value)
+	if err == errNotColor {
+		return c, p.typeError("a color")
+	} else if err != nil {
+		return c, fmt.Errorf("property %s (%s): %w", p.Identifier, p.Type, err)
+	}
+	return c, nil
+}
 
-	for _, tile := range layer.Tiles {
-		cx, cy := layer.ToGridPosition(tile.Position[0], tile.Position[1])
-		if cx == x && cy == y {
-			return tile
+// AsColors returns the values of an Array<Color> property as color.RGBAs, accepting hex strings and integers like AsColorRGBA. Null or
+// invalid elements are returned as transparent black. A single Color property returns a slice with its color (or an empty slice if it's null).
+func (p *Property) AsColors() []color.RGBA {
+
+	values, ok := p.Value.([]interface{})
+
+	if !ok {
+		if p.Value == nil {
+			return []color.RGBA{}
 		}
+		values = []interface{}{p.Value}
 	}
 
-	return nil
+	colors := make([]color.RGBA, len(values))
+
+	for i, value := range values {
+		colors[i], _ = colorValue(value)
+	}
+
+	return colors
 
 }
 
-// AutoTileAt returns the AutoLayer Tile at the specified grid (not world) X and Y position.
-// Note that this doesn't take into account the Layer's local Offset values (so a tile at 3, 4 on a layer
-// with an offset of 64, 64 would still be found at 3, 4).
-func (layer *Layer) AutoTileAt(x, y int) *Tile {
+// colorValue converts the JSON value of a color given, either a hex string or an integer (0xRRGGBB), into a color.RGBA.
+func colorValue(value interface{}) (color.RGBA, error) {
 
-	for _, autoTile := range layer.AutoTiles {
-		cx, cy := layer.ToGridPosition(autoTile.Position[0], autoTile.Position[1])
-		if cx == x && cy == y {
-			return autoTile
+	if s, ok := value.(string); ok {
+		if s == "" {
+			return color.RGBA{}, errInvalidFormat
 		}
+		c, err := parseHexColorFast(s)
+		if err != nil {
+			return color.RGBA{}, err
+		}
+		return c, nil
 	}
 
-	return nil
+	if n, ok := int64Value(value); ok {
+		if n < 0 || n > 0xFFFFFF {
+			return color.RGBA{}, errInvalidFormat
+		}
+		return color.RGBA{R: uint8(n >> 16), G: uint8(n >> 8), B: uint8(n), A: 0xff}, nil
+	}
+
+	return color.RGBA{}, errNotColor
 
 }
 
-// IntegerAt returns the IntGrid Integer at the specified world X and Y position (rounded down to the Layer's grid).
-// Note that this doesn't take into account the Layer's local Offset values (so a tile at 3, 4 on a layer with an
-// offset of 64, 64 would still be found at 3, 4).
-func (layer *Layer) IntegerAt(x, y int) *Integer {
+// errNotColor is returned by colorValue for values that are neither strings nor numbers.
+var errNotColor = errors.New("not a color")
 
-	for _, integer := range layer.IntGrid {
-		cx, cy := layer.ToGridPosition(integer.Position[0], integer.Position[1])
-		if cx == x && cy == y {
-			return integer
-		}
+// PropertyType indicates the base type of a Property as defined in LDtk. For arrays, this is the type of the array's elements.
+type PropertyType string
+
+// PropertyType constants indicating a Property's base type.
+const (
+	PropertyTypeUnknown    PropertyType = ""
+	PropertyTypeInt        PropertyType = "Int"
+	PropertyTypeFloat      PropertyType = "Float"
+	PropertyTypeBool       PropertyType = "Bool"
+	PropertyTypeString     PropertyType = "String"
+	PropertyTypeMultilines PropertyType = "Multilines"
+	PropertyTypeColor      PropertyType = "Color"
+	PropertyTypePoint      PropertyType = "Point"
+	PropertyTypeFilePath   PropertyType = "FilePath"
+	PropertyTypeEntityRef  PropertyType = "EntityRef"
+	PropertyTypeTile       PropertyType = "Tile"
+	PropertyTypeEnum       PropertyType = "Enum"
+)
+
+// LDtkType returns the base type of the Property (i.e. PropertyTypeInt for both an "Int" and an "Array<Int>" Property).
+// Local and external enums both return PropertyTypeEnum; use EnumType() to get the name of the enum.
+func (p *Property) LDtkType() PropertyType {
+	t := p.elementType()
+	if strings.HasPrefix(t, "LocalEnum.") || strings.HasPrefix(t, "ExternEnum.") {
+		return PropertyTypeEnum
 	}
+	switch pt := PropertyType(t); pt {
+	case PropertyTypeInt, PropertyTypeFloat, PropertyTypeBool, PropertyTypeString, PropertyTypeMultilines, PropertyTypeColor,
+		PropertyTypePoint, PropertyTypeFilePath, PropertyTypeEntityRef, PropertyTypeTile:
+		return pt
+	}
+	return PropertyTypeUnknown
+}
 
-	return nil
+// IsArray returns if the Property is an array of values (i.e. its type is "Array<...>").
+func (p *Property) IsArray() bool {
+	return strings.HasPrefix(p.Type, "Array<") && strings.HasSuffix(p.Type, ">")
+}
 
+// EnumType returns the name of the enum used by an Enum Property (i.e. "Goodness" for a "LocalEnum.Goodness" Property). If the Property isn't
+// an enum, a blank string is returned.
+func (p *Property) EnumType() string {
+	t := p.elementType()
+	if i := strings.IndexByte(t, '.'); i >= 0 && p.LDtkType() == PropertyTypeEnum {
+		return t[i+1:]
+	}
+	return ""
 }
 
-// Index returns the index of the layer in the Level's layer stack.
-func (layer *Layer) Index() int {
-	for i, l := range layer.level.Layers {
-		if l == layer {
-			return i
-		}
+func (p *Property) elementType() string {
+	if p.IsArray() {
+		return p.Type[len("Array<") : len(p.Type)-1]
 	}
-	return -1
+	return p.Type
 }
 
-type Tileset struct {
-	Path       string `json:"relPath"` // Relative path to the tileset image; already is normalized using filepath.FromSlash().
-	ID         int    `json:"uid"`
-	GridSize   int    `json:"tileGridSize"`
-	Spacing    int
-	Padding    int
-	Width      int `json:"pxWid"`
-	Height     int `json:"pxHei"`
-	Identifier string
-	CustomData map[int]string  `json:"-"` // Key: tileID, Value: custom data string
-	Enums      map[int]EnumSet `json:"-"` // Key: enumValueID, Value: tileIDs (tile indices)
+// IntValue returns a property's value as an int, or an error if the value is null or isn't a number.
+func (p *Property) IntValue() (int, error) {
+	i, err := p.Int64Value()
+	return int(i), err
 }
 
-// CustomDataForTile returns the custom data defined for the tile of the ID given in the tileset. If no custom data is defined, a blank string is returned.
-func (t *Tileset) CustomDataForTile(tileID int) string {
-	if data, exists := t.CustomData[tileID]; exists {
-		return data
+// FloatValue returns a property's value as a float64, or an error if the value is null or isn't a number.
+func (p *Property) FloatValue() (float64, error) {
+	if p.Value == nil {
+		return 0, p.nullError()
 	}
-	return ""
+	f, ok := numberValue(p.Value)
+	if !ok {
+		return 0, p.typeError("a number")
+	}
+	return f, nil
 }
 
-// EnumsForTile returns the EnumSet defined for the tile of the ID given in the tileset. If no enums are defined, an empty EnumSet is returned.
-func (t *Tileset) EnumsForTile(tileID int) EnumSet {
-	if data, exists := t.Enums[tileID]; exists {
-		return data
+// Int64Value returns a property's value as an int64, or an error if the value is null or isn't a number. Integers too large for a float64 to
+// hold exactly (beyond 2^53, i.e. coordinates in very large worlds) are stored in the Property's Value as int64s rather than float64s, so
+// they're returned without losing precision.
+func (p *Property) Int64Value() (int64, error) {
+	if p.Value == nil {
+		return 0, p.nullError()
 	}
-	return EnumSet{}
+	i, ok := int64Value(p.Value)
+	if !ok {
+		return 0, p.typeError("a number")
+	}
+	return i, nil
 }
 
-// BGImage represents a Level's background image as definied withing LDtk (the filepath, the scale, etc).
-type BGImage struct {
-	Path     string
-	ScaleX   float64
-	ScaleY   float64
-	CropRect []float64
+// StringValue returns a property's value as a string, or an error if the value is null or isn't a string. Can be used for strings, colors, enums, etc.
+func (p *Property) StringValue() (string, error) {
+	if p.Value == nil {
+		return "", p.nullError()
+	}
+	s, ok := p.Value.(string)
+	if !ok {
+		return "", p.typeError("a string")
+	}
+	return s, nil
 }
 
-// Level represents a Level in an LDtk Project.
-type Level struct {
-	Identifier    string // Name of the Level (i.e. "Level0")
-	WorldX        int    // Position of the Level in the LDtk Project / world
+// BoolValue returns a property's value as a boolean, or an error if the value is null or isn't a boolean.
+func (p *Property) BoolValue() (bool, error) {
+	if p.Value == nil {
+		return false, p.nullError()
+	}
+	b, ok := p.Value.(bool)
+	if !ok {
+		return false, p.typeError("a boolean")
+	}
+	return b, nil
+}
+
+// ArrayValue returns a property's value as an array of interface{} values, or an error if the value is null or isn't an array.
+func (p *Property) ArrayValue() ([]interface{}, error) {
+	if p.Value == nil {
+		return nil, p.nullError()
+	}
+	return p.arrayValue()
+}
+
+// MapValue returns a property's value as a map of string to interface{} values (as is used for Points, EntityRefs, and Tiles), or an error
+// if the value is null or isn't a map.
+func (p *Property) MapValue() (map[string]interface{}, error) {
+	if p.Value == nil {
+		return nil, p.nullError()
+	}
+	m, ok := p.Value.(map[string]interface{})
+	if !ok {
+		return nil, p.typeError("an object")
+	}
+	return m, nil
+}
+
+// ColorValue returns a property's value as a color.Color, or an error if the value is null or isn't a valid color. Like AsColorRGBA, both
+// hex strings and integers are accepted.
+func (p *Property) ColorValue() (color.Color, error) {
+	c, err := p.AsColorRGBA()
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// EntityRefValue returns the Entity referenced by the property, or an error if the value is null, isn't an entity reference, or if the referenced
+// Level, Layer, or Entity can't be found in the Project.
+func (p *Property) EntityRefValue() (*Entity, error) {
+	ref, err := p.MapValue()
+	if err != nil {
+		return nil, err
+	}
+	levelIID, _ := ref["levelIid"].(string)
+	layerIID, _ := ref["layerIid"].(string)
+	entityIID, _ := ref["entityIid"].(string)
+	if p.project == nil {
+		return nil, fmt.Errorf("property %s (%s) isn't attached to a project", p.Identifier, p.Type)
+	}
+	level := p.project.LevelByIID(levelIID)
+	if level == nil {
+		return nil, fmt.Errorf("property %s (%s): referenced %w: %q", p.Identifier, p.Type, ErrLevelNotFound, levelIID)
+	}
+	layer := level.LayerByIID(layerIID)
+	if layer == nil {
+		return nil, fmt.Errorf("property %s (%s): referenced %w: %q", p.Identifier, p.Type, ErrLayerNotFound, layerIID)
+	}
+	entity := layer.EntityByIID(entityIID)
+	if entity == nil {
+		return nil, fmt.Errorf("property %s (%s): referenced %w: %q", p.Identifier, p.Type, ErrEntityNotFound, entityIID)
+	}
+	return entity, nil
+}
+
+func (p *Property) nullError() error {
+	return fmt.Errorf("property %s (%s): %w", p.Identifier, p.Type, ErrNullValue)
+}
+
+func (p *Property) typeError(expected string) error {
+	return &FieldTypeError{Field: p.Identifier, Type: p.Type, Index: -1, Want: expected, Got: fmt.Sprintf("%T", p.Value)}
+}
+
+// propertyInt returns the value of the Property given as an int, or the fallback given if the Property is nil, null, or isn't a number.
+func propertyInt(p *Property, fallback int) int {
+	if p == nil {
+		return fallback
+	}
+	if i, err := p.IntValue(); err == nil {
+		return i
+	}
+	return fallback
+}
+
+// propertyFloat returns the value of the Property given as a float64, or the fallback given if the Property is nil, null, or isn't a number.
+func propertyFloat(p *Property, fallback float64) float64 {
+	if p == nil {
+		return fallback
+	}
+	if f, err := p.FloatValue(); err == nil {
+		return f
+	}
+	return fallback
+}
+
+// propertyBool returns the value of the Property given as a bool, or the fallback given if the Property is nil, null, or isn't a boolean.
+func propertyBool(p *Property, fallback bool) bool {
+	if p == nil {
+		return fallback
+	}
+	if b, err := p.BoolValue(); err == nil {
+		return b
+	}
+	return fallback
+}
+
+// propertyString returns the value of the Property given as a string, or the fallback given if the Property is nil, null, or isn't a string.
+func propertyString(p *Property, fallback string) string {
+	if p == nil {
+		return fallback
+	}
+	if str, err := p.StringValue(); err == nil {
+		return str
+	}
+	return fallback
+}
+
+// propertyColor returns the value of the Property given as a color.Color, or the fallback given if the Property is nil, null, or isn't a color
+// (a hex string or an integer, as accepted by AsColorRGBA).
+func propertyColor(p *Property, fallback color.Color) color.Color {
+	if p == nil {
+		return fallback
+	}
+	if c, err := colorValue(p.Value); err == nil {
+		return c
+	}
+	return fallback
+}
+
+// TileRect represents the rectangle from which an Entity tile is
+type TileRect struct {
+	X          int `json:"x"`
+	Y          int `json:"y"`
+	W          int `json:"w"`
+	H          int `json:"h"`
+	TilesetUID int `json:"tilesetUid"`
+	Tileset    *Tileset
+}
+
+// An Entity represents an Entitydefintion as defined in the entities.
+type EntityDefinition struct {
+	Identifier       string             `json:"identifier"` // Name of the Entity
+	UID              int                `json:"uid"`        // IID of the Entity
+	Width            int                `json:"width"`      // Width  of the Entity in pixels
+	Height           int                `json:"height"`     // Height of the Entity in pixels
+	Tags             []string           `json:"tags"`       // Tags (categories) assigned to the Entity
+	TileRect         *TileRect          `json:"tileRect"`
+	PivotX           float32            `json:"pivotX"`
+	PivotY           float32            `json:"pivotY"`
+	ColorString      string             `json:"color"`            // Editor color of the Entity as a hex string
+	Color            color.Color        `json:"-"`                // Editor color of the Entity
+	RenderMode       string             `json:"renderMode"`       // How the Entity is drawn in LDtk; can be compared using EntityRenderMode constants
+	TileRenderMode   string             `json:"tileRenderMode"`   // How the Entity's tile is drawn in LDtk; can be compared using EntityTileRenderMode constants
+	FillOpacity      float64            `json:"fillOpacity"`      // Opacity of the Entity's fill when drawn as a shape
+	LineOpacity      float64            `json:"lineOpacity"`      // Opacity of the Entity's outline when drawn as a shape
+	TileOpacity      float64            `json:"tileOpacity"`      // Opacity of the Entity's tile
+	Hollow           bool               `json:"hollow"`           // Whether the Entity's shape is drawn without a fill
+	FieldDefinitions []*FieldDefinition `json:"fieldDefs"`        // Definitions of the Entity's custom fields (Properties)
+	ShowName         bool               `json:"showName"`         // Whether the Entity's name is displayed in LDtk
+	Doc              string             `json:"doc"`              // Documentation of the Entity written in LDtk; empty if there isn't any
+	ExportToTOC      bool               `json:"exportToToc"`      // Whether the Entity's instances are exported to the Project's TableOfContents
+	AllowOutOfBounds bool               `json:"allowOutOfBounds"` // Whether the Entity can be placed outside of the bounds of its Level in LDtk
+	ResizableX       bool               `json:"resizableX"`       // Whether the Entity can be resized horizontally in LDtk
+	ResizableY       bool               `json:"resizableY"`       // Whether the Entity can be resized vertically in LDtk
+	KeepAspectRatio  bool               `json:"keepAspectRatio"`  // Whether resizing the Entity in LDtk keeps its aspect ratio
+	MaxCount         int                `json:"maxCount"`         // The maximum number of instances of the Entity LDtk allows (per Level or per World, according to the LimitScope); 0 if there's no limit
+	LimitScope       string             `json:"limitScope"`       // Where the MaxCount applies in LDtk ("PerLayer", "PerLevel", or "PerWorld")
+	LimitBehavior    string             `json:"limitBehavior"`    // What LDtk does when more than MaxCount instances are placed ("DiscardOldOnes", "PreventAdding", or "MoveLastOne")
+}
+
+// HasTag returns if the EntityDefinition has the tag (category) specified.
+func (def *EntityDefinition) HasTag(tag string) bool {
+	for _, t := range def.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// FieldDefinitionByIdentifier returns the definition of the Entity's custom field with the Identifier given, or nil if one isn't found.
+func (def *EntityDefinition) FieldDefinitionByIdentifier(identifier string) *FieldDefinition {
+	return fieldDefinitionByIdentifier(def.FieldDefinitions, identifier)
+}
+
+// An Entity represents an Entity as placed in the LDtk level.
+type Entity struct {
+	Identifier       string      `json:"__identifier"`   // Name of the Entity
+	IID              string      `json:"iid"`            // IID of the Entity
+	Position         []int       `json:"px"`             // Position of the Entity (x, y)
+	GridPosition     []int       `json:"__grid"`         // Position of the Entity on the Layer's grid (x, y)
+	Width            int         `json:"width"`          // Width  of the Entity in pixels
+	Height           int         `json:"height"`         // Height of the Entity in pixels
+	Properties       []*Property `json:"fieldInstances"` // The Properties defined on the Entity
+	Pivot            []float32   `json:"__pivot"`        // Pivot position of the Entity (a centered Pivot would be 0.5, 0.5)
+	Tags             []string    `json:"__tags"`         // Tags (categories) assigned to the Entity
+	TileRect         *TileRect   `json:"__tile"`
+	DefUID           int         `json:"defUid"` // UID of the EntityDefinition this Entity is an instance of
+	SmartColorString string      `json:"__smartColor"`
+	SmartColor       color.Color `json:"-"` // The Entity's "smart" color as displayed in LDtk; this is the color of the Entity or of a Property flagged to be used for the smart color
+	Data             interface{} `json:"-"` // Data allows you to attach key custom data to the entity post-parsing
+	level            *Level      `json:"-"`
+	layer            *Layer      `json:"-"`
+}
+
+// Level returns the Level the Entity belongs to, or nil if it isn't part of one (i.e. if the Entity was created manually).
+func (entity *Entity) Level() *Level {
+	return entity.level
+}
+
+// Layer returns the Layer the Entity belongs to, or nil if it isn't part of one (i.e. if the Entity was created manually).
+func (entity *Entity) Layer() *Layer {
+	return entity.layer
+}
+
+// Definition returns the EntityDefinition this Entity is an instance of, or nil if it can't be found.
+func (entity *Entity) Definition() *EntityDefinition {
+	if entity.level == nil || entity.level.Project == nil {
+		return nil
+	}
+	return entity.level.Project.EntityDefinitionByUID(entity.DefUID)
+}
+
+// Doc returns the documentation written in LDtk for the Entity's definition (i.e. for showing in in-game dev tools), or a blank string if
+// there isn't any or the definition can't be found.
+func (entity *Entity) Doc() string {
+	if def := entity.Definition(); def != nil {
+		return def.Doc
+	}
+	return ""
+}
+
+// FieldDoc returns the documentation written in LDtk for the Entity's custom field with the Identifier given, or a blank string if there
+// isn't any or the field's definition can't be found.
+func (entity *Entity) FieldDoc(identifier string) string {
+	if def := entity.Definition(); def != nil {
+		if field := def.FieldDefinitionByIdentifier(identifier); field != nil {
+			return field.Doc
+		}
+	}
+	return ""
+}
+
+// HasTag returns if the Entity has the tag (category) specified.
+func (entity *Entity) HasTag(tag string) bool {
+	for _, t := range entity.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// WorldX returns the X position of the Entity in world space, adding in the positioning of the Level. Unlike WorldPosition, the offset of the
+// Entity's Layer isn't added, matching the Entity's Position as exported by LDtk.
+func (entity *Entity) WorldX() int {
+	return entity.Position[0] + entity.level.WorldX
+}
+
+// WorldY returns the Y position of the Entity in world space, adding in the positioning of the Level. Unlike WorldPosition, the offset of the
+// Entity's Layer isn't added, matching the Entity's Position as exported by LDtk.
+func (entity *Entity) WorldY() int {
+	return entity.Position[1] + entity.level.WorldY
+}
+
+// AnchorPoint returns the position of the Entity's pivot in level space, including the offset of the Layer the Entity is on.
+// Note that in LDtk, an Entity's Position is where its pivot lies, not its top-left corner.
+func (entity *Entity) AnchorPoint() (int, int) {
+	x, y := entity.Position[0], entity.Position[1]
+	if entity.layer != nil {
+		x += entity.layer.OffsetX
+		y += entity.layer.OffsetY
+	}
+	return x, y
+}
+
+// WorldPosition returns the position of the Entity's pivot in world space, adding in the positioning of the Level and the offset of the Layer.
+// This is where the Entity is drawn; it differs from WorldX and WorldY (which don't add the Layer's offset) when the Layer is offset.
+func (entity *Entity) WorldPosition() (int, int) {
+	x, y := entity.AnchorPoint()
+	if entity.level != nil {
+		x += entity.level.WorldX
+		y += entity.level.WorldY
+	}
+	return x, y
+}
+
+// Bounds returns the rectangle the Entity covers in level space, accounting for the Entity's pivot and the offset of its Layer.
+func (entity *Entity) Bounds() image.Rectangle {
+	x, y := entity.AnchorPoint()
+	if len(entity.Pivot) >= 2 {
+		x -= int(math.Round(float64(entity.Pivot[0]) * float64(entity.Width)))
+		y -= int(math.Round(float64(entity.Pivot[1]) * float64(entity.Height)))
+	}
+	return image.Rect(x, y, x+entity.Width, y+entity.Height)
+}
+
+// WorldBounds returns the rectangle the Entity covers in world space, accounting for the Entity's pivot, the offset of its Layer, and the
+// position of its Level; like WorldPosition (and unlike WorldX and WorldY), it includes the Layer's offset.
+func (entity *Entity) WorldBounds() image.Rectangle {
+	bounds := entity.Bounds()
+	if entity.level != nil {
+		bounds = bounds.Add(image.Pt(entity.level.WorldX, entity.level.WorldY))
+	}
+	return bounds
+}
+
+// PropertyByIdentifier returns a Property by its Identifier string (name).
+func (entity *Entity) PropertyByIdentifier(id string) *Property {
+
+	var prop *Property
+
+	for _, p := range entity.Properties {
+		if p.Identifier == id {
+			prop = p
+			break
+		}
+	}
+
+	if def := entity.Definition(); def != nil {
+		return propertyWithDefault(entity.level.Project, prop, def.FieldDefinitions, id)
+	}
+
+	return prop
+
+}
+
+// IntProperty returns the value of the Entity's Property with the Identifier given as an int, or the fallback given if the Property doesn't
+// exist, is null, or isn't a number.
+func (entity *Entity) IntProperty(id string, fallback int) int {
+	return propertyInt(entity.PropertyByIdentifier(id), fallback)
+}
+
+// FloatProperty returns the value of the Entity's Property with the Identifier given as a float64, or the fallback given if the Property
+// doesn't exist, is null, or isn't a number.
+func (entity *Entity) FloatProperty(id string, fallback float64) float64 {
+	return propertyFloat(entity.PropertyByIdentifier(id), fallback)
+}
+
+// BoolProperty returns the value of the Entity's Property with the Identifier given as a bool, or the fallback given if the Property doesn't
+// exist, is null, or isn't a boolean.
+func (entity *Entity) BoolProperty(id string, fallback bool) bool {
+	return propertyBool(entity.PropertyByIdentifier(id), fallback)
+}
+
+// StringProperty returns the value of the Entity's Property with the Identifier given as a string (i.e. for String, Multilines, Enum, and
+// FilePath Properties), or the fallback given if the Property doesn't exist, is null, or isn't a string.
+func (entity *Entity) StringProperty(id string, fallback string) string {
+	return propertyString(entity.PropertyByIdentifier(id), fallback)
+}
+
+// ColorProperty returns the value of the Entity's Property with the Identifier given as a color.Color, or the fallback given if the Property
+// doesn't exist, is null, or isn't a color.
+func (entity *Entity) ColorProperty(id string, fallback color.Color) color.Color {
+	return propertyColor(entity.PropertyByIdentifier(id), fallback)
+}
+
+// Integer indicates the value for an individual "Integer Object" on the IntGrid layer.
+type Integer struct {
+	Position []int `json:"-"`       // Not actually available from the LDtk file, but added in afterwards as a convenience; the position of the Integer in pixels.
+	Value    int   `json:"v"`       // The value of the Integer.
+	ID       int   `json:"coordID"` // The ID of the Integer on the IntGrid.
+}
+
+// EnumSet represents a set of Enums applied to tiles; this is just for convenience so you can see if a Tile contains an enum easily.
+type EnumSet []string
+
+func (e EnumSet) Contains(enum string) bool {
+	for _, v := range e {
+		if v == enum {
+			return true
+		}
+	}
+	return false
+}
+
+// ContainsAny returns true if the EnumSet contains any of the enums given. For checks made every frame (i.e. for collision), consider using
+// EnumMasks from an EnumIndex instead, which don't compare strings.
+func (e EnumSet) ContainsAny(enums ...string) bool {
+	for _, enum := range enums {
+		if e.Contains(enum) {
+			return true
+		}
+	}
+	return false
+}
+
+// ContainsAll returns true if the EnumSet contains all of the enums given.
+func (e EnumSet) ContainsAll(enums ...string) bool {
+	for _, enum := range enums {
+		if !e.Contains(enum) {
+			return false
+		}
+	}
+	return true
+}
+
+// Intersect returns a new EnumSet of the enums that are in both the EnumSet and the other EnumSet given, in the order of the EnumSet.
+func (e EnumSet) Intersect(other EnumSet) EnumSet {
+	intersection := EnumSet{}
+	for _, v := range e {
+		if other.Contains(v) && !intersection.Contains(v) {
+			intersection = append(intersection, v)
+		}
+	}
+	return intersection
+}
+
+// Union returns a new EnumSet of the enums that are in either the EnumSet or the other EnumSet given: the enums of the EnumSet, followed by
+// the enums of the other EnumSet that aren't in it.
+func (e EnumSet) Union(other EnumSet) EnumSet {
+	union := EnumSet{}
+	for _, set := range []EnumSet{e, other} {
+		for _, v := range set {
+			if !union.Contains(v) {
+				union = append(union, v)
+			}
+		}
+	}
+	return union
+}
+
+// Tile represents a graphical tile (whether automatic or manually placed).
+type Tile struct {
+	Position   []int    `json:"px"` // Position of the Tile in pixels (x, y)
+	Src        []int    // The source position on the texture to draw this texture
+	Flip       TileFlip `json:"f"` // Flip bits - first bit is for X-flip, second is for Y. 0 = no flip, 1 = horizontal flip, 2 = vertical flip, 3 = both flipped
+	ID         int      `json:"t"` // The ID of the Tile (starting from 0).
+	RuleUID    int      `json:"-"` // For auto-layer Tiles, the UID of the auto-layer rule that placed the Tile; 0 for manually placed Tiles
+	TilesetUID int      `json:"-"` // UID of the Tileset the Tile is drawn from if it overrides its Layer's Tileset (i.e. to mix tiles from several Tilesets in one Layer); 0 to use the Layer's Tileset. LDtk doesn't export this, but it can be set when building Levels
+	layer      *Layer
+}
+
+// TileFlip represents the flip bits of a Tile.
+type TileFlip byte
+
+// TileFlip constants for each of the flip bits LDtk-Go understands.
+const (
+	TileFlipX TileFlip = 1 << iota // The Tile is flipped horizontally
+	TileFlipY                      // The Tile is flipped vertically
+
+	tileFlipKnown = TileFlipX | TileFlipY
+)
+
+// FlipX returns if the horizontal flip bit is set.
+func (f TileFlip) FlipX() bool {
+	return f&TileFlipX > 0
+}
+
+// FlipY returns if the vertical flip bit is set.
+func (f TileFlip) FlipY() bool {
+	return f&TileFlipY > 0
+}
+
+// Rotation returns the clockwise rotation of the Tile in degrees (0, 90, 180, or 270). LDtk doesn't currently rotate tiles, so this is always 0;
+// it exists so that code which handles it keeps working should a later version of LDtk use the reserved flip bits for rotation.
+func (f TileFlip) Rotation() int {
+	return 0
+}
+
+// Unknown returns any bits that are set that LDtk-Go doesn't understand (i.e. from a newer version of LDtk). These bits are ignored when drawing.
+func (f TileFlip) Unknown() TileFlip {
+	return f &^ tileFlipKnown
+}
+
+// TileTransform describes how a Tile's source image should be transformed when it's drawn.
+type TileTransform struct {
+	FlipX    bool          // Whether the Tile is flipped horizontally
+	FlipY    bool          // Whether the Tile is flipped vertically
+	Rotation int           // Clockwise rotation of the Tile in degrees, applied after flipping
+	Matrix   [3][3]float64 // An affine transformation matrix (in row-major order) that flips and rotates the Tile's image in place; apply it to the source image before moving the Tile to its Position
+}
+
+// SrcRect returns the rectangle of the Tile's image in its Tileset, along with how the image should be transformed when it's drawn.
+// If the Layer given is nil, the Layer the Tile belongs to is used. The rectangle is the size of the Tileset's grid, which can differ from the
+// Layer's; the Tileset's padding and spacing are already accounted for in the Tile's Src, and if the Tile doesn't have a Src (i.e. it was
+// created in code), it's found from the Tile's ID using Tileset.TileRect.
+func (t *Tile) SrcRect(layer *Layer) (image.Rectangle, TileTransform) {
+
+	if layer == nil {
+		layer = t.layer
+	}
+
+	size := 0
+	if layer != nil {
+		size = layer.GridSize
+	}
+
+	tileset := t.tilesetIn(layer)
+
+	if tileset != nil && tileset.GridSize > 0 {
+		size = tileset.GridSize
+	}
+
+	transform := TileTransform{
+		FlipX:    t.FlipX(),
+		FlipY:    t.FlipY(),
+		Rotation: t.Flip.Rotation(),
+	}
+
+	scaleX, scaleY := 1.0, 1.0
+
+	if transform.FlipX {
+		scaleX = -1
+	}
+
+	if transform.FlipY {
+		scaleY = -1
+	}
+
+	sin, cos := math.Sincos(float64(transform.Rotation) * math.Pi / 180)
+	sin, cos = math.Round(sin), math.Round(cos)
+
+	// The Tile is flipped, then rotated, around its center, so the result is moved back by however much its center moved.
+	a, b := cos*scaleX, -sin*scaleY
+	c, d := sin*scaleX, cos*scaleY
+	center := float64(size) / 2
+
+	transform.Matrix = [3][3]float64{
+		{a, b, center - (a*center + b*center)},
+		{c, d, center - (c*center + d*center)},
+		{0, 0, 1},
+	}
+
+	if len(t.Src) < 2 {
+		if tileset == nil {
+			return image.Rectangle{}, transform
+		}
+		return tileset.TileRect(t.ID), transform
+	}
+
+	return image.Rect(t.Src[0], t.Src[1], t.Src[0]+size, t.Src[1]+size), transform
+
+}
+
+// Layer returns the Layer the Tile belongs to.
+func (t *Tile) Layer() *Layer {
+	return t.layer
+}
+
+// Tileset returns the Tileset the Tile is drawn from: the Tileset with the Tile's TilesetUID if it has one, or its Layer's Tileset otherwise.
+// nil is returned if the Tileset can't be found.
+func (t *Tile) Tileset() *Tileset {
+	return t.tilesetIn(t.layer)
+}
+
+// tilesetIn returns the Tileset the Tile is drawn from when it's in the Layer given.
+func (t *Tile) tilesetIn(layer *Layer) *Tileset {
+	if layer == nil {
+		return nil
+	}
+	if t.TilesetUID == 0 {
+		return layer.Tileset
+	}
+	if layer.level == nil || layer.level.Project == nil {
+		return nil
+	}
+	return layer.level.Project.TilesetByUID(t.TilesetUID)
+}
+
+// Enums returns the EnumSet defined for the Tile in its Layer's Tileset. If no enums are defined, an empty EnumSet is returned.
+func (t *Tile) Enums() EnumSet {
+	tileset := t.Tileset()
+	if tileset == nil {
+		return EnumSet{}
+	}
+	return tileset.EnumsForTile(t.ID)
+}
+
+// CustomData returns the custom data defined for the Tile in its Layer's Tileset. If no custom data is defined, a blank string is returned.
+func (t *Tile) CustomData() string {
+	tileset := t.Tileset()
+	if tileset == nil {
+		return ""
+	}
+	return tileset.CustomDataForTile(t.ID)
+}
+
+// FlipX returns if the Tile is flipped horizontally.
+func (t *Tile) FlipX() bool {
+	return t.Flip.FlipX()
+}
+
+// FlipY returns if the Tile is flipped vertically.
+func (t *Tile) FlipY() bool {
+	return t.Flip.FlipY()
+}
+
+// LayerDefinition represents the definition of a Layer in the Project, which is shared between the Layer's instances in each Level.
+type LayerDefinition struct {
+	Identifier            string                    `json:"identifier"`            // Identifier (name) of the Layer
+	UID                   int                       `json:"uid"`                   // UID of the LayerDefinition
+	Type                  string                    `json:"type"`                  // Type of Layer; can be compared using LayerType constants
+	GridSize              int                       `json:"gridSize"`              // Grid size of the Layer
+	TilesetUID            int                       `json:"tilesetDefUid"`         // UID of the Tileset used by the Layer, if any
+	AutoSourceLayerDefUID int                       `json:"autoSourceLayerDefUid"` // For AutoLayers, the UID of the IntGrid LayerDefinition the auto-layer rules are applied to
+	IntGridValues         []*IntGridValueDefinition `json:"intGridValues"`         // For IntGrid layers, the values that can be placed in the IntGrid
+	AutoRuleGroups        []*AutoRuleGroup          `json:"autoRuleGroups"`        // The groups of auto-layer rules used to create tiles automatically from the IntGrid
+}
+
+// IntGridValue returns the definition of the IntGrid value given, or nil if the LayerDefinition doesn't have one for it.
+func (def *LayerDefinition) IntGridValue(value int) *IntGridValueDefinition {
+	for _, v := range def.IntGridValues {
+		if v.Value == value {
+			return v
+		}
+	}
+	return nil
+}
+
+// IntGridValueDefinition represents a value that can be placed in an IntGrid layer.
+type IntGridValueDefinition struct {
+	Value       int         `json:"value"`      // The value of the IntGrid cell
+	Identifier  string      `json:"identifier"` // Identifier (name) of the value
+	ColorString string      `json:"color"`      // Color of the value in LDtk as a hex string
+	Color       color.Color `json:"-"`          // Color of the value in LDtk
+	GroupUID    int         `json:"groupUid"`   // UID of the group the value belongs to; 0 if it's not in a group
+}
+
+// Layer represents a Layer, which can be of multiple types (Entity, AutoTile, Tile, or IntGrid).
+type Layer struct {
+	// The width and height of the layer
+	Identifier string   `json:"__identifier"`     // Identifier (name) of the Layer
+	IID        string   `json:"iid"`              // IID of the layer
+	GridSize   int      `json:"__gridsize"`       // Grid size of the Layer
+	OffsetX    int      `json:"__pxTotalOffsetX"` // The offset of the layer
+	OffsetY    int      `json:"__pxTotalOffsetY"`
+	CellWidth  int      `json:"__cWid"` // Overall width of the layer in cell count (i.e. a 160x80 level with 16x16 tiles would have a CellWidth and CellHeight of 10x5)
+	CellHeight int      `json:"__cHei"` // Overall height of the layer in cell count
+	Type       string   `json:"__type"` // Type of Layer. Can be compared using LayerType constants
+	Tileset    *Tileset `json:"-"`      // Reference to the Tileset used for this Layer (assuming the path is the same)
+	// TilesetPath string     `json:"__tilesetRelPath"` // Relative path to the tileset image; already is normalized using filepath.FromSlash().
+	TilesetUID    int        `json:"__tilesetDefUid"` // The UID of the used tileset
+	IntGrid       []*Integer `json:"-"`
+	AutoTiles     []*Tile    `json:"autoLayerTiles"` // Automatically set if IntGrid has values; like Tiles, these are in drawing order, as exported by LDtk (including the ordering of the rules that placed them)
+	Tiles         []*Tile    `json:"gridTiles"`      // Manually placed tiles, in drawing order (each Tile is drawn over the ones before it, including Tiles stacked in the same cell)
+	Entities      []*Entity  `json:"entityInstances"`
+	Visible       bool       `json:"visible"`       // Whether the layer is visible in LDtk
+	DefUID        int        `json:"layerDefUid"`   // UID of the LayerDefinition this Layer is an instance of
+	Seed          int        `json:"seed"`          // Random seed used by LDtk when applying auto-layer rules
+	OptionalRules []int      `json:"optionalRules"` // UIDs of the optional auto-layer rule groups that are enabled for this Layer
+	level         *Level     `json:"-"`
+
+	unknownFlips int
+	memory       *layerMemory
+}
+
+// Level returns the Level the Layer belongs to, or nil if it isn't part of one (i.e. if the Layer was created manually).
+func (layer *Layer) Level() *Level {
+	return layer.level
+}
+
+// Definition returns the LayerDefinition this Layer is an instance of, or nil if it can't be found.
+func (layer *Layer) Definition() *LayerDefinition {
+	if layer.level == nil || layer.level.Project == nil {
+		return nil
+	}
+	return layer.level.Project.LayerDefinitionByUID(layer.DefUID)
+}
+
+// ForEachTile runs a callback for each tile in the Layer. This is to make it simpler to run a render loop regardless of if the Layer is composed of auto tiles or
+// manually placed tiles.
+func (layer *Layer) ForEachTile(function func(tile *Tile)) {
+	for _, tile := range layer.Tiles {
+		function(tile)
+	}
+	for _, tile := range layer.AutoTiles {
+		function(tile)
+	}
+}
+
+// TileCount returns the number of tiles in the Layer, both manually placed and auto-layer tiles.
+func (layer *Layer) TileCount() int {
+	return len(layer.Tiles) + len(layer.AutoTiles)
+}
+
+// TileFilter constants indicating which of a Layer's tiles Layer.EachTile iterates through.
+const (
+	TileFilterAll    = iota // Both manually placed and auto-layer tiles
+	TileFilterManual        // Only manually placed tiles (Layer.Tiles)
+	TileFilterAuto          // Only auto-layer tiles (Layer.AutoTiles)
+)
+
+// EachTile runs the callback given for each tile in the Layer that passes the filter given (which can be one of the TileFilter constants), in
+// drawing order (manually placed tiles first, then auto-layer tiles). Along with each tile, the callback is given the tile's position on the
+// Layer's grid and its index in the iteration. If the callback returns false, iteration stops.
+func (layer *Layer) EachTile(filter int, function func(tile *Tile, gridX, gridY, index int) bool) {
+
+	index := 0
+
+	each := func(tiles []*Tile) bool {
+		for _, tile := range tiles {
+			gridX, gridY := layer.ToGridPosition(tile.Position[0], tile.Position[1])
+			if !function(tile, gridX, gridY, index) {
+				return false
+			}
+			index++
+		}
+		return true
+	}
+
+	if filter != TileFilterAuto && !each(layer.Tiles) {
+		return
+	}
+
+	if filter != TileFilterManual {
+		each(layer.AutoTiles)
+	}
+
+}
+
+// EntityByIdentifier returns the Entity with the identifier (name) specified. If no Entity with the name is found, the function returns nil.
+func (layer *Layer) EntityByIdentifier(identifier string) *Entity {
+	for _, entity := range layer.Entities {
+		if entity.Identifier == identifier {
+			return entity
+		}
+	}
+	return nil
+}
+
+// EntitiesByTag returns the Entities in the Layer that have the tag (category) specified. If no Entities have the tag, an empty slice is returned.
+func (layer *Layer) EntitiesByTag(tag string) []*Entity {
+	entities := []*Entity{}
+	for _, entity := range layer.Entities {
+		if entity.HasTag(tag) {
+			entities = append(entities, entity)
+		}
+	}
+	return entities
+}
+
+// EntityByIID returns the Entity with the IID specified. If no Entity with the name is found, the function returns nil.
+func (layer *Layer) EntityByIID(iid string) *Entity {
+	for _, entity := range layer.Entities {
+		if entity.IID == iid {
+			return entity
+		}
+	}
+	return nil
+}
+
+// ToGridPosition converts the specified position from a position in world space to a position on the Layer's grid. For example, if the layer were 128x128 and had 16x16 tiles, ToGridPosition(32, 16) would return (2, 1).
+func (layer *Layer) ToGridPosition(x, y int) (int, int) {
+	x /= layer.GridSize
+	y /= layer.GridSize
+	return x, y
+}
+
+// FromGridPosition converts the specified position from a position on the Layer's grid to world space. For example, if the layer were 128x128 and had 16x16 tiles, FromGridPosition(3, 4) would return (48, 64).
+func (layer *Layer) FromGridPosition(x, y int) (int, int) {
+	x *= layer.GridSize
+	y *= layer.GridSize
+	return x, y
+}
+
+// ToWorldPosition converts the specified position on the Layer's grid to the position of the top-left corner of that cell in the world, taking
+// into account the Layer's offset and the Level's position in the world. This is unlike FromGridPosition, which returns a position relative to
+// the Level and ignores the Layer's offset.
+func (layer *Layer) ToWorldPosition(gridX, gridY int) (int, int) {
+
+	x, y := layer.FromGridPosition(gridX, gridY)
+	x += layer.OffsetX
+	y += layer.OffsetY
+
+	if layer.level != nil {
+		x += layer.level.WorldX
+		y += layer.level.WorldY
+	}
+
+	return x, y
+
+}
+
+// ToGridPositionWorld converts the specified position in the world to a position on the Layer's grid, taking into account the Layer's offset
+// and the Level's position in the world; it's the inverse of ToWorldPosition. Positions above or to the left of the Layer return negative grid
+// positions (rather than being rounded towards 0, like with ToGridPosition).
+func (layer *Layer) ToGridPositionWorld(x, y int) (int, int) {
+
+	x -= layer.OffsetX
+	y -= layer.OffsetY
+
+	if layer.level != nil {
+		x -= layer.level.WorldX
+		y -= layer.level.WorldY
+	}
+
+	return floorDiv(x, layer.GridSize), floorDiv(y, layer.GridSize)
+
+}
+
+// floorDiv divides the value given by the divisor, rounding down (rather than towards 0, like Go's integer division).
+func floorDiv(value, divisor int) int {
+	result := value / divisor
+	if value%divisor != 0 && (value < 0) != (divisor < 0) {
+		result--
+	}
+	return result
+}
+
+// TileAt returns the Tile at the specified grid (not world) X and Y position. If several Tiles are stacked in the cell, the bottom-most one is
+// returned; use TilesAt to get all of them.
+// Note that this doesn't take into account the Layer's local Offset values (so a tile at 3, 4
+// on a layer with an offset of 64, 64 would still be found at 3, 4). To look up a Tile using a position in the world, use TileAtWorld.
+func (layer *Layer) TileAt(x, y int) *Tile {
+
+	for _, tile := range layer.Tiles {
+		cx, cy := layer.ToGridPosition(tile.Position[0], tile.Position[1])
+		if cx == x && cy == y {
+			return tile
+		}
+	}
+
+	return nil
+
+}
+
+// AutoTileAt returns the AutoLayer Tile at the specified grid (not world) X and Y position.
+// Note that this doesn't take into account the Layer's local Offset values (so a tile at 3, 4 on a layer
+// with an offset of 64, 64 would still be found at 3, 4). To look up a Tile using a position in the world, use AutoTileAtWorld.
+func (layer *Layer) AutoTileAt(x, y int) *Tile {
+
+	for _, autoTile := range layer.AutoTiles {
+		cx, cy := layer.ToGridPosition(autoTile.Position[0], autoTile.Position[1])
+		if cx == x && cy == y {
+			return autoTile
+		}
+	}
+
+	return nil
+
+}
+
+// TilesAt returns all of the Tiles (both manually placed and auto-layer Tiles) at the specified grid (not world) X and Y position, in drawing
+// order (from the bottom-most Tile to the top-most). Like TileAt, this doesn't take into account the Layer's local Offset values.
+func (layer *Layer) TilesAt(x, y int) []*Tile {
+
+	tiles := []*Tile{}
+
+	layer.EachTile(TileFilterAll, func(tile *Tile, gridX, gridY, index int) bool {
+		if gridX == x && gridY == y {
+			tiles = append(tiles, tile)
+		}
+		return true
+	})
+
+	return tiles
+
+}
+
+// AllTiles returns all of the Layer's Tiles in drawing order, as a new slice: manually placed Tiles, followed by auto-layer Tiles, with each
+// in the order LDtk exported them (or RunAutoRules placed them).
+func (layer *Layer) AllTiles() []*Tile {
+	tiles := make([]*Tile, 0, len(layer.Tiles)+len(layer.AutoTiles))
+	tiles = append(tiles, layer.Tiles...)
+	return append(tiles, layer.AutoTiles...)
+}
+
+// EntityAt returns the first Entity whose grid position is at the specified grid (not world) X and Y position, or nil if there isn't one.
+// Like TileAt, this doesn't take into account the Layer's local Offset values; to look up an Entity using a position in the world, use EntityAtWorld.
+func (layer *Layer) EntityAt(x, y int) *Entity {
+
+	for _, entity := range layer.Entities {
+		if len(entity.GridPosition) >= 2 && entity.GridPosition[0] == x && entity.GridPosition[1] == y {
+			return entity
+		}
+	}
+
+	return nil
+
+}
+
+// IntegerAt returns the IntGrid Integer at the specified grid (not world) X and Y position.
+// Note that this doesn't take into account the Layer's local Offset values (so a tile at 3, 4 on a layer with an
+// offset of 64, 64 would still be found at 3, 4). To look up an Integer using a position in the world, use IntegerAtWorld.
+func (layer *Layer) IntegerAt(x, y int) *Integer {
+
+	for _, integer := range layer.IntGrid {
+		cx, cy := layer.ToGridPosition(integer.Position[0], integer.Position[1])
+		if cx == x && cy == y {
+			return integer
+		}
+	}
+
+	return nil
+
+}
+
+// TileAtWorld returns the Tile in the cell at the specified world position, taking into account the Layer's offset and the Level's position
+// in the world (see ToGridPositionWorld).
+func (layer *Layer) TileAtWorld(x, y int) *Tile {
+	return layer.TileAt(layer.ToGridPositionWorld(x, y))
+}
+
+// AutoTileAtWorld returns the AutoLayer Tile in the cell at the specified world position, taking into account the Layer's offset and the
+// Level's position in the world (see ToGridPositionWorld).
+func (layer *Layer) AutoTileAtWorld(x, y int) *Tile {
+	return layer.AutoTileAt(layer.ToGridPositionWorld(x, y))
+}
+
+// EntityAtWorld returns the first Entity whose grid position is the cell at the specified world position, taking into account the Layer's
+// offset and the Level's position in the world (see ToGridPositionWorld).
+func (layer *Layer) EntityAtWorld(x, y int) *Entity {
+	return layer.EntityAt(layer.ToGridPositionWorld(x, y))
+}
+
+// IntegerAtWorld returns the IntGrid Integer in the cell at the specified world position, taking into account the Layer's offset and the
+// Level's position in the world (see ToGridPositionWorld).
+func (layer *Layer) IntegerAtWorld(x, y int) *Integer {
+	return layer.IntegerAt(layer.ToGridPositionWorld(x, y))
+}
+
+// Index returns the index of the layer in the Level's layer stack (its Layers), where 0 is the top-most Layer, as in LDtk.
+func (layer *Layer) Index() int {
+	for i, l := range layer.level.Layers {
+		if l == layer {
+			return i
+		}
+	}
+	return -1
+}
+
+// ZIndex returns the Layer's position in the Level's drawing order: 0 for the bottom-most Layer (which is drawn first), increasing towards the
+// top-most Layer. This is the reverse of Index. -1 is returned if the Layer isn't part of a Level.
+func (layer *Layer) ZIndex() int {
+	if layer.level == nil {
+		return -1
+	}
+	index := layer.Index()
+	if index < 0 {
+		return -1
+	}
+	return len(layer.level.Layers) - 1 - index
+}
+
+type Tileset struct {
+	Path       string `json:"relPath"` // Relative path to the tileset image; already is normalized using filepath.FromSlash().
+	ID         int    `json:"uid"`
+	GridSize   int    `json:"tileGridSize"`
+	Spacing    int
+	Padding    int
+	Width      int `json:"pxWid"`
+	Height     int `json:"pxHei"`
+	Identifier string
+	CustomData map[int]string  `json:"-"` // Key: tileID, Value: custom data string
+	Enums      map[int]EnumSet `json:"-"` // Key: enumValueID, Value: tileIDs (tile indices)
+
+	Tags              []string `json:"tags"`              // Tags (categories) of the Tileset, set in LDtk
+	TagsSourceEnumUID *int     `json:"tagsSourceEnumUid"` // UID of the Enum whose values the Tileset's tiles are tagged with (see Enums); nil if the tiles aren't tagged
+	EmbedAtlas        string   `json:"embedAtlas"`        // The identifier of the atlas built into LDtk that the Tileset is (i.e. EmbedAtlasLDtkIcons), or blank if it's a regular Tileset. Embedded atlases have no image Path
+}
+
+// EmbedAtlasLDtkIcons is the EmbedAtlas of the Tileset of icons built into LDtk. Its image isn't saved alongside the project, so it can't be
+// drawn.
+const EmbedAtlasLDtkIcons = "LdtkIcons"
+
+// HasTag returns if the Tileset has the tag (category) specified.
+func (t *Tileset) HasTag(tag string) bool {
+	for _, tilesetTag := range t.Tags {
+		if tilesetTag == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// IsEmbedded returns if the Tileset is an atlas built into LDtk (i.e. the LDtk icons), rather than an image saved alongside the project.
+func (t *Tileset) IsEmbedded() bool {
+	return t.EmbedAtlas != ""
+}
+
+// CustomDataForTile returns the custom data defined for the tile of the ID given in the tileset. If no custom data is defined, a blank string is returned.
+func (t *Tileset) CustomDataForTile(tileID int) string {
+	if data, exists := t.CustomData[tileID]; exists {
+		return data
+	}
+	return ""
+}
+
+// EnumsForTile returns the EnumSet defined for the tile of the ID given in the tileset. If no enums are defined, an empty EnumSet is returned.
+func (t *Tileset) EnumsForTile(tileID int) EnumSet {
+	if data, exists := t.Enums[tileID]; exists {
+		return data
+	}
+	return EnumSet{}
+}
+
+// CustomDataTileIDs returns the IDs of the tiles in the Tileset that have custom data, in ascending order. Use this rather than ranging over
+// CustomData when the order matters (i.e. when exporting the data), as map iteration order is random.
+func (t *Tileset) CustomDataTileIDs() []int {
+	ids := make([]int, 0, len(t.CustomData))
+	for id := range t.CustomData {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// EnumTileIDs returns the IDs of the tiles in the Tileset that are tagged with enums, in ascending order. Use this rather than ranging over
+// Enums when the order matters, as map iteration order is random.
+func (t *Tileset) EnumTileIDs() []int {
+	ids := make([]int, 0, len(t.Enums))
+	for id := range t.Enums {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// CellsWide returns the number of tiles in each row of the Tileset's image, accounting for its spacing and padding.
+func (t *Tileset) CellsWide() int {
+	if t.GridSize <= 0 || t.GridSize+t.Spacing <= 0 {
+		return 0
+	}
+	// This matches how LDtk itself counts the cells, so that partial cells at the edge of the image still get an ID.
+	return (t.Width - t.Padding*2 + t.GridSize + t.Spacing - 1) / (t.GridSize + t.Spacing)
+}
+
+// CellsHigh returns the number of tiles in each column of the Tileset's image, accounting for its spacing and padding.
+func (t *Tileset) CellsHigh() int {
+	if t.GridSize <= 0 || t.GridSize+t.Spacing <= 0 {
+		return 0
+	}
+	return (t.Height - t.Padding*2 + t.GridSize + t.Spacing - 1) / (t.GridSize + t.Spacing)
+}
+
+// TileCount returns the total number of tiles in the Tileset.
+func (t *Tileset) TileCount() int {
+	return t.CellsWide() * t.CellsHigh()
+}
+
+// TileRect returns the rectangle of the tile of the ID given within the Tileset's image, in pixels.
+func (t *Tileset) TileRect(tileID int) image.Rectangle {
+
+	cellsWide := t.CellsWide()
+
+	if cellsWide <= 0 || tileID < 0 {
+		return image.Rectangle{}
+	}
+
+	x := t.Padding + (tileID%cellsWide)*(t.GridSize+t.Spacing)
+	y := t.Padding + (tileID/cellsWide)*(t.GridSize+t.Spacing)
+
+	return image.Rect(x, y, x+t.GridSize, y+t.GridSize)
+
+}
+
+// IDForSrc returns the ID of the tile that contains the pixel position given within the Tileset's image (i.e. a Tile's Src position).
+// If the position isn't within a tile (i.e. it's outside of the image or in the spacing or padding between tiles), -1 is returned.
+func (t *Tileset) IDForSrc(x, y int) int {
+
+	cellsWide := t.CellsWide()
+
+	if cellsWide <= 0 || x < t.Padding || y < t.Padding {
+		return -1
+	}
+
+	stride := t.GridSize + t.Spacing
+	cx := (x - t.Padding) / stride
+	cy := (y - t.Padding) / stride
+
+	if cx >= cellsWide || cy >= t.CellsHigh() || (x-t.Padding)%stride >= t.GridSize || (y-t.Padding)%stride >= t.GridSize {
+		return -1
+	}
+
+	return cy*cellsWide + cx
+
+}
+
+// BGPosition constants indicating how a Level's background image is positioned and scaled within the Level.
+const (
+	BGPositionUnscaled   = "Unscaled"
+	BGPositionContain    = "Contain"
+	BGPositionCover      = "Cover"
+	BGPositionCoverDirty = "CoverDirty"
+	BGPositionRepeat     = "Repeat"
+)
+
+// BGImage represents a Level's background image as definied withing LDtk (the filepath, the scale, etc).
+type BGImage struct {
+	Path     string
+	ScaleX   float64
+	ScaleY   float64
+	CropRect []float64 // The cropped sub-rectangle of the image to display (x, y, width, height)
+	TopLeftX float64   // The position of the top-left corner of the (cropped and scaled) image in the Level
+	TopLeftY float64
+	PivotX   float64 // The pivot used to align the image within the Level (0, 0 is the top-left, 0.5, 0.5 is centered)
+	PivotY   float64
+	Mode     string // How the image is positioned and scaled; can be compared using BGPosition constants
+}
+
+// Neighbour direction constants indicating where a neighbouring Level is relative to a Level.
+const (
+	NeighbourNorth      = "n"
+	NeighbourNorthEast  = "ne"
+	NeighbourEast       = "e"
+	NeighbourSouthEast  = "se"
+	NeighbourSouth      = "s"
+	NeighbourSouthWest  = "sw"
+	NeighbourWest       = "w"
+	NeighbourNorthWest  = "nw"
+	NeighbourOverlap    = "o" // The Levels overlap in the same world depth
+	NeighbourDepthBelow = "<" // The Level is at a lower world depth
+	NeighbourDepthAbove = ">" // The Level is at a higher world depth
+)
+
+// Neighbour represents a Level that touches or overlaps another Level in the world.
+type Neighbour struct {
+	LevelIID  string `json:"levelIid"` // IID of the neighbouring Level
+	Direction string `json:"dir"`      // Where the neighbouring Level is; can be compared using Neighbour direction constants
+}
+
+// Level represents a Level in an LDtk Project.
+type Level struct {
+	Identifier    string // Name of the Level (i.e. "Level0")
+	WorldX        int    // Position of the Level in the LDtk Project / world
 	WorldY        int
 	Width         int         `json:"pxWid"` // Width and height of the level in pixels.
 	Height        int         `json:"pxHei"`
 	IID           string      `json:"iid"` // IID of the level
 	BGColorString string      `json:"__bgColor"`
 	BGColor       color.Color `json:"-"`              // Background Color for the Level; will automatically default to the Project's if it is left at default in the LDtk project.
-	Layers        []*Layer    `json:"layerInstances"` // The layers in the level in the project. Note that layers here (first is "furthest" / at the bottom, last is on top) is reversed compared to LDtk (first is at the top, bottom is on the bottom).
+	Layers        []*Layer    `json:"layerInstances"` // The layers in the level in the project, in the same order as in LDtk (and its JSON): the first layer is on top and the last is at the bottom, so layers are drawn in reverse order. See LayersBottomToTop and Layer.ZIndex
 	Properties    []*Property `json:"fieldInstances"` // The Properties defined on the Entity
 	BGImage       *BGImage    `json:"-"`              // Any background image that might be applied to this Level.
 	Project       *Project    `json:"-"`
+	ExternalPath  string      `json:"externalRelPath"` // Relative path to the Level's external file (.ldtkl), if the Project saves Levels separately
+	Neighbours    []Neighbour `json:"__neighbours"`    // The Levels that touch or overlap this one in the world
+	WorldDepth    int         `json:"worldDepth"`      // The depth of the Level in the world, for Levels stacked on top of each other (i.e. above and below ground); 0 by default, with greater values above and lower values below
+
+	lazy     bool   // If the Level's Layers are loaded on demand (see LazyLevels)
+	pending  bool   // If the Level's Layers haven't been loaded yet
+	lazyData []byte // The JSON the Level's Layers are loaded from, unless they're in an external level file
+	world    *World
+}
+
+// WorldBounds returns the rectangle the Level occupies in the world, in pixels.
+func (level *Level) WorldBounds() image.Rectangle {
+	return image.Rect(level.WorldX, level.WorldY, level.WorldX+level.Width, level.WorldY+level.Height)
+}
+
+// Contains returns if the world position given (in pixels) is within the Level's WorldBounds; like an image.Rectangle, the Level's left and
+// top edges are within it, while its right and bottom edges aren't.
+func (level *Level) Contains(worldX, worldY int) bool {
+	return image.Pt(worldX, worldY).In(level.WorldBounds())
+}
+
+// ThumbnailPath returns the path of the PNG image of the whole Level exported by LDtk (when "export level images" is enabled for the Project),
+// relative to the project file like a Tileset's Path (see Project.ResolvePath), i.e. for showing previews on a level select screen without
+// rendering the Levels. As LDtk does, the image is looked for in the "png" directory of the directory named after the project file, using the
+// Project's PNGFilePattern (or "%level_name" by default). An empty string is returned if the Project doesn't export Level images, or if it
+// wasn't loaded using Open (as the name of the project file isn't known). Note that the image may not exist if the Project hasn't been saved
+// since exporting was enabled.
+func (level *Level) ThumbnailPath() string {
+
+	project := level.Project
+
+	if project == nil || project.Path == "" {
+		return ""
+	}
+
+	if project.ImageExportMode != ImageExportModeOneImagePerLevel && project.ImageExportMode != ImageExportModeLayersAndLevels {
+		return ""
+	}
+
+	pattern := project.PNGFilePattern
+	if pattern == "" {
+		pattern = "%level_name"
+	}
+
+	index := 0
+	for i, l := range project.Levels {
+		if l == level {
+			index = i
+			break
+		}
+	}
+
+	name := strings.NewReplacer(
+		"%level_name", level.Identifier,
+		"%level_idx", fmt.Sprintf("%04d", index),
+		"%layer_name", "",
+		"%layer_idx", "",
+	).Replace(pattern)
+
+	projectName := strings.TrimSuffix(path.Base(project.Path), path.Ext(project.Path))
+
+	return path.Join(projectName, "png", name+".png")
+
+}
+
+// NeighbourLevels returns the Levels neighbouring this one in any of the directions given (see the Neighbour direction constants). If no
+// directions are given, all neighbouring Levels are returned.
+func (level *Level) NeighbourLevels(directions ...string) []*Level {
+
+	levels := []*Level{}
+
+	if level.Project == nil {
+		return levels
+	}
+
+	for _, neighbour := range level.Neighbours {
+
+		matches := len(directions) == 0
+
+		for _, dir := range directions {
+			if neighbour.Direction == dir {
+				matches = true
+				break
+			}
+		}
+
+		if matches {
+			if neighbourLevel := level.Project.LevelByIID(neighbour.LevelIID); neighbourLevel != nil {
+				levels = append(levels, neighbourLevel)
+			}
+		}
+
+	}
+
+	return levels
+
+}
+
+// NeighbourAt returns the neighbouring Level that contains the world position given, or nil if there isn't one. This is useful to find the
+// Level to transition to when the player leaves the current one.
+func (level *Level) NeighbourAt(worldX, worldY int) *Level {
+	for _, neighbour := range level.NeighbourLevels() {
+		if neighbour.Contains(worldX, worldY) {
+			return neighbour
+		}
+	}
+	return nil
+}
+
+// LayersTopToBottom returns the Level's Layers from the top-most to the bottom-most, which is the order of Layers (and of LDtk's layer list).
+func (level *Level) LayersTopToBottom() []*Layer {
+	return append([]*Layer{}, level.Layers...)
+}
+
+// LayersBottomToTop returns the Level's Layers from the bottom-most to the top-most, which is the order they're drawn in.
+func (level *Level) LayersBottomToTop() []*Layer {
+	layers := make([]*Layer, len(level.Layers))
+	for i, layer := range level.Layers {
+		layers[len(layers)-1-i] = layer
+	}
+	return layers
 }
 
 // LayerByIdentifier returns a Layer by its identifier (name). Returns nil if the specified Layer isn't found.
@@ -382,94 +1825,401 @@ func (level *Level) LayerByIID(iid string) *Layer {
 	return nil
 }
 
+// EntitiesByTag returns the Entities across all of the Level's Layers that have the tag (category) specified. If no Entities have the tag,
+// an empty slice is returned.
+func (level *Level) EntitiesByTag(tag string) []*Entity {
+	entities := []*Entity{}
+	for _, layer := range level.Layers {
+		entities = append(entities, layer.EntitiesByTag(tag)...)
+	}
+	return entities
+}
+
+// Entities returns all Entities across all of the Level's Layers, in the Level's Layer order.
+func (level *Level) Entities() []*Entity {
+	entities := []*Entity{}
+	for _, layer := range level.Layers {
+		entities = append(entities, layer.Entities...)
+	}
+	return entities
+}
+
+// EntitiesByIdentifier returns the Entities across all of the Level's Layers that have the identifier (name) specified.
+// If no Entities have the identifier, an empty slice is returned.
+func (level *Level) EntitiesByIdentifier(identifier string) []*Entity {
+	entities := []*Entity{}
+	for _, layer := range level.Layers {
+		for _, entity := range layer.Entities {
+			if entity.Identifier == identifier {
+				entities = append(entities, entity)
+			}
+		}
+	}
+	return entities
+}
+
+// EntitiesByProperty returns the Entities across all of the Level's Layers that have a Property with the identifier given set to the value given.
+// See Property.Equals() for how values are compared.
+func (level *Level) EntitiesByProperty(identifier string, value interface{}) []*Entity {
+	entities := []*Entity{}
+	for _, entity := range level.Entities() {
+		if prop := entity.PropertyByIdentifier(identifier); prop != nil && prop.Equals(value) {
+			entities = append(entities, entity)
+		}
+	}
+	return entities
+}
+
 // PropertyByIdentifier returns a Property by its Identifier string (name).
 func (level *Level) PropertyByIdentifier(id string) *Property {
 
+	var prop *Property
+
 	for _, p := range level.Properties {
 		if p.Identifier == id {
-			return p
+			prop = p
+			break
 		}
 	}
-	return nil
 
+	if level.Project != nil {
+		return propertyWithDefault(level.Project, prop, level.Project.LevelFieldDefinitions, id)
+	}
+
+	return prop
+
+}
+
+// IntProperty returns the value of the Level's Property with the Identifier given as an int, or the fallback given if the Property doesn't
+// exist, is null, or isn't a number. This is useful for optional tuning values set on Levels.
+func (level *Level) IntProperty(id string, fallback int) int {
+	return propertyInt(level.PropertyByIdentifier(id), fallback)
+}
+
+// FloatProperty returns the value of the Level's Property with the Identifier given as a float64, or the fallback given if the Property
+// doesn't exist, is null, or isn't a number.
+func (level *Level) FloatProperty(id string, fallback float64) float64 {
+	return propertyFloat(level.PropertyByIdentifier(id), fallback)
+}
+
+// BoolProperty returns the value of the Level's Property with the Identifier given as a bool, or the fallback given if the Property doesn't
+// exist, is null, or isn't a boolean.
+func (level *Level) BoolProperty(id string, fallback bool) bool {
+	return propertyBool(level.PropertyByIdentifier(id), fallback)
+}
+
+// StringProperty returns the value of the Level's Property with the Identifier given as a string (i.e. for String, Multilines, Enum, and
+// FilePath Properties), or the fallback given if the Property doesn't exist, is null, or isn't a string.
+func (level *Level) StringProperty(id string, fallback string) string {
+	return propertyString(level.PropertyByIdentifier(id), fallback)
+}
+
+// ColorProperty returns the value of the Level's Property with the Identifier given as a color.Color, or the fallback given if the Property
+// doesn't exist, is null, or isn't a color.
+func (level *Level) ColorProperty(id string, fallback color.Color) color.Color {
+	return propertyColor(level.PropertyByIdentifier(id), fallback)
 }
 
 // Project represents a full LDtk Project, allowing you access to the Levels within as well as some project-level properties.
 type Project struct {
-	WorldLayout       string
-	WorldGridWidth    int
-	WorldGridHeight   int
-	BGColorString     string      `json:"defaultLevelBgColor"`
-	BGColor           color.Color `json:"-"`
-	JSONVersion       string
-	Levels            []*Level
-	Tilesets          []*Tileset
-	IntGridNames      []string
-	EntityDefinitions []*EntityDefinition
+	WorldLayout           string
+	WorldGridWidth        int
+	WorldGridHeight       int
+	BGColorString         string      `json:"defaultLevelBgColor"`
+	BGColor               color.Color `json:"-"`
+	JSONVersion           string
+	Levels                []*Level
+	Worlds                []*World `json:"worlds"` // The Worlds of the Project, if it has multiple Worlds (see ProjectFlagMultiWorlds); their Levels are also in the Project's Levels
+	Tilesets              []*Tileset
+	IntGridNames          []string
+	EntityDefinitions     []*EntityDefinition
+	LayerDefinitions      []*LayerDefinition
+	LevelFieldDefinitions []*FieldDefinition // Definitions of the custom fields (Properties) of Levels
+	TableOfContents       []*TOCEntry        `json:"toc"` // Instances of Entities flagged to be exported to the table of contents, across all Levels
+	CustomCommands        []*CustomCommand   // Custom commands defined in the Project
+	Properties            []*Property        `json:"fieldInstances"` // The custom Properties defined on the Project itself (i.e. global game settings)
+	Flags                 []string           // Options enabled for the Project in LDtk (see the ProjectFlag constants)
+	ImageExportMode       string             `json:"imageExportMode"` // Which PNG images LDtk exports when saving the Project; can be compared using ImageExportMode constants
+	PNGFilePattern        string             `json:"pngFilePattern"`  // The file name pattern of the exported PNG images set in LDtk; empty if the default pattern is used
+	ExternalEnumPaths     []string           `json:"-"`               // Paths of the files (i.e. CastleDB or JSON files) the Project's external enums are imported from, relative to the project file
+	Path                  string             `json:"-"`               // Path to the project file, if the Project was loaded using Open; slash-separated
+	UsePropertyDefaults   bool               `json:"-"`               // If true, PropertyByIdentifier on Levels and Entities returns the default value set in LDtk for Properties that weren't set (see the UsePropertyDefaults LoadOption)
 	// JSONData    string
+	tilesetsByUID   map[int]*Tileset
+	entityDefsByUID map[int]*EntityDefinition
+	sourceHash      [sha256.Size]byte
+	unknownFields   unknownFields
+	fileSystem      fs.FS
+	dir             string
+	subscriptions   []propertySubscription
+	allocator       Allocator
+}
+
+// PropertyByIdentifier returns a Property defined on the Project itself by its Identifier string (name), or nil if one isn't found.
+func (project *Project) PropertyByIdentifier(id string) *Property {
+	for _, p := range project.Properties {
+		if p.Identifier == id {
+			return p
+		}
+	}
+	return nil
+}
+
+// HasFlag returns true if the option given is enabled for the Project in LDtk (i.e. ProjectFlagMultiWorlds).
+func (project *Project) HasFlag(flag string) bool {
+	for _, f := range project.Flags {
+		if f == flag {
+			return true
+		}
+	}
+	return false
+}
+
+// WorldDepths returns the distinct WorldDepths of the Project's Levels, in ascending order (from the bottom to the top).
+func (project *Project) WorldDepths() []int {
+
+	depths := []int{}
+	found := map[int]bool{}
+
+	for _, level := range project.Levels {
+		if !found[level.WorldDepth] {
+			found[level.WorldDepth] = true
+			depths = append(depths, level.WorldDepth)
+		}
+	}
+
+	sort.Ints(depths)
+
+	return depths
+
+}
+
+// FileSystem returns the file system the Project was loaded from (i.e. an OverlayFS, so that tileset images are loaded with the same overrides
+// as the project file), or nil if it wasn't loaded using Open.
+func (project *Project) FileSystem() fs.FS {
+	return project.fileSystem
 }
 
 // LevelByPosition returns the level that "contains" the point indicated by the X and Y values given, or nil if one isn't found.
-// (Note that the world position is displayed in LDTK at the bottom in the status bar.)
+// (Note that the world position is displayed in LDTK at the bottom in the status bar.) Like Level.Contains, a Level's right and bottom edges
+// belong to the Levels next to it, so a point on an edge shared by two Levels is always in the same one. If Levels overlap, the first one in
+// the Project's Levels is returned. The Levels of all of the Project's Worlds are searched; in Projects with multiple Worlds, use
+// World.LevelAt or LevelAtWorld instead.
 func (project *Project) LevelByPosition(x, y int) *Level {
 
 	for _, level := range project.Levels {
 
-		rect := image.Rect(level.WorldX, level.WorldY, level.WorldX+level.Width, level.WorldY+level.Height)
+		if level.Contains(x, y) {
+			return level
+		}
+
+	}
+
+	return nil
+
+}
+
+// WorldGridSize returns the width and height of the cells of the world grid that Levels are aligned to in GridVania layouts, in pixels.
+func (project *Project) WorldGridSize() (int, int) {
+	return project.WorldGridWidth, project.WorldGridHeight
+}
+
+// ToWorldGrid converts the world position given (in pixels) to the cell of the world grid (see WorldGridSize) that contains it. This is
+// useful for GridVania layouts, i.e. to snap a camera to the room the player is in. If the Project doesn't have a world grid, 0, 0 is returned.
+func (project *Project) ToWorldGrid(worldX, worldY int) (int, int) {
+	if project.WorldGridWidth <= 0 || project.WorldGridHeight <= 0 {
+		return 0, 0
+	}
+	return floorDiv(worldX, project.WorldGridWidth), floorDiv(worldY, project.WorldGridHeight)
+}
+
+// FromWorldGrid converts the cell of the world grid given (see WorldGridSize) to the world position of its top-left corner, in pixels. This is
+// useful for GridVania layouts, i.e. to place rooms procedurally so that they're aligned to the world grid.
+func (project *Project) FromWorldGrid(gridX, gridY int) (int, int) {
+	return gridX * project.WorldGridWidth, gridY * project.WorldGridHeight
+}
+
+// LevelAtGrid returns the Level that covers the cell of the world grid given (see WorldGridSize), or nil if no Level does. In GridVania
+// layouts, a Level can cover several cells. In Projects with multiple Worlds, each World has its own grid, so use World.LevelAtGrid instead.
+func (project *Project) LevelAtGrid(gridX, gridY int) *Level {
+	if project.WorldGridWidth <= 0 || project.WorldGridHeight <= 0 {
+		return nil
+	}
+	return project.LevelByPosition(project.FromWorldGrid(gridX, gridY))
+}
+
+// WorldBounds returns the smallest rectangle that contains all of the Project's Levels in the world, in pixels; an empty rectangle is
+// returned if the Project has no Levels. In Projects with multiple Worlds, this covers the Levels of all of them (see World.Bounds).
+func (project *Project) WorldBounds() image.Rectangle {
+	bounds := image.Rectangle{}
+	for i, level := range project.Levels {
+		if i == 0 {
+			bounds = level.WorldBounds()
+		} else {
+			bounds = bounds.Union(level.WorldBounds())
+		}
+	}
+	return bounds
+}
+
+// LevelByIdentifier returns the level that has the identifier specified, or nil if one isn't found.
+func (project *Project) LevelByIdentifier(identifier string) *Level {
+	for _, level := range project.Levels {
+		if level.Identifier == identifier {
+			return level
+		}
+	}
+	return nil
+}
 
-		if rect.Min.X <= x && rect.Min.Y <= y && rect.Max.X >= x && rect.Max.Y >= y {
+// LevelByIID returns the level that has the unique identifier specified, or nil if one isn't found.
+func (project *Project) LevelByIID(iid string) *Level {
+	for _, level := range project.Levels {
+		if level.IID == iid {
 			return level
 		}
+	}
+	return nil
+}
+
+// TilesetByUID returns the Tileset with the UID given, or nil if one isn't found.
+func (project *Project) TilesetByUID(uid int) *Tileset {
+	for _, tileset := range project.Tilesets {
+		if tileset.ID == uid {
+			return tileset
+		}
+	}
+	return nil
+}
+
+func (project *Project) TilesetByIdentifier(identifier string) *Tileset {
+	for _, tileset := range project.Tilesets {
+		if tileset.Identifier == identifier {
+			return tileset
+		}
+	}
+	return nil
+}
+
+// TilesetsByTag returns all Tilesets in the Project that have the tag (category) specified.
+func (project *Project) TilesetsByTag(tag string) []*Tileset {
+	tilesets := []*Tileset{}
+	for _, tileset := range project.Tilesets {
+		if tileset.HasTag(tag) {
+			tilesets = append(tilesets, tileset)
+		}
+	}
+	return tilesets
+}
+
+// EntityByIID returns the Entity by unique identifier specified, or nil if entity isn't found
+func (project *Project) EntityByIID(iid string) *Entity {
+	for _, level := range project.Levels {
+		for _, layer := range level.Layers {
+			for _, entity := range layer.Entities {
+				if entity.IID == iid {
+					return entity
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// EachEntity runs the callback given for each Entity in the Project, along with the Layer and Level containing it. If the callback
+// returns false, iteration stops.
+func (project *Project) EachEntity(function func(entity *Entity, layer *Layer, level *Level) bool) {
+	for _, level := range project.Levels {
+		for _, layer := range level.Layers {
+			for _, entity := range layer.Entities {
+				if !function(entity, layer, level) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// EntityByIdentifier returns the first Entity found in the Project with the identifier (name) specified, or nil if one isn't found.
+// This is useful for finding singular Entities (like a "PlayerStart") anywhere in the world.
+func (project *Project) EntityByIdentifier(identifier string) *Entity {
+	var found *Entity
+	project.EachEntity(func(entity *Entity, layer *Layer, level *Level) bool {
+		if entity.Identifier == identifier {
+			found = entity
+			return false
+		}
+		return true
+	})
+	return found
+}
 
-	}
+// EntitiesByIdentifier returns all Entities in the Project with the identifier (name) specified.
+func (project *Project) EntitiesByIdentifier(identifier string) []*Entity {
+	return project.EntitiesWhere(func(entity *Entity) bool { return entity.Identifier == identifier })
+}
 
-	return nil
+// EntitiesByTag returns all Entities in the Project that have the tag (category) specified.
+func (project *Project) EntitiesByTag(tag string) []*Entity {
+	return project.EntitiesWhere(func(entity *Entity) bool { return entity.HasTag(tag) })
+}
 
+// EntitiesByProperty returns all Entities in the Project that have a Property with the identifier given set to the value given.
+// See Property.Equals() for how values are compared.
+func (project *Project) EntitiesByProperty(identifier string, value interface{}) []*Entity {
+	return project.EntitiesWhere(func(entity *Entity) bool {
+		prop := entity.PropertyByIdentifier(identifier)
+		return prop != nil && prop.Equals(value)
+	})
 }
 
-// LevelByIdentifier returns the level that has the identifier specified, or nil if one isn't found.
-func (project *Project) LevelByIdentifier(identifier string) *Level {
-	for _, level := range project.Levels {
-		if level.Identifier == identifier {
-			return level
+// EntitiesWhere returns all Entities in the Project for which the filter function given returns true.
+func (project *Project) EntitiesWhere(filter func(entity *Entity) bool) []*Entity {
+	entities := []*Entity{}
+	project.EachEntity(func(entity *Entity, layer *Layer, level *Level) bool {
+		if filter(entity) {
+			entities = append(entities, entity)
 		}
-	}
-	return nil
+		return true
+	})
+	return entities
 }
 
-// LevelByIID returns the level that has the unique identifier specified, or nil if one isn't found.
-func (project *Project) LevelByIID(iid string) *Level {
-	for _, level := range project.Levels {
-		if level.IID == iid {
-			return level
+// EntityDefinitionByUID returns the EntityDefinition with the UID specified, or nil if it isn't found.
+func (project *Project) EntityDefinitionByUID(uid int) *EntityDefinition {
+	for _, definition := range project.EntityDefinitions {
+		if definition.UID == uid {
+			return definition
 		}
 	}
 	return nil
 }
 
-func (project *Project) TilesetByIdentifier(identifier string) *Tileset {
-	for _, tileset := range project.Tilesets {
-		if tileset.Identifier == identifier {
-			return tileset
+// LayerDefinitionByUID returns the LayerDefinition with the UID given, or nil if one isn't found.
+func (project *Project) LayerDefinitionByUID(uid int) *LayerDefinition {
+	for _, definition := range project.LayerDefinitions {
+		if definition.UID == uid {
+			return definition
 		}
 	}
 	return nil
 }
 
-// EntityByIID returns the Entity by unique identifier specified, or nil if entity isn't found
-func (project *Project) EntityByIID(iid string) *Entity {
-	for _, level := range project.Levels {
-		for _, layer := range level.Layers {
-			for _, entity := range layer.Entities {
-				if entity.IID == iid {
-					return entity
-				}
-			}
+// LayerDefinitionByIdentifier returns the LayerDefinition with the identifier (name) given, or nil if one isn't found.
+func (project *Project) LayerDefinitionByIdentifier(identifier string) *LayerDefinition {
+	for _, definition := range project.LayerDefinitions {
+		if definition.Identifier == identifier {
+			return definition
 		}
 	}
 	return nil
 }
 
+// LevelFieldDefinitionByIdentifier returns the definition of the custom field of Levels with the Identifier given, or nil if one isn't found.
+func (project *Project) LevelFieldDefinitionByIdentifier(identifier string) *FieldDefinition {
+	return fieldDefinitionByIdentifier(project.LevelFieldDefinitions, identifier)
+}
+
 // EntityDefinitionByIdentifier returns the EntityDefinition by unique identifier specified, or nil if entity isn't found
 func (project *Project) EntityDefinitionByIdentifier(identifier string) *EntityDefinition {
 	for _, definition := range project.EntityDefinitions {
@@ -480,177 +2230,594 @@ func (project *Project) EntityDefinitionByIdentifier(identifier string) *EntityD
 	return nil
 }
 
-// Open loads the LDtk project from the filepath specified using the file system provided.
-// Open returns the Project and an error should the loading process fail (unable to find the file, unable to deserialize the JSON, etc).
-func Open(filepath string, fileSystem fs.FS) (*Project, error) {
+// AddLevel adds a Level created at runtime (i.e. one assembled out of other Levels) to the Project. The Level (along with its Layers, Entities,
+// and Tiles) is linked to the Project's definitions the same way loaded Levels are, and entity references are resolved again afterwards.
+// Tilesets and definitions the Level relies on should be added to the Project beforehand.
+func (project *Project) AddLevel(level *Level) {
+
+	project.setupDefinitions()
+
+	project.Levels = append(project.Levels, level)
+
+	project.setupLevel(level)
+
+	project.resolveReferences()
+
+	for _, world := range project.Worlds {
+		world.UpdateBounds()
+	}
+
+}
+
+// Warnings returns descriptions of any data in the Project that LDtk-Go didn't understand and ignored (i.e. data from a newer version of LDtk).
+// If there's nothing to report, an empty slice is returned.
+func (project *Project) Warnings() []string {
+	warnings := []string{}
+	for _, level := range project.Levels {
+		for _, layer := range level.Layers {
+			if layer.unknownFlips > 0 {
+				warnings = append(warnings, fmt.Sprintf("level %s, layer %s: %d tile(s) have unknown flip bits set, which were ignored", level.Identifier, layer.Identifier, layer.unknownFlips))
+			}
+		}
+	}
+	return warnings
+}
+
+// Open loads the LDtk project from the filepath specified using the file system provided. If the file system is nil, the project is opened
+// from the OS file system instead. If the project saves its Levels in separate files, they're loaded from the same file system and directory
+// as the project file. Open returns the Project and an error should the loading process fail (unable to find the file, unable to deserialize
+// the JSON, etc).
+func Open(filepath string, fileSystem fs.FS, options ...LoadOption) (*Project, error) {
+	return OpenContext(context.Background(), filepath, fileSystem, options...)
+}
+
+// ReadFrom reads the LDtk project from the io.Reader given. Returns the Project and an error should there be an error in the loading process.
+func ReadFrom(reader io.Reader, options ...LoadOption) (*Project, error) {
+	return ReadContext(context.Background(), reader, options...)
+}
+
+// Read reads the LDtk project using the specified slice of bytes, which can be compressed (using gzip, or any format registered using
+// RegisterDecompressor). Returns the Project and an error should there be an error in the loading process (unable to properly deserialize the JSON).
+func Read(data []byte, options ...LoadOption) (*Project, error) {
+	return read(data, newLoadConfig(options))
+}
+
+// ReadWithProgress reads the LDtk project using the specified slice of bytes, like Read, calling the progress function given as each stage of
+// loading progresses (see OnStage), so that a loading bar can be drawn instead of freezing at startup for large projects.
+func ReadWithProgress(data []byte, progress func(stage string, done, total int), options ...LoadOption) (*Project, error) {
+	return read(data, newLoadConfig(append(options, OnStage(progress))))
+}
+
+func read(data []byte, config *loadConfig) (*Project, error) {
 
-	file, err := fileSystem.Open(filepath)
+	data, err := decompressBytes(data)
 
 	if err != nil {
 		return nil, err
 	}
 
-	bytes, err := io.ReadAll(file)
+	if data, err = migrateProject(data); err != nil {
+		return nil, err
+	}
 
-	if err != nil {
+	if config.streamed() {
+		return readStream(bytes.NewReader(data), int64(len(data)), config)
+	}
+
+	// Everything is decoded in a single pass (see decode.go); afterwards, we just need to link everything together.
+	project := &Project{IntGridNames: []string{}, UsePropertyDefaults: config.usePropertyDefaults, allocator: config.allocator}
+
+	config.stage(StageDecode, 0, len(data))
+
+	if config.lazyLevels {
+		if err := project.unmarshalLazy(data); err != nil {
+			return nil, err
+		}
+	} else if config.parallelLevels > 0 {
+		if err := project.unmarshalParallel(data, config.parallelLevels); err != nil {
+			return nil, err
+		}
+	} else if err := json.Unmarshal(data, project); err != nil {
 		return nil, err
 	}
 
-	project, err := Read(bytes)
+	config.stage(StageDecode, len(data), len(data))
+
+	if config.strictSchema {
+		project.unknownFields = unknownFields{}
+		if err := project.unknownFields.check(data, reflect.TypeOf(Project{})); err != nil {
+			return nil, err
+		}
+	}
+
+	project.setupBGColor()
+
+	project.setupLevels(project.Levels, config, project.setupLoadedLevel)
+
+	// Resolve references between Levels now that they've all been loaded.
+	config.stage(StageReferences, 0, 1)
+	project.resolveReferences()
+	config.stage(StageReferences, 1, 1)
+
+	return project, nil
+
+}
+
+// setupLevels sets up the Levels given using the setup function given, reporting the progress to the OnStage function, if there is one.
+func (project *Project) setupLevels(levels []*Level, config *loadConfig, setup func(level *Level)) {
+
+	totalLayers := 0
+	for _, level := range levels {
+		totalLayers += len(level.Layers)
+	}
+
+	config.stage(StageLevels, 0, len(levels))
+	config.stage(StageLayers, 0, totalLayers)
+
+	layers := 0
 
-	return project, err
+	for i, level := range levels {
+		setup(level)
+		layers += len(level.Layers)
+		config.stage(StageLevels, i+1, len(levels))
+		config.stage(StageLayers, layers, totalLayers)
+	}
 
 }
 
-// Read reads the LDtk project using the specified slice of bytes. Returns the Project and an error should there be an error in the loading process (unable to properly deserialize the JSON).
-func Read(data []byte) (*Project, error) {
+// readStream reads the LDtk project from the io.Reader given one top-level value at a time, setting up each Level as soon as it's decoded
+// so that the entire JSON document never has to be held in memory at once. total is the size of the data in bytes, or -1 if it isn't known.
+// If decoding progress is reported, the Levels are set up once they've all been decoded instead, so that their setup progress can be reported.
+func readStream(reader io.Reader, total int64, config *loadConfig) (*Project, error) {
+
+	project := &Project{IntGridNames: []string{}, UsePropertyDefaults: config.usePropertyDefaults, allocator: config.allocator}
+
+	decoder := json.NewDecoder(reader)
+
+	if err := expectDelim(decoder, '{'); err != nil {
+		return nil, err
+	}
+
+	interner := stringInterner{}
+	others := map[string]json.RawMessage{}
+	pendingLevels := []*Level{}
+	defsLoaded := false
+
+	setupLevel := func(level *Level) {
+		project.setupLoadedLevel(level)
+		if config.lowMemory {
+			interner.internLevel(level)
+		}
+	}
+
+	decoded := func() {
+		config.stage(StageDecode, int(decoder.InputOffset()), int(total))
+	}
+
+	decodedLevel := func(level *Level) {
+		decoded()
+		// Levels need the definitions to be set up, so if they come first in the file, we have to hold onto them until they do.
+		if defsLoaded && config.onStage == nil {
+			setupLevel(level)
+		} else {
+			pendingLevels = append(pendingLevels, level)
+		}
+	}
+
+	config.stage(StageDecode, 0, int(total))
+
+	for decoder.More() {
+
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		key, _ := token.(string)
+
+		switch key {
+
+		case "levels":
+
+			if err := expectDelim(decoder, '['); err != nil {
+				return nil, err
+			}
+
+			for decoder.More() {
+				level := &Level{}
+				if err := decoder.Decode(level); err != nil {
+					return nil, err
+				}
+				project.Levels = append(project.Levels, level)
+				decodedLevel(level)
+			}
+
+			if err := expectDelim(decoder, ']'); err != nil {
+				return nil, err
+			}
+
+		case "worlds":
+
+			// Worlds are decoded whole, as a Project with multiple Worlds usually saves its Levels in separate files anyway.
+			if err := decoder.Decode(&project.Worlds); err != nil {
+				return nil, err
+			}
+
+			if err := project.validateWorlds(); err != nil {
+				return nil, err
+			}
+
+			count := len(project.Levels)
+			project.addWorldLevels()
+
+			for _, level := range project.Levels[count:] {
+				decodedLevel(level)
+			}
+
+		case "defs":
+
+			defs := &projectDefinitions{}
+			if err := decoder.Decode(defs); err != nil {
+				return nil, err
+			}
+
+			if err := defs.validate(); err != nil {
+				return nil, err
+			}
+
+			project.applyDefinitions(defs)
+			defsLoaded = true
+			decoded()
+
+		default:
+
+			raw := json.RawMessage{}
+			if err := decoder.Decode(&raw); err != nil {
+				return nil, err
+			}
+			others[key] = raw
+
+		}
+
+		if defsLoaded && config.onStage == nil {
+			for _, level := range pendingLevels {
+				setupLevel(level)
+			}
+			pendingLevels = nil
+		}
+
+	}
+
+	// The closing brace (and any whitespace after it) isn't read by the decoder, so decoding is reported as finished explicitly.
+	if total >= 0 {
+		config.stage(StageDecode, int(total), int(total))
+	} else {
+		decoded()
+	}
 
-	project := &Project{IntGridNames: []string{}}
+	if !defsLoaded {
+		project.applyDefinitions(&projectDefinitions{})
+	}
 
-	err := json.Unmarshal(data, project)
+	project.setupLevels(pendingLevels, config, setupLevel)
 
+	// The remaining top-level values are small, so they can simply be decoded into the Project as usual.
+	remaining, err := json.Marshal(others)
 	if err != nil {
 		return nil, err
 	}
 
-	dataStr := string(data)
+	if err := json.Unmarshal(remaining, project); err != nil {
+		return nil, err
+	}
+
+	project.setupBGColor()
+
+	config.stage(StageReferences, 0, 1)
+	project.resolveReferences()
+	config.stage(StageReferences, 1, 1)
+
+	return project, nil
+
+}
 
-	// Additional convenience fields
+func expectDelim(decoder *json.Decoder, delim json.Delim) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if token != delim {
+		return fmt.Errorf("invalid LDtk JSON: expected %q, got %v", delim, token)
+	}
+	return nil
+}
 
+// setupBGColor fills in the Project's default background color.
+func (project *Project) setupBGColor() {
 	if project.BGColorString != "" {
 		project.BGColor, _ = parseHexColorFast(project.BGColorString)
 	} else {
 		project.BGColor = color.RGBA{}
 	}
+}
 
-	tilesetByUID := map[int]*Tileset{}
+// applyDefinitions sets the Project's tilesets, entity definitions, and IntGrid value names from the decoded definitions.
+func (project *Project) applyDefinitions(defs *projectDefinitions) {
 
-	for _, tilesetDef := range gjson.Get(dataStr, `defs.tilesets`).Array() {
+	project.Tilesets = defs.Tilesets
 
-		newTS := &Tileset{CustomData: map[int]string{}, Enums: map[int]EnumSet{}}
-		json.Unmarshal([]byte(tilesetDef.Raw), newTS)
-		newTS.Path = filepath.FromSlash(newTS.Path)
-		project.Tilesets = append(project.Tilesets, newTS)
+	project.EntityDefinitions = defs.Entities
+	if project.EntityDefinitions == nil {
+		project.EntityDefinitions = []*EntityDefinition{}
+	}
 
-		ts := project.TilesetByIdentifier(tilesetDef.Get("identifier").String())
-		for _, enumSet := range tilesetDef.Get("enumTags").Array() {
-			enumName := enumSet.Get("enumValueId").String()
-			enumTiles := enumSet.Get("tileIds").Array()
-			for _, idNumber := range enumTiles {
-				id := int(idNumber.Int())
-				if _, exists := ts.Enums[id]; !exists {
-					ts.Enums[id] = EnumSet{}
-				}
-				ts.Enums[id] = append(ts.Enums[id], enumName)
-			}
-		}
+	project.LayerDefinitions = defs.Layers
+	if project.LayerDefinitions == nil {
+		project.LayerDefinitions = []*LayerDefinition{}
+	}
+
+	project.LevelFieldDefinitions = defs.Levels
+	if project.LevelFieldDefinitions == nil {
+		project.LevelFieldDefinitions = []*FieldDefinition{}
+	}
+
+	project.ExternalEnumPaths = nil
+	enumPaths := map[string]bool{}
 
-		for _, customData := range tilesetDef.Get("customData").Array() {
-			newTS.CustomData[int(customData.Get("tileId").Int())] = customData.Get("data").String()
+	// Each external enum lists the file it's imported from, so enums imported from the same file share the path.
+	for _, enum := range defs.ExternalEnums {
+		if enum.ExternalPath != "" && !enumPaths[enum.ExternalPath] {
+			enumPaths[enum.ExternalPath] = true
+			project.ExternalEnumPaths = append(project.ExternalEnumPaths, enum.ExternalPath)
 		}
+	}
 
-		tilesetByUID[newTS.ID] = newTS
+	project.IntGridNames = []string{}
 
+	for _, layerDef := range defs.Layers {
+		if layerDef.Type == LayerTypeIntGrid {
+			for _, value := range layerDef.IntGridValues {
+				project.IntGridNames = append(project.IntGridNames, value.Identifier)
+			}
+		}
 	}
 
-	for index, level := range project.Levels {
+	project.setupDefinitions()
+
+}
+
+// setupDefinitions indexes the Project's tilesets and entity definitions and fills in their convenience fields.
+func (project *Project) setupDefinitions() {
+
+	project.tilesetsByUID = map[int]*Tileset{}
+
+	for _, tileset := range project.Tilesets {
+		project.tilesetsByUID[tileset.ID] = tileset
+	}
 
-		level.Project = project
+	project.entityDefsByUID = map[int]*EntityDefinition{}
 
-		if level.BGColorString != "" {
-			level.BGColor, _ = parseHexColorFast(level.BGColorString)
+	for _, entityDefinition := range project.EntityDefinitions {
+		if entityDefinition.TileRect != nil {
+			entityDefinition.TileRect.Tileset = project.tilesetsByUID[entityDefinition.TileRect.TilesetUID]
+		}
+		if entityDefinition.ColorString != "" {
+			entityDefinition.Color, _ = parseHexColorFast(entityDefinition.ColorString)
 		} else {
-			level.BGColor = color.RGBA{}
+			entityDefinition.Color = color.RGBA{}
+		}
+		project.entityDefsByUID[entityDefinition.UID] = entityDefinition
+	}
+
+	for _, layerDefinition := range project.LayerDefinitions {
+		for _, value := range layerDefinition.IntGridValues {
+			if value.ColorString != "" {
+				value.Color, _ = parseHexColorFast(value.ColorString)
+			} else {
+				value.Color = color.RGBA{}
+			}
 		}
+	}
 
-		// Parse level JSON data for background info
-		levelData := gjson.Get(dataStr, "levels."+strconv.Itoa(index))
+}
 
-		if levelData.Get("bgRelPath").Exists() && levelData.Get("bgRelPath").String() != "" {
+// setupLoadedLevel sets up a Level that was just decoded, moving its contents into memory from the Project's Allocator first, if it has one.
+func (project *Project) setupLoadedLevel(level *Level) {
+	project.allocateLevel(level)
+	project.setupLevel(level)
+}
 
-			bgPos := levelData.Get("__bgPos")
-			scale := bgPos.Get("scale").Array()
-			cropRect := bgPos.Get("cropRect").Array()
+// setupLevel fills in the convenience fields of a Level (and its Layers and Entities) after it's been deserialized.
+func (project *Project) setupLevel(level *Level) {
 
-			level.BGImage = &BGImage{
-				Path:   levelData.Get("bgRelPath").String(),
-				ScaleX: scale[0].Float(),
-				ScaleY: scale[1].Float(),
-				CropRect: []float64{
-					cropRect[0].Float(),
-					cropRect[1].Float(),
-					cropRect[2].Float(),
-					cropRect[3].Float(),
-				},
-			}
+	level.Project = project
 
-		}
+	for _, prop := range level.Properties {
+		prop.project = project
+	}
 
-		for layerIndex, layer := range level.Layers {
+	if level.BGColorString != "" {
+		level.BGColor, _ = parseHexColorFast(level.BGColorString)
+	} else {
+		level.BGColor = color.RGBA{}
+	}
 
-			layer.level = level
+	for _, layer := range level.Layers {
 
-			for i, integer := range levelData.Get("layerInstances." + strconv.Itoa(layerIndex) + ".intGridCsv").Array() {
+		layer.level = level
 
-				if integer.Int() != 0 {
+		for _, e := range layer.Entities {
+			if e.TileRect != nil {
+				e.TileRect.Tileset = project.tilesetsByUID[e.TileRect.TilesetUID]
+			}
 
-					newI := &Integer{
-						Value: int(integer.Int()),
-						ID:    i,
-					}
+			e.level = level
+			e.layer = layer
 
-					y := int(float64(newI.ID) / float64(layer.CellWidth))
-					x := newI.ID - y*layer.CellWidth
-					newI.Position = []int{x * layer.GridSize, y * layer.GridSize}
+			if e.SmartColorString != "" {
+				e.SmartColor, _ = parseHexColorFast(e.SmartColorString)
+			} else if def := project.entityDefsByUID[e.DefUID]; def != nil {
+				e.SmartColor = def.Color
+			} else {
+				e.SmartColor = color.RGBA{}
+			}
 
-					layer.IntGrid = append(layer.IntGrid, newI)
+			if len(e.GridPosition) < 2 && len(e.Position) >= 2 && layer.GridSize > 0 {
+				gx, gy := layer.ToGridPosition(e.Position[0], e.Position[1])
+				e.GridPosition = []int{gx, gy}
+			}
 
-				}
+			// Older projects don't export __tags on instances, so we fall back to the definition's tags.
+			if def := project.entityDefsByUID[e.DefUID]; e.Tags == nil && def != nil {
+				e.Tags = def.Tags
+			}
 
+			for _, prop := range e.Properties {
+				prop.project = project
 			}
+		}
 
-			for _, e := range layer.Entities {
-				if e.TileRect != nil {
-					e.TileRect.Tileset = tilesetByUID[e.TileRect.TilesetUID]
-				}
+		layer.Tileset = project.tilesetsByUID[layer.TilesetUID]
 
-				e.level = level
+		layer.unknownFlips = 0
 
-				for _, prop := range e.Properties {
-					prop.project = project
-				}
+		for _, tile := range layer.Tiles {
+			tile.layer = layer
+			if tile.Flip.Unknown() != 0 {
+				layer.unknownFlips++
+			}
+		}
+
+		for _, tile := range layer.AutoTiles {
+			tile.layer = layer
+			if tile.Flip.Unknown() != 0 {
+				layer.unknownFlips++
 			}
+		}
+
+	}
+
+}
+
+// ResolvePath returns the path given (i.e. a Tileset's or background image's Path, which LDtk stores relative to the project file) relative to
+// the root of the file system the Project was loaded from, rather than to the project file. If the Project wasn't loaded using Open, the path
+// is returned as-is.
+func (project *Project) ResolvePath(relPath string) string {
+	if project.dir == "" || project.dir == "." {
+		return relPath
+	}
+	return path.Join(project.dir, filepath.ToSlash(relPath))
+}
+
+// loadExternalLevels reads any Levels the Project stores in separate files from the file system given, relative to the project file.
+// An error naming the file is returned if any of the files can't be read (i.e. if it doesn't exist), rather than leaving its Level empty.
+func (project *Project) loadExternalLevels(ctx context.Context, fileSystem fs.FS) error {
+
+	loaded := false
+
+	for _, level := range project.Levels {
+
+		// Lazily loaded Levels are read from their files when they're needed instead.
+		if level.ExternalPath == "" || level.Layers != nil || level.pending {
+			continue
+		}
 
-			layer.Tileset = tilesetByUID[layer.TilesetUID]
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		levelPath := path.Join(project.dir, filepath.ToSlash(level.ExternalPath))
+
+		data, err := fs.ReadFile(fileSystem, levelPath)
+
+		if err != nil {
+			return fmt.Errorf("%s: %w", levelPath, err)
+		}
 
+		if err := project.readExternalLevel(level, data); err != nil {
+			return fmt.Errorf("%s: %w", levelPath, err)
 		}
 
+		loaded = true
+
 	}
 
-	for _, layerDef := range gjson.Get(dataStr, `defs.layers`).Array() {
-		if layerDef.Get("type").String() == "IntGrid" {
-			for _, value := range layerDef.Get("intGridValues").Array() {
-				project.IntGridNames = append(project.IntGridNames, value.Get("identifier").String())
-			}
+	if loaded {
+		project.resolveReferences()
+	}
+
+	return nil
+
+}
+
+// readExternalLevel reads the data of an external level file (.ldtkl) into the Level given, replacing its contents.
+func (project *Project) readExternalLevel(level *Level, data []byte) error {
+
+	data, err := decompressBytes(data)
+
+	if err != nil {
+		return err
+	}
+
+	if data, err = project.migrateLevelData(data); err != nil {
+		return err
+	}
+
+	loaded := &Level{}
+
+	if err := json.Unmarshal(data, loaded); err != nil {
+		return err
+	}
+
+	if project.unknownFields != nil {
+		if err := project.unknownFields.check(data, reflect.TypeOf(Level{})); err != nil {
+			return err
 		}
 	}
 
-	entityDefinitions := []*EntityDefinition{}
-	defsResult := gjson.Get(dataStr, `defs.entities`).Array()
-	for _, def := range defsResult {
-		b := []byte(def.Raw)
-		entityDefinition := &EntityDefinition{}
-		if err := json.Unmarshal(b, &entityDefinition); err != nil {
-			return nil, err
+	// The level file doesn't store where the Level is in the Project.
+	loaded.ExternalPath = level.ExternalPath
+	loaded.world = level.world
+	loaded.lazy = level.lazy
+	*level = *loaded
+
+	project.setupLoadedLevel(level)
+
+	return nil
+
+}
+
+// resolveReferences resolves references that can point across Levels (like entity references), once all Levels have been set up.
+func (project *Project) resolveReferences() {
+
+	for _, prop := range project.Properties {
+		prop.project = project
+		prop.resolveEntityRefs()
+	}
+
+	for _, level := range project.Levels {
+		for _, prop := range level.Properties {
+			prop.resolveEntityRefs()
 		}
-		if entityDefinition.TileRect != nil {
-			entityDefinition.TileRect.Tileset = tilesetByUID[entityDefinition.TileRect.TilesetUID]
+		for _, entity := range level.Entities() {
+			for _, prop := range entity.Properties {
+				prop.resolveEntityRefs()
+			}
 		}
-		entityDefinitions = append(entityDefinitions, entityDefinition)
 	}
-	project.EntityDefinitions = entityDefinitions
 
-	return project, err
+	// Fill in the world positions of table of contents instances that only contain IIDs.
+	for _, entry := range project.TableOfContents {
+		for _, instance := range entry.Instances {
+			if instance.Width != 0 || instance.Height != 0 {
+				continue
+			}
+			if entity := project.TOCEntity(instance); entity != nil {
+				instance.WorldX, instance.WorldY = entity.WorldPosition()
+				instance.Width, instance.Height = entity.Width, entity.Height
+			}
+		}
+	}
 
 }
 
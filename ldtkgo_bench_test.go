@@ -0,0 +1,130 @@
+package ldtkgo
+
+import (
+	"os"
+	"testing"
+
+	"github.com/solarlune/ldtkgo/internal/gen"
+)
+
+// benchProject is the project the loading benchmarks read; it's a copy of the example project.
+const benchProject = "testdata/example/example.ldtk"
+
+func readBenchProject(b *testing.B) []byte {
+	data, err := os.ReadFile(benchProject)
+	if err != nil {
+		b.Fatal(err)
+	}
+	return data
+}
+
+func BenchmarkRead(b *testing.B) {
+
+	data := readBenchProject(b)
+
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := Read(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+}
+
+func BenchmarkOpen(b *testing.B) {
+
+	fileSystem := os.DirFS("testdata/example")
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := Open("example.ldtk", fileSystem); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+}
+
+func BenchmarkReadLowMemory(b *testing.B) {
+
+	data := readBenchProject(b)
+
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := Read(data, LowMemory()); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+}
+
+// generatedBenchProject generates the large project that the lookup benchmarks use, with 100 Levels of 64x36 cells.
+func generatedBenchProject(b *testing.B) []byte {
+	options := gen.DefaultOptions()
+	options.Levels = 100
+	data, err := gen.Project(options)
+	if err != nil {
+		b.Fatal(err)
+	}
+	return data
+}
+
+func BenchmarkReadGenerated(b *testing.B) {
+
+	data := generatedBenchProject(b)
+
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := Read(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+}
+
+func BenchmarkTileAt(b *testing.B) {
+
+	project, err := Read(generatedBenchProject(b))
+
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	layer := project.Levels[0].LayerByIdentifier("Tiles")
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		cell := i % (layer.CellWidth * layer.CellHeight)
+		layer.TileAt(cell%layer.CellWidth, cell/layer.CellWidth)
+	}
+
+}
+
+func BenchmarkIntegerAt(b *testing.B) {
+
+	project, err := Read(generatedBenchProject(b))
+
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	layer := project.Levels[0].LayerByIdentifier("Collision")
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		cell := i % (layer.CellWidth * layer.CellHeight)
+		layer.IntegerAt(cell%layer.CellWidth, cell/layer.CellWidth)
+	}
+
+}
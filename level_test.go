@@ -0,0 +1,49 @@
+package ldtkgo
+
+import "testing"
+
+// TestLevelByPositionEdges checks that points on the edges between Levels are found in the same Level by every lookup.
+func TestLevelByPositionEdges(t *testing.T) {
+
+	project, err := Read([]byte(`{"jsonVersion":"1.5.3","worldLayout":"GridVania","worldGridWidth":16,"worldGridHeight":16,
+		"defs":{"tilesets":[],"layers":[],"entities":[]},"levels":[
+		{"identifier":"Left","iid":"left","worldX":0,"worldY":0,"pxWid":16,"pxHei":16,"layerInstances":[]},
+		{"identifier":"Right","iid":"right","worldX":16,"worldY":0,"pxWid":16,"pxHei":16,"layerInstances":[]}
+	]}`))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		x, y int
+		want string // The identifier of the Level containing the point, or "" for none
+	}{
+		{x: 0, y: 0, want: "Left"},
+		{x: 15, y: 15, want: "Left"},
+		{x: 16, y: 0, want: "Right"},
+		{x: 31, y: 15, want: "Right"},
+		{x: 32, y: 0},
+		{x: 0, y: 16},
+		{x: -1, y: 0},
+	}
+
+	for _, test := range tests {
+
+		got := ""
+		if level := project.LevelByPosition(test.x, test.y); level != nil {
+			got = level.Identifier
+		}
+
+		if got != test.want {
+			t.Errorf("LevelByPosition(%d, %d) is %q, not %q", test.x, test.y, got, test.want)
+		}
+
+		gridX, gridY := project.ToWorldGrid(test.x, test.y)
+		if level := project.LevelAtGrid(gridX, gridY); test.want != "" && (level == nil || level.Identifier != test.want) {
+			t.Errorf("LevelAtGrid(%d, %d) is %v, not %q", gridX, gridY, level, test.want)
+		}
+
+	}
+
+}
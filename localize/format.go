@@ -0,0 +1,228 @@
+package localize
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// csvHeader is the header row of the CSV files written by WriteCSV.
+var csvHeader = []string{"key", "text", "level", "entity", "field"}
+
+// WriteJSON writes the Entries given to the writer as a JSON object mapping their keys to their text, which can be read back using ReadJSON.
+func WriteJSON(writer io.Writer, entries []Entry) error {
+
+	table := Table{}
+
+	for _, entry := range entries {
+		table[entry.Key] = entry.Text
+	}
+
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "\t")
+	encoder.SetEscapeHTML(false)
+
+	return encoder.Encode(table)
+
+}
+
+// ReadJSON reads a Table from a JSON object mapping keys to translations, as written by WriteJSON.
+func ReadJSON(reader io.Reader) (Table, error) {
+
+	table := Table{}
+
+	if err := json.NewDecoder(reader).Decode(&table); err != nil {
+		return nil, err
+	}
+
+	return table, nil
+
+}
+
+// WriteCSV writes the Entries given to the writer as CSV, with a header row followed by a row for each Entry, with the columns "key", "text",
+// "level", "entity", and "field". It can be read back using ReadCSV once the text column has been translated.
+func WriteCSV(writer io.Writer, entries []Entry) error {
+
+	csvWriter := csv.NewWriter(writer)
+
+	if err := csvWriter.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if err := csvWriter.Write([]string{entry.Key, entry.Text, entry.Level, entry.Entity, entry.Field}); err != nil {
+			return err
+		}
+	}
+
+	csvWriter.Flush()
+
+	return csvWriter.Error()
+
+}
+
+// ReadCSV reads a Table from CSV with a header row naming a "key" column and a "text" column (which holds the translations), as written by
+// WriteCSV. Other columns are ignored, as are rows with a blank key.
+func ReadCSV(reader io.Reader) (Table, error) {
+
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1
+
+	header, err := csvReader.Read()
+
+	if err != nil {
+		return nil, err
+	}
+
+	keyColumn, textColumn := -1, -1
+
+	for i, name := range header {
+		switch strings.TrimSpace(name) {
+		case "key":
+			keyColumn = i
+		case "text":
+			textColumn = i
+		}
+	}
+
+	if keyColumn < 0 || textColumn < 0 {
+		return nil, errors.New("CSV header doesn't have both a key and a text column")
+	}
+
+	table := Table{}
+
+	for {
+
+		record, err := csvReader.Read()
+
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		if keyColumn >= len(record) || textColumn >= len(record) || record[keyColumn] == "" {
+			continue
+		}
+
+		table[record[keyColumn]] = record[textColumn]
+
+	}
+
+	return table, nil
+
+}
+
+// WritePO writes the Entries given to the writer as a gettext PO template, with each Entry's key as its message context and its text as its
+// message ID; the Entry's Context is written as a comment for translators. It can be read back using ReadPO once translated.
+func WritePO(writer io.Writer, entries []Entry) error {
+
+	buffered := bufio.NewWriter(writer)
+
+	fmt.Fprintf(buffered, "msgid \"\"\nmsgstr \"\"\n\"Content-Type: text/plain; charset=UTF-8\\n\"\n")
+
+	for _, entry := range entries {
+		fmt.Fprintf(buffered, "\n#. %s\n", entry.Context())
+		fmt.Fprintf(buffered, "msgctxt %s\n", poQuote(entry.Key))
+		fmt.Fprintf(buffered, "msgid %s\n", poQuote(entry.Text))
+		fmt.Fprintf(buffered, "msgstr \"\"\n")
+	}
+
+	return buffered.Flush()
+
+}
+
+// ReadPO reads a Table from a gettext PO file whose messages' contexts are keys, as written by WritePO. Messages without a context or without
+// a translation (an empty msgstr) are skipped, so the text in the Project is kept for them.
+func ReadPO(reader io.Reader) (Table, error) {
+
+	table := Table{}
+
+	var context, translation *string
+	var current *string
+
+	flush := func() {
+		if context != nil && translation != nil && *translation != "" {
+			table[*context] = *translation
+		}
+		context, translation, current = nil, nil, nil
+	}
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	lineNumber := 0
+
+	for scanner.Scan() {
+
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		keyword := line
+		value := ""
+
+		if i := strings.IndexByte(line, ' '); i >= 0 {
+			keyword, value = line[:i], strings.TrimSpace(line[i+1:])
+		}
+
+		if strings.HasPrefix(line, "\"") {
+			// A continuation of the previous keyword's string.
+			keyword, value = "", line
+		}
+
+		text := ""
+
+		if value != "" {
+			unquoted, err := strconv.Unquote(value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid string %s", lineNumber, value)
+			}
+			text = unquoted
+		}
+
+		switch keyword {
+		case "":
+			if current != nil {
+				*current += text
+			}
+		case "msgctxt":
+			flush()
+			context = &text
+			current = context
+		case "msgid":
+			if context == nil {
+				flush()
+			}
+			current = new(string) // The original text isn't needed
+		case "msgstr":
+			translation = &text
+			current = translation
+		default:
+			current = nil
+		}
+
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	flush()
+
+	return table, nil
+
+}
+
+// poQuote quotes the string given for a PO file.
+func poQuote(text string) string {
+	replacer := strings.NewReplacer("\\", "\\\\", "\"", "\\\"", "\n", "\\n", "\r", "\\r", "\t", "\\t")
+	return "\"" + replacer.Replace(text) + "\""
+}
@@ -0,0 +1,145 @@
+package localize
+
+// localize extracts the text of LDtk Projects (the values of String and Multilines fields on Levels and Entities, like signs and dialogue)
+// into localization files keyed by stable keys, and substitutes translated text back into Projects at runtime:
+//
+//	entries := localize.Extract(project)
+//	localize.WritePO(file, entries) // Sent off to translators
+//	...
+//	table, err := localize.ReadPO(translatedFile)
+//	table.Apply(project)
+//
+// Keys are made from the IID of the Level or Entity and the identifier of the field (i.e. "a3b1c2d0-...-e5f6.dialogue"), with the index of
+// the element appended for arrays (i.e. "a3b1c2d0-...-e5f6.lines.2"), so they don't change when Levels or Entities are renamed or moved.
+
+import (
+	"strconv"
+
+	"github.com/solarlune/ldtkgo"
+)
+
+// Entry is a string extracted from a Project for localization.
+type Entry struct {
+	Key    string // The stable key of the string
+	Text   string // The text of the string in the Project
+	Level  string // The identifier of the Level the string is on (for context)
+	Entity string // The identifier of the Entity the string is on (for context); blank for Level fields
+	Field  string // The identifier of the field the string is the value of (for context)
+}
+
+// Context returns a description of where the Entry's string is in the Project, for translators (i.e. "Level_0 > Sign > text").
+func (entry Entry) Context() string {
+	if entry.Entity == "" {
+		return entry.Level + " > " + entry.Field
+	}
+	return entry.Level + " > " + entry.Entity + " > " + entry.Field
+}
+
+// Key returns the key of the value of the field with the identifier given on the Level or Entity with the IID given. index is the index of the
+// element for array fields, or -1 for other fields.
+func Key(iid, field string, index int) string {
+	if index < 0 {
+		return iid + "." + field
+	}
+	return iid + "." + field + "." + strconv.Itoa(index)
+}
+
+// Extract returns an Entry for each non-null String and Multilines value (including the elements of arrays) on the Project's Levels and
+// Entities, in order: for each Level, its own fields, followed by the fields of its Entities (in the order of the Level's Layers).
+func Extract(project *ldtkgo.Project) []Entry {
+
+	entries := []Entry{}
+
+	walkStrings(project, func(iid, levelIdentifier, entityIdentifier string, property *ldtkgo.Property, index int, text string) {
+		entries = append(entries, Entry{
+			Key:    Key(iid, property.Identifier, index),
+			Text:   text,
+			Level:  levelIdentifier,
+			Entity: entityIdentifier,
+			Field:  property.Identifier,
+		})
+	})
+
+	return entries
+
+}
+
+// Table maps the keys of the strings in a Project to their translations.
+type Table map[string]string
+
+// Text returns the translation of the value of the field with the identifier given on the Level or Entity with the IID given (see Key), or
+// the fallback given if the Table doesn't have one.
+func (table Table) Text(iid, field string, index int, fallback string) string {
+	if text, ok := table[Key(iid, field, index)]; ok {
+		return text
+	}
+	return fallback
+}
+
+// Apply replaces the String and Multilines values on the Project's Levels and Entities with their translations from the Table, so the rest of
+// the game can read the Properties as usual. Strings that the Table has no translation for are left as they are. As the Project is modified,
+// load it again (or Clone it beforehand) to switch to another language.
+func (table Table) Apply(project *ldtkgo.Project) {
+
+	walkStrings(project, func(iid, levelIdentifier, entityIdentifier string, property *ldtkgo.Property, index int, text string) {
+
+		translation, ok := table[Key(iid, property.Identifier, index)]
+
+		if !ok {
+			return
+		}
+
+		if index < 0 {
+			property.Value = translation
+		} else {
+			property.Value.([]interface{})[index] = translation
+		}
+
+	})
+
+}
+
+// walkStrings calls the function given for each non-null String and Multilines value on the Project's Levels and Entities, with the index of
+// the value in its array, or -1 if the Property isn't an array.
+func walkStrings(project *ldtkgo.Project, fn func(iid, levelIdentifier, entityIdentifier string, property *ldtkgo.Property, index int, text string)) {
+
+	visit := func(iid, levelIdentifier, entityIdentifier string, properties []*ldtkgo.Property) {
+
+		for _, property := range properties {
+
+			if t := property.LDtkType(); t != ldtkgo.PropertyTypeString && t != ldtkgo.PropertyTypeMultilines {
+				continue
+			}
+
+			if !property.IsArray() {
+				if text, ok := property.Value.(string); ok {
+					fn(iid, levelIdentifier, entityIdentifier, property, -1, text)
+				}
+				continue
+			}
+
+			values, _ := property.Value.([]interface{})
+
+			for i, value := range values {
+				if text, ok := value.(string); ok {
+					fn(iid, levelIdentifier, entityIdentifier, property, i, text)
+				}
+			}
+
+		}
+
+	}
+
+	for _, level := range project.Levels {
+
+		visit(level.IID, level.Identifier, "", level.Properties)
+
+		for _, layer := range level.Layers {
+			for _, entity := range layer.Entities {
+				visit(entity.IID, level.Identifier, entity.Identifier, entity.Properties)
+			}
+		}
+
+	}
+
+}
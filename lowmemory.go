@@ -0,0 +1,51 @@
+package ldtkgo
+
+// stringInterner deduplicates strings so that repeated values (identifiers, types, paths, etc.) share the same memory.
+type stringInterner map[string]string
+
+func (interner stringInterner) intern(s string) string {
+	if existing, exists := interner[s]; exists {
+		return existing
+	}
+	interner[s] = s
+	return s
+}
+
+func (interner stringInterner) internProperties(properties []*Property) {
+	for _, prop := range properties {
+		prop.Identifier = interner.intern(prop.Identifier)
+		prop.Type = interner.intern(prop.Type)
+		if value, ok := prop.Value.(string); ok {
+			prop.Value = interner.intern(value)
+		}
+	}
+}
+
+func (interner stringInterner) internLevel(level *Level) {
+
+	level.BGColorString = interner.intern(level.BGColorString)
+
+	if level.BGImage != nil {
+		level.BGImage.Path = interner.intern(level.BGImage.Path)
+		level.BGImage.Mode = interner.intern(level.BGImage.Mode)
+	}
+
+	interner.internProperties(level.Properties)
+
+	for _, layer := range level.Layers {
+
+		layer.Identifier = interner.intern(layer.Identifier)
+		layer.Type = interner.intern(layer.Type)
+
+		for _, entity := range layer.Entities {
+			entity.Identifier = interner.intern(entity.Identifier)
+			entity.SmartColorString = interner.intern(entity.SmartColorString)
+			for i, tag := range entity.Tags {
+				entity.Tags[i] = interner.intern(tag)
+			}
+			interner.internProperties(entity.Properties)
+		}
+
+	}
+
+}
@@ -0,0 +1,484 @@
+package ldtkgo
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+)
+
+// merger combines Projects into a merged Project, keeping track of the UIDs and IIDs that are already in use.
+type merger struct {
+	project *Project
+	iids    map[string]bool
+}
+
+// Merge combines the Projects given into a single new Project, so that a game can load separate project files (i.e. a base game and its
+// expansions or mods) as one world. The first Project is the base: the merged Project uses its settings (world layout, default background
+// color, custom commands, and flags), and the Levels, Tilesets, and definitions of the other Projects are added to it, in order.
+//
+// Definitions that are the same in several Projects (Tilesets with the same UID and image, or Entity and Layer definitions with the same UID
+// and Identifier, as when an expansion is made from a copy of the base project) are only added once. Other definitions whose UIDs are already
+// in use are given new UIDs, and Levels, Layers, and Entities whose IIDs are already in use are given new IIDs, which are derived from the
+// originals and the position of their Project in the arguments, so merging the same Projects always gives the same result. All references to
+// remapped UIDs and IIDs (i.e. the Tilesets of Layers, entity references, and Level neighbours) are updated to match. Level field definitions,
+// Project Properties, and table of contents entries are combined by Identifier, keeping the first ones found.
+//
+// The paths of Tilesets, background images, and FilePath Properties are made relative to the root of the file system instead of to their project
+// files (see Project.ResolvePath), so that they stay valid for Projects from different directories. The Projects given aren't modified; nothing
+// is shared between them and the merged Project. Note that Levels aren't moved, so Levels from different Projects may overlap in the world.
+func Merge(projects ...*Project) (*Project, error) {
+
+	if len(projects) == 0 {
+		return nil, errors.New("no projects to merge")
+	}
+
+	for i, project := range projects {
+		if project == nil {
+			return nil, fmt.Errorf("project %d to merge is nil", i)
+		}
+	}
+
+	m := &merger{
+		project: projects[0].Clone(),
+		iids:    map[string]bool{},
+	}
+
+	m.project.resolvePaths()
+	m.project.Path = ""
+	m.project.subscriptions = nil
+
+	for _, world := range m.project.Worlds {
+		m.iids[world.IID] = true
+	}
+
+	for _, level := range m.project.Levels {
+		m.iids[level.IID] = true
+		for _, layer := range level.Layers {
+			m.iids[layer.IID] = true
+			for _, entity := range layer.Entities {
+				m.iids[entity.IID] = true
+			}
+		}
+	}
+
+	for i, project := range projects[1:] {
+		source := project.Clone()
+		source.resolvePaths()
+		m.add(source, i+1)
+	}
+
+	m.project.setupDefinitions()
+
+	for _, level := range m.project.Levels {
+		m.project.setupLevel(level)
+	}
+
+	m.project.resolveReferences()
+
+	return m.project, nil
+
+}
+
+// resolvePaths makes the paths stored in the Project relative to the root of its file system, rather than to the project file.
+func (project *Project) resolvePaths() {
+
+	for _, tileset := range project.Tilesets {
+		if tileset.Path != "" {
+			tileset.Path = project.ResolvePath(tileset.Path)
+		}
+	}
+
+	resolveFilePaths := func(properties []*Property) {
+		for _, prop := range properties {
+			if prop.LDtkType() != PropertyTypeFilePath {
+				continue
+			}
+			if array, ok := prop.Value.([]interface{}); ok {
+				for i, value := range array {
+					if filePath, ok := value.(string); ok && filePath != "" {
+						array[i] = project.ResolvePath(filePath)
+					}
+				}
+			} else if filePath, ok := prop.Value.(string); ok && filePath != "" {
+				prop.Value = project.ResolvePath(filePath)
+			}
+		}
+	}
+
+	resolveFilePaths(project.Properties)
+
+	for i, enumPath := range project.ExternalEnumPaths {
+		project.ExternalEnumPaths[i] = project.ResolvePath(enumPath)
+	}
+
+	for _, level := range project.Levels {
+		if level.BGImage != nil && level.BGImage.Path != "" {
+			level.BGImage.Path = project.ResolvePath(level.BGImage.Path)
+		}
+		if level.ExternalPath != "" {
+			level.ExternalPath = project.ResolvePath(level.ExternalPath)
+		}
+		resolveFilePaths(level.Properties)
+		for _, entity := range level.Entities() {
+			resolveFilePaths(entity.Properties)
+		}
+	}
+
+	project.dir = ""
+
+}
+
+// add adds the contents of the source Project (a clone, which can be modified freely) to the merged Project; index is the position of the
+// source Project in the arguments to Merge.
+func (m *merger) add(source *Project, index int) {
+
+	tilesetUIDs := map[int]int{}
+	entityDefUIDs := map[int]int{}
+	layerDefUIDs := map[int]int{}
+
+	// Tilesets; only the Tilesets and definitions that were in the merged Project beforehand can be shared with the source Project's.
+	existingTilesets := map[int]*Tileset{}
+	used := map[int]bool{}
+
+	for _, tileset := range m.project.Tilesets {
+		existingTilesets[tileset.ID] = tileset
+		used[tileset.ID] = true
+	}
+
+	for _, tileset := range source.Tilesets {
+
+		if existing := existingTilesets[tileset.ID]; existing != nil && existing.Path == tileset.Path {
+			tilesetUIDs[tileset.ID] = existing.ID
+			continue
+		}
+
+		uid := freeUID(tileset.ID, used)
+		used[uid] = true
+		tilesetUIDs[tileset.ID] = uid
+		tileset.ID = uid
+
+		m.project.Tilesets = append(m.project.Tilesets, tileset)
+
+	}
+
+	remapTileset := func(uid int) int {
+		if remapped, exists := tilesetUIDs[uid]; exists {
+			return remapped
+		}
+		return uid
+	}
+
+	// Entity definitions
+
+	existingEntityDefs := map[int]*EntityDefinition{}
+	used = map[int]bool{}
+
+	for _, def := range m.project.EntityDefinitions {
+		existingEntityDefs[def.UID] = def
+		used[def.UID] = true
+	}
+
+	added := []*EntityDefinition{}
+
+	for _, def := range source.EntityDefinitions {
+
+		if existing := existingEntityDefs[def.UID]; existing != nil && existing.Identifier == def.Identifier {
+			entityDefUIDs[def.UID] = existing.UID
+			continue
+		}
+
+		uid := freeUID(def.UID, used)
+		used[uid] = true
+		entityDefUIDs[def.UID] = uid
+		def.UID = uid
+
+		m.project.EntityDefinitions = append(m.project.EntityDefinitions, def)
+		added = append(added, def)
+
+	}
+
+	remapEntityDef := func(uid int) int {
+		if remapped, exists := entityDefUIDs[uid]; exists {
+			return remapped
+		}
+		return uid
+	}
+
+	remapFieldDefinitions := func(defs []*FieldDefinition) {
+		for _, fieldDef := range defs {
+			if fieldDef.TilesetUID != nil {
+				*fieldDef.TilesetUID = remapTileset(*fieldDef.TilesetUID)
+			}
+			if fieldDef.AllowedRefsEntityUID != nil {
+				*fieldDef.AllowedRefsEntityUID = remapEntityDef(*fieldDef.AllowedRefsEntityUID)
+			}
+		}
+	}
+
+	for _, def := range added {
+		if def.TileRect != nil {
+			def.TileRect.TilesetUID = remapTileset(def.TileRect.TilesetUID)
+		}
+		remapFieldDefinitions(def.FieldDefinitions)
+	}
+
+	// Layer definitions
+
+	existingLayerDefs := map[int]*LayerDefinition{}
+	used = map[int]bool{}
+
+	for _, def := range m.project.LayerDefinitions {
+		existingLayerDefs[def.UID] = def
+		used[def.UID] = true
+	}
+
+	addedLayers := []*LayerDefinition{}
+
+	for _, def := range source.LayerDefinitions {
+
+		if existing := existingLayerDefs[def.UID]; existing != nil && existing.Identifier == def.Identifier && existing.Type == def.Type {
+			layerDefUIDs[def.UID] = existing.UID
+			continue
+		}
+
+		uid := freeUID(def.UID, used)
+		used[uid] = true
+		layerDefUIDs[def.UID] = uid
+		def.UID = uid
+
+		m.project.LayerDefinitions = append(m.project.LayerDefinitions, def)
+		addedLayers = append(addedLayers, def)
+
+	}
+
+	remapLayerDef := func(uid int) int {
+		if remapped, exists := layerDefUIDs[uid]; exists {
+			return remapped
+		}
+		return uid
+	}
+
+	for _, def := range addedLayers {
+		if def.TilesetUID != 0 {
+			def.TilesetUID = remapTileset(def.TilesetUID)
+		}
+		if def.AutoSourceLayerDefUID != 0 {
+			def.AutoSourceLayerDefUID = remapLayerDef(def.AutoSourceLayerDefUID)
+		}
+	}
+
+	// Level field definitions and IntGrid names
+
+	for _, fieldDef := range source.LevelFieldDefinitions {
+		if m.project.LevelFieldDefinitionByIdentifier(fieldDef.Identifier) == nil {
+			remapFieldDefinitions([]*FieldDefinition{fieldDef})
+			m.project.LevelFieldDefinitions = append(m.project.LevelFieldDefinitions, fieldDef)
+		}
+	}
+
+	for _, name := range source.IntGridNames {
+		if m.project.IntGridConstantByName(name) < 0 {
+			m.project.IntGridNames = append(m.project.IntGridNames, name)
+		}
+	}
+
+	for _, enumPath := range source.ExternalEnumPaths {
+		if !containsPath(m.project.ExternalEnumPaths, enumPath) {
+			m.project.ExternalEnumPaths = append(m.project.ExternalEnumPaths, enumPath)
+		}
+	}
+
+	// IIDs
+
+	iids := map[string]string{}
+
+	remapIID := func(iid string) string {
+		if iid == "" {
+			return iid
+		}
+		if remapped, exists := iids[iid]; exists {
+			return remapped
+		}
+		remapped := iid
+		for m.iids[remapped] {
+			remapped = derivedIID(index, remapped)
+		}
+		m.iids[remapped] = true
+		iids[iid] = remapped
+		return remapped
+	}
+
+	for _, world := range source.Worlds {
+		world.IID = remapIID(world.IID)
+	}
+
+	for _, level := range source.Levels {
+		level.IID = remapIID(level.IID)
+		for _, layer := range level.Layers {
+			layer.IID = remapIID(layer.IID)
+			for _, entity := range layer.Entities {
+				entity.IID = remapIID(entity.IID)
+			}
+		}
+	}
+
+	remapRef := func(iid string) string {
+		if remapped, exists := iids[iid]; exists {
+			return remapped
+		}
+		return iid
+	}
+
+	remapProperties := func(properties []*Property) {
+		for _, prop := range properties {
+			remapPropertyValue(prop, remapRef, remapTileset)
+		}
+	}
+
+	// Levels
+
+	for _, level := range source.Levels {
+
+		for i := range level.Neighbours {
+			level.Neighbours[i].LevelIID = remapRef(level.Neighbours[i].LevelIID)
+		}
+
+		remapProperties(level.Properties)
+
+		for _, layer := range level.Layers {
+
+			layer.DefUID = remapLayerDef(layer.DefUID)
+
+			if layer.TilesetUID != 0 {
+				layer.TilesetUID = remapTileset(layer.TilesetUID)
+			}
+
+			layer.ForEachTile(func(tile *Tile) {
+				if tile.TilesetUID != 0 {
+					tile.TilesetUID = remapTileset(tile.TilesetUID)
+				}
+			})
+
+			for _, entity := range layer.Entities {
+				entity.DefUID = remapEntityDef(entity.DefUID)
+				if entity.TileRect != nil {
+					entity.TileRect.TilesetUID = remapTileset(entity.TileRect.TilesetUID)
+				}
+				remapProperties(entity.Properties)
+			}
+
+		}
+
+		m.project.Levels = append(m.project.Levels, level)
+
+	}
+
+	// The source's Worlds are added as they are, so their Levels stay separate from the merged Project's other Worlds.
+	m.project.Worlds = append(m.project.Worlds, source.Worlds...)
+
+	// Project Properties and table of contents
+
+	remapProperties(source.Properties)
+
+	for _, prop := range source.Properties {
+		if m.project.PropertyByIdentifier(prop.Identifier) == nil {
+			m.project.Properties = append(m.project.Properties, prop)
+		}
+	}
+
+	for _, entry := range source.TableOfContents {
+
+		for _, instance := range entry.Instances {
+			instance.IIDs.EntityIID = remapRef(instance.IIDs.EntityIID)
+			instance.IIDs.LayerIID = remapRef(instance.IIDs.LayerIID)
+			instance.IIDs.LevelIID = remapRef(instance.IIDs.LevelIID)
+		}
+
+		var existing *TOCEntry
+		for _, e := range m.project.TableOfContents {
+			if e.Identifier == entry.Identifier {
+				existing = e
+				break
+			}
+		}
+
+		if existing != nil {
+			existing.Instances = append(existing.Instances, entry.Instances...)
+		} else {
+			m.project.TableOfContents = append(m.project.TableOfContents, entry)
+		}
+
+	}
+
+}
+
+// remapPropertyValue updates the IIDs in the value of an EntityRef Property, or the Tileset UIDs in the value of a Tile Property, using the
+// functions given.
+func remapPropertyValue(prop *Property, remapIID func(string) string, remapTileset func(int) int) {
+
+	kind := prop.LDtkType()
+
+	if kind != PropertyTypeEntityRef && kind != PropertyTypeTile {
+		return
+	}
+
+	values := []interface{}{prop.Value}
+	if array, ok := prop.Value.([]interface{}); ok {
+		values = array
+	}
+
+	for _, v := range values {
+
+		value, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if kind == PropertyTypeEntityRef {
+			for _, key := range []string{"entityIid", "layerIid", "levelIid"} {
+				if iid, ok := value[key].(string); ok {
+					value[key] = remapIID(iid)
+				}
+			}
+		} else if uid, ok := value["tilesetUid"].(float64); ok {
+			value["tilesetUid"] = float64(remapTileset(int(uid)))
+		}
+
+	}
+
+}
+
+// freeUID returns the UID given if it isn't in use, or the UID after the highest one in use otherwise.
+func freeUID(uid int, used map[int]bool) int {
+
+	if !used[uid] {
+		return uid
+	}
+
+	for existing := range used {
+		if existing >= uid {
+			uid = existing + 1
+		}
+	}
+
+	return uid
+
+}
+
+// derivedIID returns a new IID (in the same format as LDtk's) derived from the Project index and IID given.
+func derivedIID(index int, iid string) string {
+	b := sha256.Sum256([]byte(fmt.Sprintf("%d/%s", index, iid)))
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// containsPath returns whether the paths given include the path given.
+func containsPath(paths []string, p string) bool {
+	for _, existing := range paths {
+		if existing == p {
+			return true
+		}
+	}
+	return false
+}
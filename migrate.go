@@ -0,0 +1,439 @@
+package ldtkgo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// LDtk's JSON format has changed over time; to keep projects saved with older versions of LDtk loading, their JSON is migrated to the current
+// format before it's decoded. Each migration undoes a change made in the version of LDtk given, and is applied to projects (and their external
+// level files) saved with an older version. Projects saved with the latest version of LDtk aren't touched, so they don't pay for any of this.
+
+// migration converts the JSON of a project (and of each of its Levels) saved with a version of LDtk older than its version to the format used
+// from its version onwards. Either function can be nil.
+type migration struct {
+	version string
+	project func(project map[string]interface{}, context *migrationContext)
+	level   func(level map[string]interface{}, context *migrationContext)
+}
+
+// migrations are the migrations between versions of LDtk's JSON format, in order.
+var migrations = []migration{
+	{version: "0.7.0", project: migrateDefaultBGColor, level: migrateLevelBGColor},
+	{version: "0.8.0", project: migrateIntGridValues, level: migrateIntGridCSV},
+	{version: "1.0.0", project: migrateEntityDefTiles, level: migrateLevel1},
+}
+
+// oldestSupportedVersion is the oldest version of LDtk whose projects can be migrated; projects saved with older versions are rejected with
+// ErrUnsupportedVersion.
+const oldestSupportedVersion = "0.6.0"
+
+// migrationContext holds the parts of a project that its Levels need to be migrated.
+type migrationContext struct {
+	defaultBGColor string
+	entitySizes    map[int][2]int
+}
+
+// newMigrationContext creates a migrationContext from the JSON of a project.
+func newMigrationContext(project map[string]interface{}) *migrationContext {
+
+	context := &migrationContext{entitySizes: map[int][2]int{}}
+
+	if color, ok := project["defaultLevelBgColor"].(string); ok {
+		context.defaultBGColor = color
+	} else if color, ok := project["bgColor"].(string); ok {
+		context.defaultBGColor = color
+	}
+
+	defs, _ := project["defs"].(map[string]interface{})
+
+	for _, def := range jsonObjects(defs["entities"]) {
+		context.entitySizes[jsonInt(def["uid"])] = [2]int{jsonInt(def["width"]), jsonInt(def["height"])}
+	}
+
+	return context
+
+}
+
+// needsMigration returns whether a project saved with the version of LDtk given has to be migrated. Projects without a version are assumed to
+// be current (i.e. generated by tools other than LDtk).
+func needsMigration(version string) bool {
+	return version != "" && compareVersions(version, migrations[len(migrations)-1].version) < 0
+}
+
+// migrateProject migrates the JSON of a project saved with an older version of LDtk to the current format, returning it as-is if it doesn't
+// need migrating.
+func migrateProject(data []byte) ([]byte, error) {
+
+	version := peekJSONVersion(data)
+
+	if !needsMigration(version) {
+		return data, nil
+	}
+
+	if compareVersions(version, oldestSupportedVersion) < 0 {
+		return nil, fmt.Errorf("%w: %s (the oldest supported version is %s)", ErrUnsupportedVersion, version, oldestSupportedVersion)
+	}
+
+	project := map[string]interface{}{}
+
+	if err := decodeJSONNumbers(data, &project); err != nil {
+		return nil, err
+	}
+
+	context := newMigrationContext(project)
+
+	for _, m := range migrations {
+
+		if compareVersions(version, m.version) >= 0 {
+			continue
+		}
+
+		if m.project != nil {
+			m.project(project, context)
+		}
+
+		if m.level != nil {
+			for _, level := range jsonObjects(project["levels"]) {
+				m.level(level, context)
+			}
+		}
+
+	}
+
+	return json.Marshal(project)
+
+}
+
+// migrateLevelData migrates the JSON of an external level file of the Project to the current format if the Project was saved with an older
+// version of LDtk.
+func (project *Project) migrateLevelData(data []byte) ([]byte, error) {
+
+	if !needsMigration(project.JSONVersion) {
+		return data, nil
+	}
+
+	level := map[string]interface{}{}
+
+	if err := decodeJSONNumbers(data, &level); err != nil {
+		return nil, err
+	}
+
+	context := &migrationContext{defaultBGColor: project.BGColorString, entitySizes: map[int][2]int{}}
+
+	for _, def := range project.EntityDefinitions {
+		context.entitySizes[def.UID] = [2]int{def.Width, def.Height}
+	}
+
+	for _, m := range migrations {
+		if compareVersions(project.JSONVersion, m.version) < 0 && m.level != nil {
+			m.level(level, context)
+		}
+	}
+
+	return json.Marshal(level)
+
+}
+
+// migrateDefaultBGColor fills in the default background color of Levels, which was the project's background color before LDtk 0.7.0.
+func migrateDefaultBGColor(project map[string]interface{}, context *migrationContext) {
+	if _, ok := project["defaultLevelBgColor"].(string); !ok && context.defaultBGColor != "" {
+		project["defaultLevelBgColor"] = context.defaultBGColor
+	}
+}
+
+// migrateLevelBGColor fills in the background color of a Level, which was only stored if it was different from the default before LDtk 0.7.0.
+func migrateLevelBGColor(level map[string]interface{}, context *migrationContext) {
+
+	if _, ok := level["__bgColor"].(string); ok {
+		return
+	}
+
+	if color, ok := level["bgColor"].(string); ok {
+		level["__bgColor"] = color
+	} else {
+		level["__bgColor"] = context.defaultBGColor
+	}
+
+}
+
+// migrateIntGridValues numbers the values of IntGrid layer definitions, which were identified by their index before LDtk 0.8.0; they're
+// numbered from 1, to match the values migrateIntGridCSV gives cells.
+func migrateIntGridValues(project map[string]interface{}, context *migrationContext) {
+
+	defs, _ := project["defs"].(map[string]interface{})
+
+	for _, layer := range jsonObjects(defs["layers"]) {
+		for i, value := range jsonObjects(layer["intGridValues"]) {
+			if _, ok := value["value"]; !ok {
+				value["value"] = i + 1
+			}
+		}
+	}
+
+}
+
+// migrateIntGridCSV converts the IntGrid values of a Level's layers from a list of cells (with 0-based values) to the CSV format of LDtk 0.8.0
+// (with 1-based values, and 0 for empty cells).
+func migrateIntGridCSV(level map[string]interface{}, context *migrationContext) {
+
+	for _, layer := range jsonObjects(level["layerInstances"]) {
+
+		cells, ok := layer["intGrid"].([]interface{})
+
+		if !ok {
+			continue
+		}
+
+		if _, ok := layer["intGridCsv"]; !ok {
+
+			csv := make([]interface{}, jsonInt(layer["__cWid"])*jsonInt(layer["__cHei"]))
+
+			for i := range csv {
+				csv[i] = 0
+			}
+
+			for _, cell := range jsonObjects(cells) {
+				if coord := jsonInt(cell["coordId"]); coord >= 0 && coord < len(csv) {
+					csv[coord] = jsonInt(cell["v"]) + 1
+				}
+			}
+
+			layer["intGridCsv"] = csv
+
+		}
+
+		delete(layer, "intGrid")
+
+	}
+
+}
+
+// migrateEntityDefTiles converts the tiles of Entity definitions from a tile ID to the tile rectangles used from LDtk 1.0.0 onwards.
+func migrateEntityDefTiles(project map[string]interface{}, context *migrationContext) {
+
+	defs, _ := project["defs"].(map[string]interface{})
+
+	tilesets := map[int]map[string]interface{}{}
+
+	for _, tileset := range jsonObjects(defs["tilesets"]) {
+		tilesets[jsonInt(tileset["uid"])] = tileset
+	}
+
+	for _, def := range jsonObjects(defs["entities"]) {
+
+		if _, ok := def["tileRect"].(map[string]interface{}); !ok && def["tileId"] != nil && def["tilesetId"] != nil {
+			if tileset, ok := tilesets[jsonInt(def["tilesetId"])]; ok {
+				def["tileRect"] = tileIDRect(tileset, jsonInt(def["tileId"]))
+			}
+		}
+
+		delete(def, "tileId")
+
+	}
+
+}
+
+// tileIDRect returns the JSON of the tile rectangle of the tile with the ID given in the JSON of the tileset given.
+func tileIDRect(tileset map[string]interface{}, tileID int) map[string]interface{} {
+
+	gridSize := jsonInt(tileset["tileGridSize"])
+	spacing := jsonInt(tileset["spacing"])
+	padding := jsonInt(tileset["padding"])
+
+	columns := 1
+	if gridSize+spacing > 0 {
+		if c := (jsonInt(tileset["pxWid"]) - padding*2 + spacing) / (gridSize + spacing); c > 0 {
+			columns = c
+		}
+	}
+
+	return map[string]interface{}{
+		"tilesetUid": jsonInt(tileset["uid"]),
+		"x":          padding + (tileID%columns)*(gridSize+spacing),
+		"y":          padding + (tileID/columns)*(gridSize+spacing),
+		"w":          gridSize,
+		"h":          gridSize,
+	}
+
+}
+
+// migrateLevel1 converts a Level to the format of LDtk 1.0.0: IIDs are generated for the Level, its layers, and its Entities (from their UIDs
+// and positions in the Level, so they're the same each time the project is loaded), tile IDs are moved out of the tiles' data, and the tiles
+// and sizes of Entities are filled in.
+func migrateLevel1(level map[string]interface{}, context *migrationContext) {
+
+	levelKey := "level/" + jsonString(level["uid"]) + "/" + jsonString(level["identifier"])
+
+	if _, ok := level["iid"].(string); !ok {
+		level["iid"] = derivedIID(0, levelKey)
+	}
+
+	for layerIndex, layer := range jsonObjects(level["layerInstances"]) {
+
+		layerKey := fmt.Sprintf("%s/layer/%d", levelKey, layerIndex)
+
+		if _, ok := layer["iid"].(string); !ok {
+			layer["iid"] = derivedIID(0, layerKey)
+		}
+
+		for _, tiles := range []string{"gridTiles", "autoLayerTiles"} {
+			for _, tile := range jsonObjects(layer[tiles]) {
+				// The ID of the tile used to be the last value of its data.
+				if data, ok := tile["d"].([]interface{}); ok && tile["t"] == nil && len(data) > 0 {
+					tile["t"] = data[len(data)-1]
+				}
+			}
+		}
+
+		for entityIndex, entity := range jsonObjects(layer["entityInstances"]) {
+
+			if _, ok := entity["iid"].(string); !ok {
+				entity["iid"] = derivedIID(entityIndex, layerKey+"/entity")
+			}
+
+			if tile, ok := entity["__tile"].(map[string]interface{}); ok {
+				if rect, ok := tile["srcRect"].([]interface{}); ok && len(rect) == 4 {
+					tile["x"], tile["y"], tile["w"], tile["h"] = rect[0], rect[1], rect[2], rect[3]
+					delete(tile, "srcRect")
+				}
+			}
+
+			size, ok := context.entitySizes[jsonInt(entity["defUid"])]
+
+			if entity["width"] == nil && ok {
+				entity["width"] = size[0]
+			}
+
+			if entity["height"] == nil && ok {
+				entity["height"] = size[1]
+			}
+
+		}
+
+	}
+
+}
+
+// peekJSONVersion returns the version of LDtk the project with the JSON given was saved with, or a blank string if it doesn't have one. The
+// version is near the top of the file, so rather than decoding the entire file, the first "jsonVersion" key is looked for.
+func peekJSONVersion(data []byte) string {
+
+	index := bytes.Index(data, []byte(`"jsonVersion"`))
+
+	if index < 0 {
+		return ""
+	}
+
+	rest := bytes.TrimLeft(data[index+len(`"jsonVersion"`):], " \t\r\n")
+
+	if len(rest) == 0 || rest[0] != ':' {
+		return ""
+	}
+
+	version := ""
+
+	if err := json.NewDecoder(bytes.NewReader(rest[1:])).Decode(&version); err != nil {
+		return ""
+	}
+
+	return version
+
+}
+
+// compareVersions compares the LDtk versions given (i.e. "1.5.3" or "0.9.3-beta"), returning -1 if a is older than b, 1 if a is newer, and 0
+// if they're the same.
+func compareVersions(a, b string) int {
+
+	aParts := strings.Split(a, ".")
+	bParts := strings.Split(b, ".")
+
+	for i := 0; i < len(aParts) || i < len(bParts); i++ {
+
+		aNumber, bNumber := 0, 0
+
+		if i < len(aParts) {
+			aNumber = leadingNumber(aParts[i])
+		}
+
+		if i < len(bParts) {
+			bNumber = leadingNumber(bParts[i])
+		}
+
+		if aNumber < bNumber {
+			return -1
+		} else if aNumber > bNumber {
+			return 1
+		}
+
+	}
+
+	return 0
+
+}
+
+// leadingNumber returns the number at the start of the string given (i.e. 3 for "3-beta"), or 0 if it doesn't start with a number.
+func leadingNumber(text string) int {
+
+	end := 0
+
+	for end < len(text) && text[end] >= '0' && text[end] <= '9' {
+		end++
+	}
+
+	number, _ := strconv.Atoi(text[:end])
+
+	return number
+
+}
+
+// decodeJSONNumbers decodes the JSON given into the value given, keeping numbers as json.Numbers so that they're encoded again exactly as they were.
+func decodeJSONNumbers(data []byte, value interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(value)
+}
+
+// jsonObjects returns the objects in the JSON array given, skipping any other values.
+func jsonObjects(value interface{}) []map[string]interface{} {
+
+	array, _ := value.([]interface{})
+	objects := make([]map[string]interface{}, 0, len(array))
+
+	for _, element := range array {
+		if object, ok := element.(map[string]interface{}); ok {
+			objects = append(objects, object)
+		}
+	}
+
+	return objects
+
+}
+
+// jsonInt returns the JSON number given as an int, or 0 if it isn't a number.
+func jsonInt(value interface{}) int {
+
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return int(i)
+		}
+		f, _ := v.Float64()
+		return int(f)
+	case float64:
+		return int(v)
+	case int:
+		return v
+	}
+
+	return 0
+
+}
+
+// jsonString returns the JSON value given as a string, for use in keys.
+func jsonString(value interface{}) string {
+	return fmt.Sprint(value)
+}
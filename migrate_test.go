@@ -0,0 +1,127 @@
+package ldtkgo
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestMigrateVersions loads a project saved at each version of LDtk where the JSON format changed (and at the versions around them), and
+// checks that projects older than the oldest supported version are rejected, and that the rest decode to the same content.
+func TestMigrateVersions(t *testing.T) {
+
+	tests := []struct {
+		version     string // The version the project is loaded as
+		fixture     string // The fixture in testdata/versions holding the project in the format of that version
+		unsupported bool   // Whether the version is older than oldestSupportedVersion
+	}{
+		{version: "0.1.0", fixture: "0.6.0", unsupported: true},
+		{version: "0.5.0", fixture: "0.6.0", unsupported: true},
+		{version: "0.5.9", fixture: "0.6.0", unsupported: true},
+		{version: "0.5.10-beta", fixture: "0.6.0", unsupported: true},
+		{version: "0.6.0", fixture: "0.6.0"},
+		{version: "0.6.2", fixture: "0.6.0"},
+		{version: "0.7.0", fixture: "0.7.0"},
+		{version: "0.8.0", fixture: "0.8.0"},
+		{version: "0.9.3", fixture: "0.9.3"},
+		{version: "1.0.0", fixture: "1.0.0"},
+		{version: "1.5.3", fixture: "1.5.3"},
+	}
+
+	for _, test := range tests {
+
+		test := test
+
+		t.Run(test.version, func(t *testing.T) {
+
+			data, err := os.ReadFile(filepath.Join("testdata", "versions", "ldtk-"+test.fixture+".ldtk"))
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			data = []byte(strings.Replace(string(data), `"jsonVersion": "`+test.fixture+`"`, `"jsonVersion": "`+test.version+`"`, 1))
+
+			project, err := Read(data)
+
+			if test.unsupported {
+				if !errors.Is(err, ErrUnsupportedVersion) {
+					t.Fatalf("expected ErrUnsupportedVersion, got %v", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if project.JSONVersion != test.version {
+				t.Fatalf("project version is %s, not %s", project.JSONVersion, test.version)
+			}
+
+			checkMigratedProject(t, project)
+
+		})
+
+	}
+
+}
+
+// checkMigratedProject checks the content shared by the fixtures in testdata/versions.
+func checkMigratedProject(t *testing.T, project *Project) {
+
+	t.Helper()
+
+	if project.BGColorString != "#40465B" {
+		t.Errorf("project background color is %q", project.BGColorString)
+	}
+
+	level := project.Levels[0]
+
+	if level.IID == "" || level.BGColorString != "#40465B" {
+		t.Errorf("level IID is %q and background color is %q", level.IID, level.BGColorString)
+	}
+
+	collision := level.LayerByIdentifier("Collision")
+
+	for i, want := range []int{1, 0, 2, 1} {
+		integer := collision.IntegerAt(i%2, i/2)
+		got := 0
+		if integer != nil {
+			got = integer.Value
+		}
+		if got != want {
+			t.Errorf("IntGrid cell %d is %d, not %d", i, got, want)
+		}
+	}
+
+	if water := collision.Definition().IntGridValue(2); water == nil || water.Identifier != "Water" {
+		t.Errorf("IntGrid value 2 is %v, not Water", water)
+	}
+
+	ground := level.LayerByIdentifier("Ground")
+
+	for i, want := range []int{0, 2, 5, 6} {
+		tile := ground.TileAt(i%2, i/2)
+		if tile == nil || tile.ID != want || tile.Flip != TileFlip(i) {
+			t.Errorf("tile %d is %v, not tile %d with flip %d", i, tile, want, i)
+		}
+	}
+
+	player := level.LayerByIdentifier("Entities").Entities[0]
+
+	if player.IID == "" || player.Width != 16 || player.Height != 16 {
+		t.Errorf("entity IID is %q and size is %dx%d", player.IID, player.Width, player.Height)
+	}
+
+	wantRect := TileRect{X: 16, Y: 0, W: 16, H: 16, TilesetUID: 1}
+
+	for _, rect := range []*TileRect{player.TileRect, project.EntityDefinitionByIdentifier("Player").TileRect} {
+		if rect == nil || rect.X != wantRect.X || rect.Y != wantRect.Y || rect.W != wantRect.W || rect.H != wantRect.H || rect.TilesetUID != wantRect.TilesetUID {
+			t.Errorf("entity tile is %v, not %v", rect, wantRect)
+		}
+	}
+
+}
@@ -0,0 +1,199 @@
+package ldtkgo
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+)
+
+// MinimapOptions controls what's drawn by Project.GenerateMinimap.
+type MinimapOptions struct {
+	Atlases      map[int]*TilesetAtlas // Tileset images (keyed by Tileset ID, as returned by Project.OpenTilesetAtlases) used to draw the Levels' tile layers; if nil, only the Levels' background colors are drawn
+	OutlineColor color.Color           // Color of each Level's outline; if nil, Levels aren't outlined
+	MarkerTag    string                // Entities with this tag are drawn as markers on the minimap; leave blank to draw no markers
+	MarkerColor  color.Color           // Color of the Entity markers; defaults to white
+	MarkerSize   int                   // Size of the Entity markers in pixels on the minimap; defaults to 3
+}
+
+// GenerateMinimap draws an overview of the entire world to a new image, scaled by the factor given (i.e. 0.1 for a minimap one-tenth the size of
+// the world). Each Level is filled with its background color; depending on the options given, tile layers, Level outlines, and Entity markers
+// are drawn on top. If the options are nil, only the Levels' background colors are drawn. In linear world layouts, the Levels are laid out
+// one after another.
+func (project *Project) GenerateMinimap(scale float64, options *MinimapOptions) image.Image {
+
+	if options == nil {
+		options = &MinimapOptions{}
+	}
+
+	levelBounds := project.minimapLevelBounds()
+
+	world := image.Rectangle{}
+	for _, bounds := range levelBounds {
+		world = world.Union(bounds)
+	}
+
+	toMap := func(x, y float64) (int, int) {
+		return int(math.Floor((x - float64(world.Min.X)) * scale)), int(math.Floor((y - float64(world.Min.Y)) * scale))
+	}
+
+	mapRect := func(r image.Rectangle) image.Rectangle {
+		x0, y0 := toMap(float64(r.Min.X), float64(r.Min.Y))
+		x1, y1 := toMap(float64(r.Max.X), float64(r.Max.Y))
+		return image.Rect(x0, y0, x1, y1)
+	}
+
+	minimap := image.NewRGBA(mapRect(world))
+
+	for i, level := range project.Levels {
+
+		bounds := levelBounds[i]
+
+		if level.BGColor != nil {
+			draw.Draw(minimap, mapRect(bounds), image.NewUniform(level.BGColor), image.Point{}, draw.Src)
+		}
+
+		if options.Atlases != nil {
+
+			// Layers are drawn from the bottom up.
+			for layerIndex := len(level.Layers) - 1; layerIndex >= 0; layerIndex-- {
+
+				layer := level.Layers[layerIndex]
+
+				if layer.Tileset == nil || !layer.Visible {
+					continue
+				}
+
+				layerAtlas := options.Atlases[layer.Tileset.ID]
+
+				layer.ForEachTile(func(tile *Tile) {
+					atlas := layerAtlas
+					if tile.TilesetUID != 0 {
+						atlas = options.Atlases[tile.TilesetUID]
+					}
+					if atlas == nil {
+						return
+					}
+					src, _ := tile.SrcRect(layer)
+					x := bounds.Min.X + tile.Position[0] + layer.OffsetX
+					y := bounds.Min.Y + tile.Position[1] + layer.OffsetY
+					drawMinimapTile(minimap, mapRect(image.Rect(x, y, x+src.Dx(), y+src.Dy())), atlas, tile, src)
+				})
+
+			}
+
+		}
+
+		if options.OutlineColor != nil {
+			drawOutline(minimap, mapRect(bounds), options.OutlineColor)
+		}
+
+	}
+
+	if options.MarkerTag != "" {
+
+		markerColor := options.MarkerColor
+		if markerColor == nil {
+			markerColor = color.White
+		}
+
+		size := options.MarkerSize
+		if size <= 0 {
+			size = 3
+		}
+
+		for i, level := range project.Levels {
+			for _, entity := range level.EntitiesByTag(options.MarkerTag) {
+				ax, ay := entity.AnchorPoint()
+				mx, my := toMap(float64(levelBounds[i].Min.X+ax), float64(levelBounds[i].Min.Y+ay))
+				marker := image.Rect(mx-size/2, my-size/2, mx-size/2+size, my-size/2+size)
+				draw.Draw(minimap, marker, image.NewUniform(markerColor), image.Point{}, draw.Src)
+			}
+		}
+
+	}
+
+	return minimap
+
+}
+
+// minimapLevelBounds returns the bounds of each of the Project's Levels in the world. LDtk doesn't store world positions for Levels in linear
+// layouts, so in that case, the Levels are placed one after another.
+func (project *Project) minimapLevelBounds() []image.Rectangle {
+
+	bounds := make([]image.Rectangle, len(project.Levels))
+	offset := 0
+
+	for i, level := range project.Levels {
+		switch project.WorldLayout {
+		case WorldLayoutHorizontal:
+			bounds[i] = image.Rect(offset, 0, offset+level.Width, level.Height)
+			offset += level.Width
+		case WorldLayoutVertical:
+			bounds[i] = image.Rect(0, offset, level.Width, offset+level.Height)
+			offset += level.Height
+		default:
+			bounds[i] = level.WorldBounds()
+		}
+	}
+
+	return bounds
+
+}
+
+// drawMinimapTile draws the Tile given into the destination rectangle of the minimap, sampling the nearest pixel of the Tile's image (the
+// source rectangle given, within the atlas's Tileset) for each pixel of the minimap. Pixels that are mostly transparent are skipped.
+func drawMinimapTile(minimap *image.RGBA, dst image.Rectangle, atlas *TilesetAtlas, tile *Tile, src image.Rectangle) {
+
+	dst = dst.Intersect(minimap.Bounds())
+
+	if dst.Empty() || src.Empty() {
+		return
+	}
+
+	src = src.Add(atlas.Image.Bounds().Min)
+	w, h := float64(dst.Dx()), float64(dst.Dy())
+	srcW, srcH := src.Dx(), src.Dy()
+
+	for y := dst.Min.Y; y < dst.Max.Y; y++ {
+		for x := dst.Min.X; x < dst.Max.X; x++ {
+
+			sx := int((float64(x-dst.Min.X) + 0.5) / w * float64(srcW))
+			sy := int((float64(y-dst.Min.Y) + 0.5) / h * float64(srcH))
+
+			if tile.FlipX() {
+				sx = srcW - 1 - sx
+			}
+
+			if tile.FlipY() {
+				sy = srcH - 1 - sy
+			}
+
+			c := atlas.Image.At(src.Min.X+sx, src.Min.Y+sy)
+
+			if _, _, _, a := c.RGBA(); a >= 0x8000 {
+				minimap.Set(x, y, c)
+			}
+
+		}
+	}
+
+}
+
+func drawOutline(img *image.RGBA, rect image.Rectangle, c color.Color) {
+
+	if rect.Empty() {
+		return
+	}
+
+	for x := rect.Min.X; x < rect.Max.X; x++ {
+		img.Set(x, rect.Min.Y, c)
+		img.Set(x, rect.Max.Y-1, c)
+	}
+
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		img.Set(rect.Min.X, y, c)
+		img.Set(rect.Max.X-1, y, c)
+	}
+
+}
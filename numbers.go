@@ -0,0 +1,102 @@
+package ldtkgo
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// maxExactInt is the largest integer that a float64 can hold exactly; integers beyond it lose precision when decoded as float64s.
+const maxExactInt = 1 << 53
+
+// decodeValue decodes a JSON value (i.e. a Property's value) into an interface{} value as encoding/json does, except that integers that a
+// float64 can't hold exactly (i.e. coordinates in very large worlds) are decoded as int64s, so that they don't silently lose precision.
+func decodeValue(data []byte) (interface{}, error) {
+
+	var value interface{}
+
+	// Numbers are only decoded as json.Numbers when the value has one long enough to lose precision, which is rare.
+	if !hasLongNumber(data) {
+		err := json.Unmarshal(data, &value)
+		return value, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	return fromJSONNumbers(value), nil
+
+}
+
+// hasLongNumber returns whether the JSON data given has a run of 16 or more digits, which is the shortest a number can be to lose precision as a float64.
+func hasLongNumber(data []byte) bool {
+
+	run := 0
+
+	for _, b := range data {
+		if b >= '0' && b <= '9' {
+			run++
+			if run >= 16 {
+				return true
+			}
+		} else {
+			run = 0
+		}
+	}
+
+	return false
+
+}
+
+// fromJSONNumbers replaces the json.Numbers in the value given with float64s, or with int64s for integers that a float64 can't hold exactly.
+func fromJSONNumbers(value interface{}) interface{} {
+
+	switch v := value.(type) {
+
+	case json.Number:
+		if i, err := v.Int64(); err == nil && (i > maxExactInt || i < -maxExactInt) {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+
+	case []interface{}:
+		for i := range v {
+			v[i] = fromJSONNumbers(v[i])
+		}
+
+	case map[string]interface{}:
+		for key := range v {
+			v[key] = fromJSONNumbers(v[key])
+		}
+
+	}
+
+	return value
+
+}
+
+// numberValue returns the number given (a float64 or an int64 decoded from JSON) as a float64, and whether it's a number.
+func numberValue(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int64:
+		return float64(v), true
+	}
+	return 0, false
+}
+
+// int64Value returns the number given (a float64 or an int64 decoded from JSON) as an int64, and whether it's a number.
+func int64Value(value interface{}) (int64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return int64(v), true
+	case int64:
+		return v, true
+	}
+	return 0, false
+}
@@ -0,0 +1,106 @@
+package ldtkgo
+
+import "runtime"
+
+// LoadOption is an option that customizes how a Project is loaded by Open, Read, or ReadFrom.
+type LoadOption func(config *loadConfig)
+
+type loadConfig struct {
+	lowMemory           bool
+	onProgress          func(bytesRead, total int64)
+	onStage             func(stage string, done, total int)
+	usePropertyDefaults bool
+	strictSchema        bool
+	parallelLevels      int
+	allocator           Allocator
+	lazyLevels          bool
+}
+
+func newLoadConfig(options []LoadOption) *loadConfig {
+	config := &loadConfig{}
+	for _, option := range options {
+		if option != nil {
+			option(config)
+		}
+	}
+	return config
+}
+
+// LowMemory returns a LoadOption that loads the Project in a memory-lean way: the JSON is streamed and decoded one Level at a time rather
+// than held in memory in its entirety, and repeated strings (identifiers, types, paths, etc.) are shared rather than duplicated. This is slower than the default loading process, but lowers
+// peak and retained memory use for large projects.
+func LowMemory() LoadOption {
+	return func(config *loadConfig) {
+		config.lowMemory = true
+	}
+}
+
+// OnProgress returns a LoadOption that calls the function given as the project's data is read by Open, OpenContext, OpenURL, ReadFrom, or
+// ReadContext, i.e. to display a loading screen. total is the size of the data in bytes, or -1 if it isn't known ahead of time.
+func OnProgress(function func(bytesRead, total int64)) LoadOption {
+	return func(config *loadConfig) {
+		config.onProgress = function
+	}
+}
+
+// UsePropertyDefaults returns a LoadOption that makes PropertyByIdentifier on Levels and Entities fall back to the default value set for the
+// field in LDtk when the Property wasn't set (i.e. it's null, or missing from the Level or Entity), so defaults set in the editor don't have
+// to be repeated in code. This can also be toggled afterwards using Project.UsePropertyDefaults.
+func UsePropertyDefaults() LoadOption {
+	return func(config *loadConfig) {
+		config.usePropertyDefaults = true
+	}
+}
+
+// StrictSchema returns a LoadOption that records the keys in the project's JSON data that LDtk-Go doesn't read, which can then be retrieved
+// using Project.UnknownFields (i.e. to discover data that a new version of LDtk exports, but LDtk-Go drops). As the data is checked after
+// it's been decoded, the project's data is held in memory in its entirety, even if the LowMemory option is used.
+func StrictSchema() LoadOption {
+	return func(config *loadConfig) {
+		config.strictSchema = true
+	}
+}
+
+// ParallelLevels returns a LoadOption that decodes the project's Levels (and their Layers) concurrently using a pool of the number of
+// goroutines given (or the number of usable CPUs if workers is 0 or less), cutting load times for projects with many Levels on multi-core
+// machines. The indices shared between Levels (i.e. the lookups by IID) are still built afterwards on a single goroutine. This is ignored
+// when the JSON is streamed (see LowMemory and OnStage), or when the LazyLevels option is used.
+func ParallelLevels(workers int) LoadOption {
+	return func(config *loadConfig) {
+		if workers <= 0 {
+			workers = runtime.GOMAXPROCS(0)
+		}
+		config.parallelLevels = workers
+	}
+}
+
+// The stages of loading a Project reported by OnStage (and ReadWithProgress).
+const (
+	StageDecode     = "decode"     // The project's JSON is being decoded; done and total are in bytes (total is -1 if the size of the data isn't known ahead of time)
+	StageLevels     = "levels"     // The decoded Levels are being set up; done and total are numbers of Levels
+	StageLayers     = "layers"     // The decoded Levels are being set up; done and total are numbers of Layers across all Levels
+	StageReferences = "references" // References between Levels (i.e. entity references) are being resolved; done is 0 before and 1 after
+)
+
+// OnStage returns a LoadOption that calls the function given as each stage of loading the Project progresses (see the Stage constants), so
+// that a real loading bar can be drawn for large projects. Decoding progress is reported after each Level is decoded, and setup progress
+// after each Level is set up. To report decoding progress, the project's JSON is decoded one Level at a time, as with the LowMemory option
+// (unless StrictSchema is also used, in which case decoding is only reported when it starts and ends). Note that external level files
+// (.ldtkl) are loaded afterwards, and aren't reported.
+func OnStage(function func(stage string, done, total int)) LoadOption {
+	return func(config *loadConfig) {
+		config.onStage = function
+	}
+}
+
+// stage reports the progress of a stage of loading to the OnStage function, if there is one.
+func (config *loadConfig) stage(stage string, done, total int) {
+	if config.onStage != nil {
+		config.onStage(stage, done, total)
+	}
+}
+
+// streamed returns if the project's JSON should be decoded one top-level value (and Level) at a time.
+func (config *loadConfig) streamed() bool {
+	return (config.lowMemory || config.onStage != nil) && !config.strictSchema && !config.lazyLevels
+}
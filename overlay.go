@@ -0,0 +1,164 @@
+package ldtkgo
+
+import (
+	"errors"
+	"io/fs"
+	"sort"
+)
+
+// OverlayFS is a file system made of several file systems (roots) layered on top of each other, for moddable games: files in later roots
+// override the files with the same paths in earlier ones, so a mod can replace a game's project files (.ldtk and .ldtkl) and tileset images
+// by providing its own files at the same paths, without any code changes. An OverlayFS can be used anywhere a file system is accepted,
+// like Open, OpenAll, or a renderer:
+//
+//	fileSystem := ldtkgo.NewOverlayFS(os.DirFS("assets"), os.DirFS("mods/hd-tiles"), os.DirFS("mods/new-levels"))
+//	project, err := ldtkgo.Open("world.ldtk", fileSystem)
+type OverlayFS struct {
+	Roots []fs.FS // The file systems, from the bottom (the base game) to the top (the mod with the highest priority)
+}
+
+// NewOverlayFS creates a new OverlayFS from the file systems given, from the bottom to the top; files in later file systems override files in
+// earlier ones.
+func NewOverlayFS(roots ...fs.FS) *OverlayFS {
+	return &OverlayFS{Roots: roots}
+}
+
+// OpenOverlay loads the LDtk project from the filepath specified using an OverlayFS made of the file systems given (from the bottom to the top),
+// like Open. The project file, its external level files, and (when using the Project's FileSystem) its tileset images are each read from the
+// topmost file system that has them.
+func OpenOverlay(filepath string, roots []fs.FS, options ...LoadOption) (*Project, error) {
+	return Open(filepath, NewOverlayFS(roots...), options...)
+}
+
+// Open opens the named file from the topmost root that has it. Directories are opened from the topmost root as well; use ReadDir (or
+// fs.ReadDir) to list the contents of a directory across all of the roots.
+func (overlay *OverlayFS) Open(name string) (fs.File, error) {
+
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+
+	for i := len(overlay.Roots) - 1; i >= 0; i-- {
+
+		file, err := overlay.Roots[i].Open(name)
+
+		if err == nil {
+			return file, nil
+		}
+
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+
+	}
+
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+
+}
+
+// Stat returns information about the named file in the topmost root that has it.
+func (overlay *OverlayFS) Stat(name string) (fs.FileInfo, error) {
+
+	index, info, err := overlay.find(name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if index < 0 {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+	}
+
+	return info, nil
+
+}
+
+// ReadFile reads the named file from the topmost root that has it.
+func (overlay *OverlayFS) ReadFile(name string) ([]byte, error) {
+
+	index, _, err := overlay.find(name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if index < 0 {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+
+	return fs.ReadFile(overlay.Roots[index], name)
+
+}
+
+// ReadDir reads the named directory from every root that has it, returning the combined entries sorted by name. Where several roots have an
+// entry with the same name, the topmost root's entry is returned.
+func (overlay *OverlayFS) ReadDir(name string) ([]fs.DirEntry, error) {
+
+	entries := map[string]fs.DirEntry{}
+	found := false
+
+	for _, root := range overlay.Roots {
+
+		rootEntries, err := fs.ReadDir(root, name)
+
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		found = true
+
+		for _, entry := range rootEntries {
+			entries[entry.Name()] = entry
+		}
+
+	}
+
+	if !found {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+
+	sorted := make([]fs.DirEntry, 0, len(entries))
+
+	for _, entry := range entries {
+		sorted = append(sorted, entry)
+	}
+
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name() < sorted[j].Name() })
+
+	return sorted, nil
+
+}
+
+// Origin returns the index (in Roots) of the root the named file is read from, or -1 if none of the roots have it. This can be used to tell
+// which files have been overridden by mods.
+func (overlay *OverlayFS) Origin(name string) int {
+	index, _, _ := overlay.find(name)
+	return index
+}
+
+// find returns the index of the topmost root that has the named file and the file's information, or -1 if none of the roots have it.
+func (overlay *OverlayFS) find(name string) (int, fs.FileInfo, error) {
+
+	if !fs.ValidPath(name) {
+		return -1, nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrInvalid}
+	}
+
+	for i := len(overlay.Roots) - 1; i >= 0; i-- {
+
+		info, err := fs.Stat(overlay.Roots[i], name)
+
+		if err == nil {
+			return i, info, nil
+		}
+
+		if !errors.Is(err, fs.ErrNotExist) {
+			return -1, nil, err
+		}
+
+	}
+
+	return -1, nil, nil
+
+}
@@ -0,0 +1,190 @@
+package ldtkgo
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// ProjectSet represents a collection of Projects loaded together using OpenAll, for games that split their content across several project files.
+type ProjectSet struct {
+	Projects map[string]*Project // The loaded Projects, keyed by their path in the file system
+}
+
+// OpenAll loads every LDtk project (.ldtk) and external level file (.ldtkl) in the file system that matches the glob pattern given (see fs.Glob),
+// using a bounded pool of goroutines to load files concurrently. External level files are read into the Levels of the Projects that reference
+// them, so the Projects they belong to must also match the pattern. OpenAll returns the ProjectSet and the first error encountered, if any.
+func OpenAll(fileSystem fs.FS, glob string) (*ProjectSet, error) {
+
+	matches, err := fs.Glob(fileSystem, glob)
+
+	if err != nil {
+		return nil, err
+	}
+
+	projectPaths := []string{}
+	levelPaths := []string{}
+
+	for _, match := range matches {
+		switch path.Ext(match) {
+		case ".ldtk":
+			projectPaths = append(projectPaths, match)
+		case ".ldtkl":
+			levelPaths = append(levelPaths, match)
+		}
+	}
+
+	set := &ProjectSet{Projects: map[string]*Project{}}
+	projects := make([]*Project, len(projectPaths))
+
+	err = runWorkers(len(projectPaths), func(index int) error {
+		project, err := openProject(context.Background(), projectPaths[index], fileSystem, newLoadConfig(nil))
+		if err != nil {
+			return fmt.Errorf("%s: %w", projectPaths[index], err)
+		}
+		projects[index] = project
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	type externalLevel struct {
+		project *Project
+		level   *Level
+	}
+
+	owners := map[string]externalLevel{}
+
+	for i, project := range projects {
+		set.Projects[projectPaths[i]] = project
+		for _, level := range project.Levels {
+			if level.ExternalPath != "" {
+				owners[path.Join(path.Dir(projectPaths[i]), level.ExternalPath)] = externalLevel{project: project, level: level}
+			}
+		}
+	}
+
+	for _, levelPath := range levelPaths {
+		if _, exists := owners[levelPath]; !exists {
+			return nil, fmt.Errorf("%s: external level file doesn't belong to any loaded project", levelPath)
+		}
+	}
+
+	// Each Level is independent of the others, so they can be read concurrently; references between Levels are resolved afterwards.
+	err = runWorkers(len(levelPaths), func(index int) error {
+		data, err := fs.ReadFile(fileSystem, levelPaths[index])
+		if err != nil {
+			return err
+		}
+		owner := owners[levelPaths[index]]
+		if err := owner.project.readExternalLevel(owner.level, data); err != nil {
+			return fmt.Errorf("%s: %w", levelPaths[index], err)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, project := range projects {
+		project.resolveReferences()
+	}
+
+	return set, nil
+
+}
+
+// Paths returns the paths of the Projects in the ProjectSet, sorted alphabetically.
+func (set *ProjectSet) Paths() []string {
+	paths := make([]string, 0, len(set.Projects))
+	for p := range set.Projects {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// LevelByIdentifier returns the first Level with the identifier given across all Projects in the set (in path order), or nil if one isn't found.
+func (set *ProjectSet) LevelByIdentifier(identifier string) *Level {
+	for _, p := range set.Paths() {
+		if level := set.Projects[p].LevelByIdentifier(identifier); level != nil {
+			return level
+		}
+	}
+	return nil
+}
+
+// LevelByIID returns the Level with the IID given across all Projects in the set, or nil if one isn't found.
+func (set *ProjectSet) LevelByIID(iid string) *Level {
+	for _, p := range set.Paths() {
+		if level := set.Projects[p].LevelByIID(iid); level != nil {
+			return level
+		}
+	}
+	return nil
+}
+
+// EntityByIID returns the Entity with the IID given across all Projects in the set, or nil if one isn't found.
+func (set *ProjectSet) EntityByIID(iid string) *Entity {
+	for _, p := range set.Paths() {
+		if entity := set.Projects[p].EntityByIID(iid); entity != nil {
+			return entity
+		}
+	}
+	return nil
+}
+
+// runWorkers calls the work function once for each index from 0 to count-1 using a pool of goroutines sized to the number of usable CPUs,
+// returning the error for the lowest index that failed (rather than whichever failed first), so the same input always gives the same error.
+func runWorkers(count int, work func(index int) error) error {
+	return runWorkerPool(count, runtime.GOMAXPROCS(0), work)
+}
+
+// runWorkerPool is runWorkers with a pool of the number of goroutines given (at least 1).
+func runWorkerPool(count, workers int, work func(index int) error) error {
+
+	if workers < 1 {
+		workers = 1
+	}
+
+	if workers > count {
+		workers = count
+	}
+
+	jobs := make(chan int)
+	errs := make([]error, count)
+	wg := sync.WaitGroup{}
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range jobs {
+				errs[index] = work(index)
+			}
+		}()
+	}
+
+	for i := 0; i < count; i++ {
+		jobs <- i
+	}
+
+	close(jobs)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+
+}
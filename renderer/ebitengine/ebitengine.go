@@ -3,13 +3,19 @@ package ebitengine
 // eb is a render system that uses ebiten to draw LDTK levels to the screen.
 
 import (
-	"errors"
+	"fmt"
 	"image"
+	"image/color"
 	"io/fs"
+	"math"
+	"sort"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 	"github.com/solarlune/ldtkgo"
+	"github.com/solarlune/ldtkgo/camera"
+	"github.com/solarlune/ldtkgo/simple"
 
 	_ "image/png" // Importing for loading PNGs
 )
@@ -17,6 +23,10 @@ import (
 var ErrorBackgroundNotFound = "background image not found at given filepath"
 var ErrorTilesetNotFound = "tileset image not found at given filepath"
 var ErrorNoLevelGiven = "level pointer is nil"
+var ErrorLayerIndexOutOfRange = "layer index is out of range"
+var ErrorCompositeNotFound = "composite image not found for simple level"
+var ErrorEmptyRegion = "region to render is empty"
+var ErrorNoThumbnail = "level has no exported image"
 
 // Renderer is a struct that draws LDtk levels to an *ebiten.screen.
 type Renderer struct {
@@ -25,18 +35,51 @@ type Renderer struct {
 	CurrentTileset    *ebiten.Image
 	CurrentBackground *ebiten.Image
 	FileSystem        fs.FS
+	Composites        map[string]*ebiten.Image // Composite images of Levels loaded from a Super Simple Export, keyed by the Levels' paths
+	PackedTilesets    *ebiten.Image            // The single image all of the Tilesets were packed into by PackTilesets; nil if they haven't been packed
+	MaxTextures       int                      // The maximum number of tileset and background images to keep loaded; when more are loaded, the least recently used ones are unloaded, and loaded again when next drawn. If 0, there's no limit. This should be more than the number of images drawn each frame
+	SubImageCacheSize int                      // The maximum number of tile (and background) sub-images to cache, so that they aren't taken again each time they're drawn; when more are cached, the cache is cleared. If 0, 16384 are cached; if negative, sub-images aren't cached
+	Stats             RenderStats              // Counters of the work done by the last render call, i.e. for tuning the draw options; see DrawStats to show them on screen
+	project           *ldtkgo.Project          // The Project images are loaded for when they aren't loaded already
+	textureUses       map[textureKey]uint64    // When each tileset and background image was last used, for unloading the least recently used ones
+	textureUseCount   uint64                   // Incremented each time a tileset or background image is used
+	cellImage         *ebiten.Image            // A white pixel that IntGrid cells are drawn with
+	vertices          []ebiten.Vertex          // Vertices and indices of the tiles being drawn when batching, reused between layers
+	indices           []uint16
+	imageLoader       ImageLoadFunc                                       // The function used to load images, set using the ImageLoader Option
+	statsDepth        int                                                 // How many render calls are being made, so that the stats are only reset by the outermost one
+	statsStart        time.Time                                           // When the outermost render call started
+	subImages         map[*ebiten.Image]map[image.Rectangle]*ebiten.Image // The cached sub-images of each tileset and background image, by their rectangles
+	subImageCount     int                                                 // The number of sub-images cached
 }
 
+// maxBatchVertices is the number of vertices that can be drawn in one DrawTriangles call, as the indices are 16-bit.
+const maxBatchVertices = 1 << 16
+
 // New creates a new Ebitengine renderer. This is used to render a level to one or more *ebiten.Images.
-// The file system passed is the file system to use to load tileset images for the Renderer to use.
-func New(fs fs.FS, project *ldtkgo.Project) (*Renderer, error) {
+// The file system passed is the file system to use to load tileset images for the Renderer to use. The project can be nil if the Renderer
+// is only used to draw Levels loaded from a Super Simple Export. By default, an error is returned for the first image that can't be loaded;
+// see ContinueOnMissingAssets to load the rest of them instead. Tilesets that are atlases built into LDtk (see ldtkgo.Tileset.IsEmbedded) have
+// no image, and are skipped.
+func New(fs fs.FS, project *ldtkgo.Project, options ...Option) (*Renderer, error) {
+
+	config := newConfig(options)
 
 	renderer := &Renderer{
 		Backgrounds: map[string]*ebiten.Image{},
 		Tilesets:    map[string]*ebiten.Image{},
+		Composites:  map[string]*ebiten.Image{},
 		FileSystem:  fs,
+		project:     project,
+		imageLoader: config.imageLoader,
+	}
+
+	if project == nil {
+		return renderer, nil
 	}
 
+	var missing LoadErrors
+
 	for _, level := range project.Levels {
 
 		if level.BGImage == nil {
@@ -46,9 +89,14 @@ func New(fs fs.FS, project *ldtkgo.Project) (*Renderer, error) {
 		_, exists := renderer.Backgrounds[level.BGImage.Path]
 
 		if !exists {
-			img, _, err := ebitenutil.NewImageFromFileSystem(renderer.FileSystem, level.BGImage.Path)
+			img, err := renderer.loadImage(project, level.BGImage.Path)
 			if err != nil {
-				return nil, errors.New(ErrorBackgroundNotFound + ": [" + level.BGImage.Path + "]")
+				err = imageError(true, level.BGImage.Path, err)
+				if !config.continueOnMissingAssets {
+					return nil, err
+				}
+				missing = append(missing, err)
+				img = backgroundPlaceholder(level.BGImage)
 			}
 			renderer.Backgrounds[level.BGImage.Path] = img
 		}
@@ -57,29 +105,256 @@ func New(fs fs.FS, project *ldtkgo.Project) (*Renderer, error) {
 
 	for _, tileset := range project.Tilesets {
 
+		// Atlases built into LDtk (i.e. its icons) aren't saved alongside the project, so there's no image to load for them.
+		if tileset.Path == "" || tileset.IsEmbedded() {
+			continue
+		}
+
 		_, exists := renderer.Tilesets[tileset.Path]
 
 		if !exists {
-			img, _, err := ebitenutil.NewImageFromFileSystem(renderer.FileSystem, tileset.Path)
+			img, err := renderer.loadImage(project, tileset.Path)
 			if err != nil {
-				return nil, errors.New(ErrorTilesetNotFound + ": [" + tileset.Path + "]")
+				err = imageError(false, tileset.Path, err)
+				if !config.continueOnMissingAssets {
+					return nil, err
+				}
+				missing = append(missing, err)
+				img = placeholder(tileset.Width, tileset.Height)
 			}
 			renderer.Tilesets[tileset.Path] = img
 		}
 
 	}
 
+	if len(missing) > 0 {
+		return renderer, missing
+	}
+
 	return renderer, nil
 
 }
 
+// NewFromSource creates a new Ebitengine renderer like New, loading the tileset and background images from the AssetSource given (i.e. a
+// custom asset archive) rather than from a file system.
+func NewFromSource(source ldtkgo.AssetSource, project *ldtkgo.Project, options ...Option) (*Renderer, error) {
+	return New(ldtkgo.AssetFS(source), project, options...)
+}
+
+// NewPacked creates a new Ebitengine renderer like New, and then packs all of the project's tileset images into a single image using
+// PackTilesets.
+func NewPacked(fs fs.FS, project *ldtkgo.Project, options ...Option) (*Renderer, error) {
+
+	renderer, err := New(fs, project, options...)
+
+	if renderer == nil {
+		return nil, err
+	}
+
+	renderer.PackTilesets()
+
+	return renderer, err
+
+}
+
+// PackTilesets packs all of the Renderer's tileset images into a single image (PackedTilesets), replacing each entry in Tilesets with the
+// area of the packed image the tileset was copied to. As every tile is then drawn from the same source image, layers using different
+// tilesets no longer need texture switches between them, and can be batched together by Ebitengine. The tiles' source rectangles are
+// remapped to the packed image automatically when drawing; code that takes sub-images of the Tilesets directly should offset its rectangles by
+// the tileset image's Bounds().Min. PackTilesets should be called after loading the Renderer's tilesets, before
+// drawing; images added to Tilesets afterwards are drawn as usual, unpacked.
+func (r *Renderer) PackTilesets() {
+
+	paths := make([]string, 0, len(r.Tilesets))
+
+	for path, img := range r.Tilesets {
+		if img != nil {
+			paths = append(paths, path)
+		}
+	}
+
+	if len(paths) == 0 {
+		return
+	}
+
+	sort.Strings(paths)
+
+	sizes := make([]image.Point, len(paths))
+
+	for i, path := range paths {
+		sizes[i] = r.Tilesets[path].Bounds().Size()
+	}
+
+	placements, size := packRects(sizes)
+
+	packed := ebiten.NewImage(size.X, size.Y)
+
+	for i, path := range paths {
+
+		img := r.Tilesets[path]
+
+		opt := &ebiten.DrawImageOptions{}
+		opt.GeoM.Translate(float64(placements[i].Min.X-img.Bounds().Min.X), float64(placements[i].Min.Y-img.Bounds().Min.Y))
+		opt.Blend = ebiten.BlendCopy
+		packed.DrawImage(img, opt)
+
+		r.forgetSubImages(img)
+		r.Tilesets[path] = packed.SubImage(placements[i]).(*ebiten.Image)
+
+	}
+
+	r.PackedTilesets = packed
+
+}
+
+// packRects places rectangles of the sizes given into rows (tallest first) within an area that's roughly square, returning where each one was
+// placed (in the order given) and the size of the area needed to hold all of them.
+func packRects(sizes []image.Point) ([]image.Rectangle, image.Point) {
+
+	order := make([]int, len(sizes))
+	area := 0
+	width := 0
+
+	for i, size := range sizes {
+		order[i] = i
+		area += size.X * size.Y
+		if size.X > width {
+			width = size.X
+		}
+	}
+
+	if side := int(math.Ceil(math.Sqrt(float64(area)))); side > width {
+		width = side
+	}
+
+	sort.SliceStable(order, func(i, j int) bool { return sizes[order[i]].Y > sizes[order[j]].Y })
+
+	placements := make([]image.Rectangle, len(sizes))
+	total := image.Point{}
+	x, y, rowHeight := 0, 0, 0
+
+	for _, i := range order {
+
+		size := sizes[i]
+
+		if x > 0 && x+size.X > width {
+			x = 0
+			y += rowHeight
+			rowHeight = 0
+		}
+
+		placements[i] = image.Rectangle{image.Pt(x, y), image.Pt(x, y).Add(size)}
+
+		x += size.X
+
+		if size.Y > rowHeight {
+			rowHeight = size.Y
+		}
+
+		if x > total.X {
+			total.X = x
+		}
+
+		if y+size.Y > total.Y {
+			total.Y = y + size.Y
+		}
+
+	}
+
+	return placements, total
+
+}
+
+// tilesetRect returns the rectangle given (in the coordinates of a tileset's image) in the coordinates of the image it's drawn from, as
+// tilesets packed into one image by PackTilesets start at their position in the packed image, rather than at (0, 0).
+func tilesetRect(tileset *ebiten.Image, rect image.Rectangle) image.Rectangle {
+	return rect.Add(tileset.Bounds().Min)
+}
+
+// loadImage loads the image at the path given, which is relative to the project file. If the Renderer's file system is the same one the
+// project was loaded from, the path is resolved relative to the project file's directory; otherwise, it's used as-is.
+func (r *Renderer) loadImage(project *ldtkgo.Project, path string) (*ebiten.Image, error) {
+	if project == nil {
+		return r.openImage(path)
+	}
+	if resolved := project.ResolvePath(path); resolved != path {
+		if img, err := r.openImage(resolved); err == nil {
+			return img, nil
+		}
+	}
+	return r.openImage(path)
+}
+
+// openImage loads the image at the path given from the Renderer's file system, using its ImageLoadFunc if it has one.
+func (r *Renderer) openImage(path string) (*ebiten.Image, error) {
+
+	if r.imageLoader == nil {
+		img, _, err := ebitenutil.NewImageFromFileSystem(r.FileSystem, path)
+		return img, err
+	}
+
+	img, err := r.imageLoader(r.FileSystem, path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return ebiten.NewImageFromImage(img), nil
+
+}
+
+// LayerStyle customizes how the tiles of an individual layer are drawn.
+type LayerStyle struct {
+	DrawOptions   *ebiten.DrawImageOptions      // The options to use when drawing the layer's tiles instead of DrawOptions.LayerDrawOptions; if nil, LayerDrawOptions is used
+	Shader        *ebiten.Shader                // A shader to draw the layer's tiles with; each tile is the shader's first source image. If nil, the tiles are drawn normally
+	ShaderOptions *ebiten.DrawRectShaderOptions // The options (i.e. uniforms) to use with the Shader. The GeoM and source images are set for each tile; if nil, the ColorScale and Blend of the draw options are used
+}
+
+// LayerStyleFunc is a function that returns the LayerStyle to use for the layer given, or nil to draw the layer normally.
+type LayerStyleFunc func(layer *ldtkgo.Layer, layerIndex int) *LayerStyle
+
+// TileZOffsetFunc is a function that returns the z-offset of the tile given (i.e. for tiles tagged with an enum value, like tall grass or
+// fences), and whether the tile is split into the below and above passes at all. Tiles that aren't are always drawn in the below pass.
+type TileZOffsetFunc func(tile *ldtkgo.Tile, layer *ldtkgo.Layer) (zOffset float64, split bool)
+
+// TilePass indicates which of the tiles of the layers to draw when splitting them around a pivot (see DrawOptions.TileZOffsetCallback).
+type TilePass int
+
+const (
+	TilePassAll   TilePass = iota // Every tile is drawn
+	TilePassBelow                 // Only the tiles that are below the pivot (behind things at the pivot, like the player) are drawn
+	TilePassAbove                 // Only the tiles that are above the pivot (in front of things at the pivot) are drawn; the background isn't drawn in this pass
+)
+
+// EntityDrawFunc is a function that draws an Entity to the screen, using the draw options given.
+type EntityDrawFunc func(entity *ldtkgo.Entity, screen *ebiten.Image, drawOptions *ebiten.DrawImageOptions)
+
 type DrawOptions struct {
 	BackgroundColorFill   bool                                                             // Whether to fill the screen with the background color or not
 	BackgroundDraw        bool                                                             // Whether to render the background image when drawing the ldtkgo.Level
 	BackgroundDrawOptions *ebiten.DrawImageOptions                                         // The options to use when drawing the background
+	BackgroundParallaxX   float64                                                          // How much the background image lags behind the camera horizontally; 0 scrolls with the level, 1 stays fixed on screen
+	BackgroundParallaxY   float64                                                          // How much the background image lags behind the camera vertically; 0 scrolls with the level, 1 stays fixed on screen
+	BackgroundRepeatX     bool                                                             // Whether to tile the background image horizontally across the visible area
+	BackgroundRepeatY     bool                                                             // Whether to tile the background image vertically across the visible area
 	LayerDrawOptions      *ebiten.DrawImageOptions                                         // The options to use when drawing the tile layers
 	LayerDrawCallback     func(layer *ldtkgo.Layer, layerIndex int) bool                   // A callback that is called for each layer rendered. If the function returns false, the layer is not rendered.
 	TileDrawCallback      func(tile *ldtkgo.Tile, tileIndex int, layer *ldtkgo.Layer) bool // A callback that is called for each tile rendered. If the function returns false, the tile is not rendered.
+	YSortLayer            string                                                           // The identifier of a tile layer whose tiles are sorted together with the Level's Entities by their bottom edges and drawn in that order (i.e. so the player can walk behind trees); leave blank to disable
+	WorldView             image.Rectangle                                                  // The area of the world (in world coordinates) that's visible when drawing using RenderWorld; only Levels that overlap it are drawn. If empty, all Levels are drawn
+	LayerStyleCallback    LayerStyleFunc                                                   // A callback that is called for each layer rendered to customize how its tiles are drawn (i.e. with a different ColorScale, Blend, or shader). If the function returns nil, the layer is drawn normally.
+	EntityDrawCallback    EntityDrawFunc                                                   // A callback that draws each Entity when Y-sorting, given a copy of the layer draw options. If nil, the Entity's tile (if it has one) is drawn.
+	BatchTiles            bool                                                             // Whether to draw each layer's tiles with a single DrawTriangles call instead of a DrawImage call per tile, which is faster for dense layers. Layers drawn with a shader or Y-sorted aren't batched
+	IntGridCells          bool                                                             // Whether to draw the cells of IntGrid layers that have no auto-layer rules as rectangles filled with the colors of their IntGrid values, like LDtk does; this is useful for prototype maps
+	WorldDepthFilter      func(depth int) bool                                             // A callback that is called with the WorldDepth of each Level when drawing using RenderWorld. If the function returns false, the Level is not rendered; if nil, Levels at all depths are rendered
+	WorldDepthTint        func(depth int) ebiten.ColorScale                                // A callback that returns the ColorScale to tint Levels at the WorldDepth given with when drawing using RenderWorld (i.e. to darken the Levels below the current one); if nil, Levels aren't tinted
+	TileZOffsetCallback   TileZOffsetFunc                                                  // A callback that is called for each tile when drawing a TilePass other than TilePassAll. A split tile is above the pivot if its bottom edge plus its z-offset is greater than the PivotY; if nil, every tile is below the pivot
+	TilePass              TilePass                                                         // Which tiles to draw: all of them (the default), or only the ones below or above the PivotY. Render the Level with TilePassBelow, draw the player, and then render it again with TilePassAbove to draw tiles in front of the player
+	PivotY                float64                                                          // The Y position (in Level coordinates) that tiles are split around when drawing a TilePass other than TilePassAll (i.e. the bottom of the player)
+	BackgroundColor       func(level *ldtkgo.Level) color.Color                            // A callback that returns the color to fill the background with for the Level given, overriding its BGColor; if nil (or if the function returns nil), the Level's BGColor is used
+	BackgroundColorScale  ebiten.ColorScale                                                // The ColorScale the background color is modulated by before it's filled in (i.e. to darken it at night); the zero value leaves it unchanged
+	BackgroundColorBounds bool                                                             // Whether to fill the background color only within the Level's bounds (transformed by the LayerDrawOptions' GeoM) rather than filling the whole screen. RenderWorld always fills each Level's background color within its bounds
+	CullTiles             bool                                                             // Whether to skip drawing tiles that are entirely outside of the destination image, which saves draw calls (or vertices, when batching) when only part of a large Level is on screen. It's off by default, as checking each tile costs time when Levels are entirely on screen. The number of tiles culled is counted in the Renderer's Stats
 }
 
 // NewDefaultDrawOptions creates a RenderOptions struct with the default set of render options.
@@ -92,52 +367,333 @@ func NewDefaultDrawOptions() *DrawOptions {
 	}
 }
 
+// CameraGeoM returns a GeoM that draws the view of the camera.Camera given to the screen: it moves the Level by the Camera's Offset, and
+// then scales it by the Camera's Zoom.
+func CameraGeoM(cam *camera.Camera) ebiten.GeoM {
+
+	geoM := ebiten.GeoM{}
+	geoM.Translate(cam.Offset())
+
+	if cam.Zoom > 0 {
+		geoM.Scale(cam.Zoom, cam.Zoom)
+	}
+
+	return geoM
+
+}
+
+// ApplyCamera sets the draw options' layer and background GeoMs to draw the view of the camera.Camera given (replacing their previous GeoMs),
+// and sets the WorldView to the Camera's View, so that RenderWorld only draws the Levels the Camera can see.
+func (drawOptions *DrawOptions) ApplyCamera(cam *camera.Camera) {
+
+	if drawOptions.LayerDrawOptions == nil {
+		drawOptions.LayerDrawOptions = &ebiten.DrawImageOptions{}
+	}
+
+	if drawOptions.BackgroundDrawOptions == nil {
+		drawOptions.BackgroundDrawOptions = &ebiten.DrawImageOptions{}
+	}
+
+	geoM := CameraGeoM(cam)
+
+	drawOptions.LayerDrawOptions.GeoM = geoM
+	drawOptions.BackgroundDrawOptions.GeoM = geoM
+	drawOptions.WorldView = cam.View()
+
+}
+
 // Render draws an *ldtkgo.Level to the destination screen specified using render options to control the process.
 func (r *Renderer) Render(level *ldtkgo.Level, screen *ebiten.Image, drawOptions *DrawOptions) error {
 
 	if level == nil {
-		return errors.New(ErrorNoLevelGiven)
+		return ErrNoLevelGiven
 	}
 
+	r.beginStats()
+	defer r.endStats()
+
+	r.Stats.LevelsDrawn++
+
 	if drawOptions == nil {
 		drawOptions = NewDefaultDrawOptions()
 	}
 
-	if drawOptions.BackgroundColorFill {
-		screen.Fill(level.BGColor) // We want to use the BG Color when possible
+	if drawOptions.BackgroundColorFill && drawOptions.TilePass != TilePassAbove {
+		if drawOptions.BackgroundColorBounds {
+			fillLevelBounds(level, screen, drawOptions.LayerDrawOptions.GeoM, backgroundColor(level, drawOptions))
+		} else {
+			screen.Fill(backgroundColor(level, drawOptions)) // We want to use the BG Color when possible
+		}
 	}
 
-	if drawOptions.BackgroundDraw && level.BGImage != nil && level.BGImage.Path != "" {
-		r.CurrentBackground = r.Backgrounds[level.BGImage.Path]
-		opt := *drawOptions.BackgroundDrawOptions
-		opt.GeoM.Translate(-level.BGImage.CropRect[0], -level.BGImage.CropRect[1])
-		opt.GeoM.Scale(level.BGImage.ScaleX, level.BGImage.ScaleY)
-		screen.DrawImage(r.CurrentBackground, &opt)
+	if drawOptions.BackgroundDraw && drawOptions.TilePass != TilePassAbove && level.BGImage != nil && level.BGImage.Path != "" {
+		if r.CurrentBackground = r.texture(level.BGImage.Path, true); r.CurrentBackground != nil {
+			r.drawBackground(level, screen, drawOptions)
+		}
 	}
 
 	// Reverse sort the layers when drawing because in LDtk, the numbering order is from top-to-bottom, but the drawing order is from bottom-to-top.
 	for layerIndex := len(level.Layers) - 1; layerIndex >= 0; layerIndex-- {
+		r.drawLayer(level, layerIndex, screen, drawOptions)
+	}
+
+	return nil
+
+}
 
-		layer := level.Layers[layerIndex]
+// DrawLayer draws a single Layer of a Level to the destination screen, without the Level's background or its other Layers, i.e. to draw
+// things (like the player) in between the Layers of a Level. The draw options are used as they are by Render, including the layer callbacks.
+func (r *Renderer) DrawLayer(layer *ldtkgo.Layer, screen *ebiten.Image, drawOptions *DrawOptions) error {
 
-		if drawOptions.LayerDrawCallback != nil {
-			if !drawOptions.LayerDrawCallback(layer, layerIndex) {
-				continue
-			}
+	if layer == nil || layer.Level() == nil {
+		return ErrNoLevelGiven
+	}
+
+	if drawOptions == nil {
+		drawOptions = NewDefaultDrawOptions()
+	}
+
+	layerIndex := layer.Index()
+
+	if layerIndex < 0 {
+		return ErrLayerIndexOutOfRange
+	}
+
+	r.beginStats()
+	defer r.endStats()
+
+	r.drawLayer(layer.Level(), layerIndex, screen, drawOptions)
+
+	return nil
+
+}
+
+// drawLayer draws the Layer at the index given in the Level, unless the draw options' LayerDrawCallback skips it.
+func (r *Renderer) drawLayer(level *ldtkgo.Level, layerIndex int, screen *ebiten.Image, drawOptions *DrawOptions) {
+
+	layer := level.Layers[layerIndex]
+
+	if drawOptions.LayerDrawCallback != nil {
+		if !drawOptions.LayerDrawCallback(layer, layerIndex) {
+			return
+		}
+	}
+
+	var style *LayerStyle
+
+	if drawOptions.LayerStyleCallback != nil {
+		style = drawOptions.LayerStyleCallback(layer, layerIndex)
+	}
+
+	r.renderLayerWithStats(level, layer, func() {
+		if drawOptions.YSortLayer != "" && layer.Identifier == drawOptions.YSortLayer {
+			r.renderYSorted(level, layer, screen, drawOptions, style)
+		} else {
+			r.renderLayer(layer, screen, drawOptions, style)
 		}
+	})
+
+}
+
+// RenderSimpleLevel draws a Level loaded from a Super Simple Export (see the simple package) to the destination screen, using its pre-rendered
+// composite image rather than drawing it tile by tile. The composite image is loaded the first time the Level is drawn. Of the draw options,
+// only BackgroundColorFill, BackgroundColorScale, and LayerDrawOptions are used.
+func (r *Renderer) RenderSimpleLevel(level *simple.Level, screen *ebiten.Image, drawOptions *DrawOptions) error {
+
+	if level == nil {
+		return ErrNoLevelGiven
+	}
+
+	r.beginStats()
+	defer r.endStats()
 
-		if layer.Tileset != nil && layer.Tileset.Path != "" {
+	if drawOptions == nil {
+		drawOptions = NewDefaultDrawOptions()
+	}
+
+	if r.Composites == nil {
+		r.Composites = map[string]*ebiten.Image{}
+	}
 
-			r.CurrentTileset = r.Tilesets[layer.Tileset.Path]
-			// if tiles := layer.AllTiles(); len(tiles) > 0 {
+	composite, exists := r.Composites[level.Path]
 
-			tileIndex := 0
+	if !exists {
+		img, err := level.CompositeImage()
+		if err != nil {
+			return &ImageError{Kind: ErrCompositeNotFound, Path: level.Path, Err: err}
+		}
+		composite = ebiten.NewImageFromImage(img)
+		r.Composites[level.Path] = composite
+	}
 
-			layer.ForEachTile(func(tileData *ldtkgo.Tile) {
-				r.drawTile(tileData, tileIndex, layer, screen, drawOptions)
-				tileIndex++
+	if drawOptions.BackgroundColorFill {
+		screen.Fill(scaleColor(level.BGColor, drawOptions.BackgroundColorScale))
+	}
+
+	opt := &ebiten.DrawImageOptions{}
+	if drawOptions.LayerDrawOptions != nil {
+		*opt = *drawOptions.LayerDrawOptions
+	}
+
+	screen.DrawImage(composite, opt)
+
+	r.Stats.LevelsDrawn++
+	r.Stats.DrawCalls++
+
+	return nil
+
+}
+
+// renderYSorted draws the tiles of the layer given along with the Level's Entities, sorted by their bottom edges so that things lower on screen
+// are drawn in front.
+func (r *Renderer) renderYSorted(level *ldtkgo.Level, layer *ldtkgo.Layer, screen *ebiten.Image, drawOptions *DrawOptions, style *LayerStyle) {
+
+	type sortable struct {
+		bottom int
+		tile   *ldtkgo.Tile
+		entity *ldtkgo.Entity
+	}
+
+	sorted := []sortable{}
+
+	if layer.Tileset != nil && layer.Tileset.Path != "" {
+		if r.CurrentTileset = r.texture(layer.Tileset.Path, false); r.CurrentTileset != nil {
+			layer.ForEachTile(func(tile *ldtkgo.Tile) {
+				sorted = append(sorted, sortable{bottom: tile.Position[1] + layer.OffsetY + layer.GridSize, tile: tile})
 			})
+		}
+	}
+
+	// The Entities are drawn in the below pass only, so that they aren't drawn twice when splitting the tiles around a pivot.
+	if drawOptions.TilePass != TilePassAbove {
+		for _, entityLayer := range level.Layers {
+			for _, entity := range entityLayer.Entities {
+				sorted = append(sorted, sortable{bottom: entity.Bounds().Max.Y, entity: entity})
+			}
+		}
+	}
+
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].bottom < sorted[j].bottom })
+
+	tileIndex := 0
+
+	for _, item := range sorted {
+
+		if item.tile != nil {
+			r.drawTile(item.tile, tileIndex, layer, screen, drawOptions, style)
+			tileIndex++
+			continue
+		}
+
+		opt := *drawOptions.LayerDrawOptions
+
+		if drawOptions.EntityDrawCallback != nil {
+			drawOptions.EntityDrawCallback(item.entity, screen, &opt)
+		} else {
+			r.drawEntityTile(item.entity, screen, &opt)
+		}
+
+	}
 
+}
+
+// drawEntityTile draws the tile assigned to the Entity (if it has one) at the Entity's top-left corner.
+func (r *Renderer) drawEntityTile(entity *ldtkgo.Entity, screen *ebiten.Image, opt *ebiten.DrawImageOptions) {
+
+	tile := r.EntityTileImage(entity)
+
+	if tile == nil {
+		return
+	}
+
+	bounds := entity.Bounds()
+
+	geoM := ebiten.GeoM{}
+	geoM.Translate(float64(bounds.Min.X), float64(bounds.Min.Y))
+	geoM.Concat(opt.GeoM)
+	opt.GeoM = geoM
+
+	screen.DrawImage(tile, opt)
+
+	r.Stats.DrawCalls++
+
+}
+
+// LoadThumbnail loads the PNG image of the whole Level given exported by LDtk (see Level.ThumbnailPath) from the Renderer's file system, i.e.
+// for showing previews on a level select screen. The image isn't kept by the Renderer. An error is returned if the Project doesn't export
+// Level images, or if the image couldn't be loaded.
+func (r *Renderer) LoadThumbnail(level *ldtkgo.Level) (*ebiten.Image, error) {
+
+	path := level.ThumbnailPath()
+
+	if path == "" {
+		return nil, fmt.Errorf("%w: [%s]", ErrNoThumbnail, level.Identifier)
+	}
+
+	return r.loadImage(level.Project, path)
+
+}
+
+// TileImage returns the area of the loaded tileset image that the TileRect given covers (i.e. the value of a Tile Property, from
+// Property.AsTileRect), or nil if the TileRect is nil or its tileset isn't loaded.
+func (r *Renderer) TileImage(tileRect *ldtkgo.TileRect) *ebiten.Image {
+
+	if tileRect == nil || tileRect.Tileset == nil || tileRect.Tileset.Path == "" {
+		return nil
+	}
+
+	tileset := r.texture(tileRect.Tileset.Path, false)
+
+	if tileset == nil {
+		return nil
+	}
+
+	return r.subImage(tileset, tilesetRect(tileset, image.Rect(tileRect.X, tileRect.Y, tileRect.X+tileRect.W, tileRect.Y+tileRect.H)))
+
+}
+
+// EntityTileImage returns the image of the tile assigned to the Entity given, or nil if the Entity doesn't have one or its tileset isn't loaded.
+func (r *Renderer) EntityTileImage(entity *ldtkgo.Entity) *ebiten.Image {
+	return r.TileImage(entity.TileRect)
+}
+
+// RenderWorld draws every Level in the ldtkgo.Project to the destination screen at its position in the world (its WorldX and WorldY values),
+// so that scrolling across the boundaries between Levels is seamless. The draw options' GeoMs act as the camera, and if the draw options' WorldView
+// is set, only the Levels that overlap it are drawn. Each Level's background color is filled in within its own bounds. Levels are drawn in order
+// of their WorldDepth, from the bottom to the top (and in the order of the Project's Levels at the same depth), so that stacked Levels composite
+// correctly; the draw options' WorldDepthFilter and WorldDepthTint can be used to hide or tint the Levels at each depth.
+func (r *Renderer) RenderWorld(project *ldtkgo.Project, screen *ebiten.Image, drawOptions *DrawOptions) error {
+
+	if drawOptions == nil {
+		drawOptions = NewDefaultDrawOptions()
+	}
+
+	r.beginStats()
+	defer r.endStats()
+
+	levels := append([]*ldtkgo.Level{}, project.Levels...)
+
+	sort.SliceStable(levels, func(i, j int) bool { return levels[i].WorldDepth < levels[j].WorldDepth })
+
+	for _, level := range levels {
+
+		if !drawOptions.WorldView.Empty() && !level.WorldBounds().Overlaps(drawOptions.WorldView) {
+			r.Stats.LevelsCulled++
+			continue
+		}
+
+		if drawOptions.WorldDepthFilter != nil && !drawOptions.WorldDepthFilter(level.WorldDepth) {
+			continue
+		}
+
+		levelOptions := drawOptions
+
+		if drawOptions.WorldDepthTint != nil {
+			levelOptions = tintedDrawOptions(drawOptions, drawOptions.WorldDepthTint(level.WorldDepth))
+		}
+
+		if err := r.renderInWorld(level, screen, levelOptions); err != nil {
+			return err
 		}
 
 	}
@@ -146,7 +702,509 @@ func (r *Renderer) Render(level *ldtkgo.Level, screen *ebiten.Image, drawOptions
 
 }
 
-func (r *Renderer) drawTile(tileData *ldtkgo.Tile, tileIndex int, layer *ldtkgo.Layer, screen *ebiten.Image, drawOptions *DrawOptions) {
+// tintedDrawOptions returns a copy of the draw options given with the background and layer draw options' ColorScales scaled by the ColorScale given.
+func tintedDrawOptions(drawOptions *DrawOptions, tint ebiten.ColorScale) *DrawOptions {
+
+	tinted := *drawOptions
+
+	backgroundOptions := *drawOptions.BackgroundDrawOptions
+	backgroundOptions.ColorScale.ScaleWithColorScale(tint)
+	tinted.BackgroundDrawOptions = &backgroundOptions
+
+	layerOptions := *drawOptions.LayerDrawOptions
+	layerOptions.ColorScale.ScaleWithColorScale(tint)
+	tinted.LayerDrawOptions = &layerOptions
+
+	return &tinted
+
+}
+
+// RenderTransition draws two Levels at their positions in the world relative to each other, for transitioning between neighbouring Levels
+// (i.e. when the player moves from one room to the next). As with RenderWorld, the draw options' GeoMs act as the camera. progress ranges from
+// 0 (the start of the transition, in the from Level) to 1 (the end of the transition, in the to Level). RenderTransition returns the rectangle
+// (in world coordinates) that the camera should be clamped within at this point of the transition, which moves from the bounds of the from Level
+// to the bounds of the to Level.
+func (r *Renderer) RenderTransition(from, to *ldtkgo.Level, progress float64, screen *ebiten.Image, drawOptions *DrawOptions) (image.Rectangle, error) {
+
+	if from == nil || to == nil {
+		return image.Rectangle{}, ErrNoLevelGiven
+	}
+
+	r.beginStats()
+	defer r.endStats()
+
+	if drawOptions == nil {
+		drawOptions = NewDefaultDrawOptions()
+	}
+
+	progress = math.Max(0, math.Min(progress, 1))
+
+	for _, level := range []*ldtkgo.Level{from, to} {
+		if err := r.renderInWorld(level, screen, drawOptions); err != nil {
+			return image.Rectangle{}, err
+		}
+	}
+
+	fromBounds, toBounds := from.WorldBounds(), to.WorldBounds()
+
+	lerp := func(a, b int) int {
+		return int(math.Round(float64(a) + float64(b-a)*progress))
+	}
+
+	clamp := image.Rect(
+		lerp(fromBounds.Min.X, toBounds.Min.X),
+		lerp(fromBounds.Min.Y, toBounds.Min.Y),
+		lerp(fromBounds.Max.X, toBounds.Max.X),
+		lerp(fromBounds.Max.Y, toBounds.Max.Y),
+	)
+
+	return clamp, nil
+
+}
+
+// renderInWorld draws the Level at its position in the world, filling in its background color within its own bounds.
+func (r *Renderer) renderInWorld(level *ldtkgo.Level, screen *ebiten.Image, drawOptions *DrawOptions) error {
+
+	levelOptions := *drawOptions
+	levelOptions.BackgroundColorFill = false
+
+	backgroundOptions := *drawOptions.BackgroundDrawOptions
+	backgroundOptions.GeoM = worldGeoM(level, drawOptions.BackgroundDrawOptions.GeoM)
+	levelOptions.BackgroundDrawOptions = &backgroundOptions
+
+	layerOptions := *drawOptions.LayerDrawOptions
+	layerOptions.GeoM = worldGeoM(level, drawOptions.LayerDrawOptions.GeoM)
+	levelOptions.LayerDrawOptions = &layerOptions
+
+	if drawOptions.BackgroundColorFill {
+		fillLevelBounds(level, screen, layerOptions.GeoM, backgroundColor(level, drawOptions))
+	}
+
+	return r.Render(level, screen, &levelOptions)
+
+}
+
+// fillLevelBounds fills the area of the screen covered by the Level given when it's drawn using the GeoM given with the color given. Note
+// that this assumes the GeoM isn't rotated.
+func fillLevelBounds(level *ldtkgo.Level, screen *ebiten.Image, geoM ebiten.GeoM, fillColor color.Color) {
+
+	x0, y0 := geoM.Apply(0, 0)
+	x1, y1 := geoM.Apply(float64(level.Width), float64(level.Height))
+
+	// image.Rect swaps the corners if needed, so flipping the camera is fine.
+	fillRect := image.Rect(int(math.Round(x0)), int(math.Round(y0)), int(math.Round(x1)), int(math.Round(y1))).Intersect(screen.Bounds())
+
+	if !fillRect.Empty() {
+		screen.SubImage(fillRect).(*ebiten.Image).Fill(fillColor)
+	}
+
+}
+
+// backgroundColor returns the color the background of the Level given is filled with: the color returned by the draw options' BackgroundColor
+// callback (or the Level's BGColor), modulated by the draw options' BackgroundColorScale.
+func backgroundColor(level *ldtkgo.Level, drawOptions *DrawOptions) color.Color {
+
+	var bgColor color.Color
+
+	if drawOptions.BackgroundColor != nil {
+		bgColor = drawOptions.BackgroundColor(level)
+	}
+
+	if bgColor == nil {
+		bgColor = level.BGColor
+	}
+
+	return scaleColor(bgColor, drawOptions.BackgroundColorScale)
+
+}
+
+// scaleColor returns the color given modulated by the ColorScale given.
+func scaleColor(c color.Color, scale ebiten.ColorScale) color.Color {
+
+	if c == nil {
+		c = color.Transparent
+	}
+
+	if scale == (ebiten.ColorScale{}) {
+		return c
+	}
+
+	// The color's components are premultiplied, as are the ColorScale's.
+	cr, cg, cb, ca := c.RGBA()
+
+	channel := func(value uint32, scale float32) uint16 {
+		return uint16(math.Max(0, math.Min(0xffff, float64(value)*float64(scale))))
+	}
+
+	return color.RGBA64{
+		R: channel(cr, scale.R()),
+		G: channel(cg, scale.G()),
+		B: channel(cb, scale.B()),
+		A: channel(ca, scale.A()),
+	}
+
+}
+
+// worldGeoM returns a GeoM that moves a Level to its position in the world before applying the camera GeoM given.
+func worldGeoM(level *ldtkgo.Level, camera ebiten.GeoM) ebiten.GeoM {
+	geoM := ebiten.GeoM{}
+	geoM.Translate(float64(level.WorldX), float64(level.WorldY))
+	geoM.Concat(camera)
+	return geoM
+}
+
+// RenderLayerToImage draws the tiles of the Layer at the index given in the ldtkgo.Level to a new *ebiten.Image the size of the Level, so that
+// individual layers can be drawn separately (i.e. above the player) or processed further (i.e. blurred).
+func (r *Renderer) RenderLayerToImage(level *ldtkgo.Level, layerIndex int) (*ebiten.Image, error) {
+
+	if level == nil {
+		return nil, ErrNoLevelGiven
+	}
+
+	if layerIndex < 0 || layerIndex >= len(level.Layers) {
+		return nil, ErrLayerIndexOutOfRange
+	}
+
+	r.beginStats()
+	defer r.endStats()
+
+	img := ebiten.NewImage(level.Width, level.Height)
+
+	layer := level.Layers[layerIndex]
+
+	r.renderLayerWithStats(level, layer, func() {
+		r.renderLayer(layer, img, NewDefaultDrawOptions(), nil)
+	})
+
+	return img, nil
+
+}
+
+// RenderToImages draws each of the Layers of the ldtkgo.Level to its own new *ebiten.Image the size of the Level (as RenderLayerToImage does),
+// returning the images in the same order as the Level's Layers, i.e. for compositing them with effects between them.
+func (r *Renderer) RenderToImages(level *ldtkgo.Level) ([]*ebiten.Image, error) {
+
+	if level == nil {
+		return nil, ErrNoLevelGiven
+	}
+
+	r.beginStats()
+	defer r.endStats()
+
+	images := make([]*ebiten.Image, len(level.Layers))
+
+	for i := range level.Layers {
+
+		img, err := r.RenderLayerToImage(level, i)
+
+		if err != nil {
+			return nil, err
+		}
+
+		images[i] = img
+
+	}
+
+	return images, nil
+
+}
+
+// RenderRegion draws the area of the ldtkgo.Level within the rectangle given (in pixels, relative to the Level's top-left corner) to a new
+// *ebiten.Image the size of the rectangle, including the Level's background; tiles that cross the rectangle's edges are clipped. This can be
+// used to bake room-sized pieces of a Level (i.e. for screenshots, minimap chunks, or snapshots for transitions). Parts of the rectangle
+// outside of the Level are left transparent.
+func (r *Renderer) RenderRegion(level *ldtkgo.Level, rect image.Rectangle) (*ebiten.Image, error) {
+
+	if level == nil {
+		return nil, ErrNoLevelGiven
+	}
+
+	if rect.Empty() {
+		return nil, ErrEmptyRegion
+	}
+
+	r.beginStats()
+	defer r.endStats()
+
+	img := ebiten.NewImage(rect.Dx(), rect.Dy())
+
+	// Only the part of the image that the Level covers is filled with its background color.
+	if levelArea := image.Rect(0, 0, level.Width, level.Height).Intersect(rect); !levelArea.Empty() && level.BGColor != nil {
+		img.SubImage(levelArea.Sub(rect.Min)).(*ebiten.Image).Fill(level.BGColor)
+	}
+
+	drawOptions := NewDefaultDrawOptions()
+	drawOptions.BackgroundColorFill = false
+	drawOptions.LayerDrawOptions.GeoM.Translate(float64(-rect.Min.X), float64(-rect.Min.Y))
+	drawOptions.BackgroundDrawOptions.GeoM.Translate(float64(-rect.Min.X), float64(-rect.Min.Y))
+
+	if err := r.Render(level, img, drawOptions); err != nil {
+		return nil, err
+	}
+
+	return img, nil
+
+}
+
+func (r *Renderer) renderLayer(layer *ldtkgo.Layer, screen *ebiten.Image, drawOptions *DrawOptions, style *LayerStyle) {
+
+	if drawOptions.IntGridCells && layer.Type == ldtkgo.LayerTypeIntGrid {
+		if def := layer.Definition(); def != nil && (len(def.AutoRuleGroups) == 0 || layer.Tileset == nil) {
+			if drawOptions.TilePass != TilePassAbove {
+				r.renderIntGridCells(layer, def, screen, drawOptions, style)
+			}
+			return
+		}
+	}
+
+	if layer.Tileset == nil || layer.Tileset.Path == "" {
+		return
+	}
+
+	r.CurrentTileset = r.texture(layer.Tileset.Path, false)
+
+	if r.CurrentTileset == nil {
+		return
+	}
+
+	if drawOptions.BatchTiles && (style == nil || style.Shader == nil) {
+		r.renderLayerBatched(layer, screen, drawOptions, style)
+		return
+	}
+
+	tileIndex := 0
+
+	layer.ForEachTile(func(tileData *ldtkgo.Tile) {
+		r.drawTile(tileData, tileIndex, layer, screen, drawOptions, style)
+		tileIndex++
+	})
+
+}
+
+// renderIntGridCells draws each of the IntGrid cells of the layer given as a rectangle filled with its IntGrid value's color.
+func (r *Renderer) renderIntGridCells(layer *ldtkgo.Layer, def *ldtkgo.LayerDefinition, screen *ebiten.Image, drawOptions *DrawOptions, style *LayerStyle) {
+
+	if r.cellImage == nil {
+		r.cellImage = ebiten.NewImage(1, 1)
+		r.cellImage.Fill(color.White)
+	}
+
+	layerDrawOptions := drawOptions.LayerDrawOptions
+
+	if style != nil && style.DrawOptions != nil {
+		layerDrawOptions = style.DrawOptions
+	}
+
+	for _, integer := range layer.IntGrid {
+
+		value := def.IntGridValue(integer.Value)
+
+		if value == nil || value.Color == nil {
+			continue
+		}
+
+		opt := *layerDrawOptions
+
+		opt.GeoM = ebiten.GeoM{}
+		opt.GeoM.Scale(float64(layer.GridSize), float64(layer.GridSize))
+		opt.GeoM.Translate(float64(integer.Position[0]+layer.OffsetX), float64(integer.Position[1]+layer.OffsetY))
+		opt.GeoM.Concat(layerDrawOptions.GeoM)
+
+		opt.ColorScale.Reset()
+		opt.ColorScale.ScaleWithColor(value.Color)
+		opt.ColorScale.ScaleWithColorScale(layerDrawOptions.ColorScale)
+
+		screen.DrawImage(r.cellImage, &opt)
+
+		r.Stats.DrawCalls++
+
+	}
+
+}
+
+// renderLayerBatched draws the tiles of the layer given by building the vertices of all of them and drawing them with a single DrawTriangles
+// call (or more, if there are too many tiles for one call), rather than with a DrawImage call per tile.
+func (r *Renderer) renderLayerBatched(layer *ldtkgo.Layer, screen *ebiten.Image, drawOptions *DrawOptions, style *LayerStyle) {
+
+	layerDrawOptions := drawOptions.LayerDrawOptions
+
+	if style != nil && style.DrawOptions != nil {
+		layerDrawOptions = style.DrawOptions
+	}
+
+	// The color scale of the draw options is premultiplied, so the vertex colors are as well.
+	colorScale := layerDrawOptions.ColorScale
+	colorR, colorG, colorB, colorA := colorScale.R(), colorScale.G(), colorScale.B(), colorScale.A()
+
+	triangleOptions := &ebiten.DrawTrianglesOptions{
+		ColorScaleMode: ebiten.ColorScaleModePremultipliedAlpha,
+		Blend:          layerDrawOptions.Blend,
+		Filter:         layerDrawOptions.Filter,
+	}
+
+	r.vertices = r.vertices[:0]
+	r.indices = r.indices[:0]
+
+	// Tiles that override their Layer's Tileset are drawn from a different image, so the batch is drawn whenever the image changes.
+	source := r.CurrentTileset
+
+	flush := func() {
+		if len(r.indices) > 0 {
+			screen.DrawTriangles(r.vertices, r.indices, source, triangleOptions)
+			r.Stats.DrawCalls++
+		}
+		r.vertices = r.vertices[:0]
+		r.indices = r.indices[:0]
+	}
+
+	screenBounds := screen.Bounds()
+
+	tileIndex := 0
+
+	layer.ForEachTile(func(tileData *ldtkgo.Tile) {
+
+		index := tileIndex
+		tileIndex++
+
+		if !inTilePass(tileData, layer, drawOptions) {
+			return
+		}
+
+		if drawOptions.TileDrawCallback != nil && !drawOptions.TileDrawCallback(tileData, index, layer) {
+			return
+		}
+
+		tileset := r.tileTexture(tileData)
+
+		if tileset == nil {
+			return
+		}
+
+		srcRect, transform := tileData.SrcRect(layer)
+		srcRect = tilesetRect(tileset, srcRect)
+		geoM := tileGeoM(tileData, transform, layer, layerDrawOptions.GeoM)
+
+		w, h := srcRect.Dx(), srcRect.Dy()
+
+		if drawOptions.CullTiles && !tileVisible(geoM, w, h, screenBounds) {
+			r.Stats.TilesCulled++
+			return
+		}
+
+		if tileset != source {
+			flush()
+			source = tileset
+		}
+
+		if len(r.vertices)+4 > maxBatchVertices {
+			flush()
+		}
+
+		base := uint16(len(r.vertices))
+
+		for _, corner := range [4]image.Point{{0, 0}, {w, 0}, {0, h}, {w, h}} {
+			x, y := geoM.Apply(float64(corner.X), float64(corner.Y))
+			r.vertices = append(r.vertices, ebiten.Vertex{
+				DstX:   float32(x),
+				DstY:   float32(y),
+				SrcX:   float32(srcRect.Min.X + corner.X),
+				SrcY:   float32(srcRect.Min.Y + corner.Y),
+				ColorR: colorR,
+				ColorG: colorG,
+				ColorB: colorB,
+				ColorA: colorA,
+			})
+		}
+
+		r.indices = append(r.indices, base, base+1, base+2, base+1, base+3, base+2)
+
+		r.Stats.TilesDrawn++
+
+	})
+
+	flush()
+
+}
+
+func (r *Renderer) drawBackground(level *ldtkgo.Level, screen *ebiten.Image, drawOptions *DrawOptions) {
+
+	bg := level.BGImage
+
+	// LDtk has already worked out the crop, scale, and position of the image according to its positioning mode, so we just need to apply them.
+	crop := image.Rect(int(bg.CropRect[0]), int(bg.CropRect[1]), int(bg.CropRect[0]+bg.CropRect[2]), int(bg.CropRect[1]+bg.CropRect[3]))
+
+	img := r.subImage(r.CurrentBackground, crop)
+
+	w := float64(crop.Dx()) * bg.ScaleX
+	h := float64(crop.Dy()) * bg.ScaleY
+
+	if w <= 0 || h <= 0 {
+		return
+	}
+
+	x, y := bg.TopLeftX, bg.TopLeftY
+
+	// The area of the level that's visible on screen, found by undoing the background's transformation (i.e. the camera).
+	view := image.Rect(0, 0, level.Width, level.Height)
+
+	inverse := drawOptions.BackgroundDrawOptions.GeoM
+	if inverse.IsInvertible() {
+		inverse.Invert()
+		bounds := screen.Bounds()
+		minX, minY := inverse.Apply(float64(bounds.Min.X), float64(bounds.Min.Y))
+		maxX, maxY := inverse.Apply(float64(bounds.Max.X), float64(bounds.Max.Y))
+		view = image.Rect(int(math.Floor(minX)), int(math.Floor(minY)), int(math.Ceil(maxX)), int(math.Ceil(maxY)))
+
+		// Parallax moves the background along with the camera, so that it appears to scroll more slowly than the level.
+		x += minX * drawOptions.BackgroundParallaxX
+		y += minY * drawOptions.BackgroundParallaxY
+	}
+
+	// Repeating backgrounds are tiled across the entire level (or the visible area when repeating is forced on by the draw options),
+	// aligned to the image's original position.
+	startX, endX := x, x+w
+	startY, endY := y, y+h
+
+	if bg.Mode == ldtkgo.BGPositionRepeat || drawOptions.BackgroundRepeatX {
+		edge := 0.0
+		endX = float64(level.Width)
+		if drawOptions.BackgroundRepeatX {
+			edge = math.Min(edge, float64(view.Min.X))
+			endX = math.Max(endX, float64(view.Max.X))
+		}
+		startX = x - math.Ceil((x-edge)/w)*w
+	}
+
+	if bg.Mode == ldtkgo.BGPositionRepeat || drawOptions.BackgroundRepeatY {
+		edge := 0.0
+		endY = float64(level.Height)
+		if drawOptions.BackgroundRepeatY {
+			edge = math.Min(edge, float64(view.Min.Y))
+			endY = math.Max(endY, float64(view.Max.Y))
+		}
+		startY = y - math.Ceil((y-edge)/h)*h
+	}
+
+	for ty := startY; ty < endY; ty += h {
+		for tx := startX; tx < endX; tx += w {
+			geoM := ebiten.GeoM{}
+			geoM.Scale(bg.ScaleX, bg.ScaleY)
+			geoM.Translate(tx, ty)
+			geoM.Concat(drawOptions.BackgroundDrawOptions.GeoM)
+			opt := *drawOptions.BackgroundDrawOptions
+			opt.GeoM = geoM
+			screen.DrawImage(img, &opt)
+			r.Stats.DrawCalls++
+		}
+	}
+
+}
+
+func (r *Renderer) drawTile(tileData *ldtkgo.Tile, tileIndex int, layer *ldtkgo.Layer, screen *ebiten.Image, drawOptions *DrawOptions, style *LayerStyle) {
+
+	if !inTilePass(tileData, layer, drawOptions) {
+		return
+	}
 
 	if drawOptions.TileDrawCallback != nil {
 		if !drawOptions.TileDrawCallback(tileData, tileIndex, layer) {
@@ -154,37 +1212,116 @@ func (r *Renderer) drawTile(tileData *ldtkgo.Tile, tileIndex int, layer *ldtkgo.
 		}
 	}
 
+	tileset := r.tileTexture(tileData)
+
+	if tileset == nil {
+		return
+	}
+
+	srcRect, transform := tileData.SrcRect(layer)
+
+	layerDrawOptions := drawOptions.LayerDrawOptions
+
+	if style != nil && style.DrawOptions != nil {
+		layerDrawOptions = style.DrawOptions
+	}
+
+	geoM := tileGeoM(tileData, transform, layer, layerDrawOptions.GeoM)
+
+	if drawOptions.CullTiles && !tileVisible(geoM, srcRect.Dx(), srcRect.Dy(), screen.Bounds()) {
+		r.Stats.TilesCulled++
+		return
+	}
+
 	// Subimage the Tile from the Tileset
-	tile := r.CurrentTileset.SubImage(image.Rect(tileData.Src[0], tileData.Src[1], tileData.Src[0]+layer.GridSize, tileData.Src[1]+layer.GridSize)).(*ebiten.Image)
+	tile := r.subImage(tileset, tilesetRect(tileset, srcRect))
 
-	geoM := ebiten.GeoM{}
+	opt := *layerDrawOptions // Clone the draw options used to render the tiles, because we'll be transforming them
 
-	// We have to offset the tile to be centered before flipping
-	geoM.Translate(float64(-layer.GridSize/2), float64(-layer.GridSize/2))
+	opt.GeoM = geoM
+
+	r.Stats.TilesDrawn++
+	r.Stats.DrawCalls++
 
-	// Handle flipping; first bit in byte is horizontal flipping, second is vertical flipping.
+	if style != nil && style.Shader != nil {
+
+		shaderOpt := ebiten.DrawRectShaderOptions{ColorScale: opt.ColorScale, Blend: opt.Blend}
+		if style.ShaderOptions != nil {
+			shaderOpt = *style.ShaderOptions
+		}
 
-	if tileData.FlipX() {
-		geoM.Scale(-1, 1)
+		shaderOpt.GeoM = opt.GeoM
+		shaderOpt.Images[0] = tile
+
+		screen.DrawRectShader(srcRect.Dx(), srcRect.Dy(), style.Shader, &shaderOpt)
+		return
+
+	}
+
+	// Finally, draw the tile to the Result image.
+	screen.DrawImage(tile, &opt)
+
+}
+
+// inTilePass returns whether the Tile given is drawn in the draw options' TilePass: a Tile is above the pivot if the TileZOffsetCallback splits
+// it, and its bottom edge plus its z-offset is greater than the PivotY.
+func inTilePass(tileData *ldtkgo.Tile, layer *ldtkgo.Layer, drawOptions *DrawOptions) bool {
+
+	if drawOptions.TilePass == TilePassAll {
+		return true
 	}
-	if tileData.FlipY() {
-		geoM.Scale(1, -1)
+
+	above := false
+
+	if drawOptions.TileZOffsetCallback != nil {
+		if zOffset, split := drawOptions.TileZOffsetCallback(tileData, layer); split {
+			bottom := float64(tileData.Position[1] + layer.OffsetY + layer.GridSize)
+			above = bottom+zOffset > drawOptions.PivotY
+		}
 	}
 
-	// Undo offsetting
-	geoM.Translate(float64(layer.GridSize/2), float64(layer.GridSize/2))
+	return above == (drawOptions.TilePass == TilePassAbove)
 
-	geoM.Concat(drawOptions.LayerDrawOptions.GeoM)
+}
 
-	opt := *drawOptions.LayerDrawOptions // Clone the draw options used to render the tiles, because we'll be transforming them
+// tileTexture returns the tileset image the Tile given is drawn from: the image of the Tile's own Tileset if it overrides its Layer's, or the
+// current tileset (the Layer's) otherwise. nil is returned if the image can't be found.
+func (r *Renderer) tileTexture(tile *ldtkgo.Tile) *ebiten.Image {
 
-	opt.GeoM = geoM
+	if tile.TilesetUID == 0 {
+		return r.CurrentTileset
+	}
+
+	tileset := tile.Tileset()
+
+	if tileset == nil || tileset.Path == "" {
+		return nil
+	}
+
+	return r.texture(tileset.Path, false)
+
+}
+
+// tileGeoM returns the GeoM used to draw the Tile given: the Tile is flipped and rotated according to its transform, moved to its position
+// in the layer, and then transformed by the layer draw options' GeoM given.
+func tileGeoM(tileData *ldtkgo.Tile, transform ldtkgo.TileTransform, layer *ldtkgo.Layer, camera ebiten.GeoM) ebiten.GeoM {
+
+	// Handle flipping
+	geoM := ebiten.GeoM{}
+
+	for i := 0; i < 2; i++ {
+		for j := 0; j < 3; j++ {
+			geoM.SetElement(i, j, transform.Matrix[i][j])
+		}
+	}
 
 	// Move tile to final position; note that slightly unlike LDtk, layer offsets in LDtk-Go are added directly into the final tiles' X and Y positions. This means that with this renderer,
 	// if a layer's offset pushes tiles outside of the layer's render Result image, they will be cut off. On LDtk, the tiles are still rendered, of course.
-	opt.GeoM.Translate(float64(tileData.Position[0]+layer.OffsetX), float64(tileData.Position[1]+layer.OffsetY))
+	geoM.Translate(float64(tileData.Position[0]+layer.OffsetX), float64(tileData.Position[1]+layer.OffsetY))
 
-	// Finally, draw the tile to the Result image.
-	screen.DrawImage(tile, &opt)
+	// The layer draw options' transformation is applied last, so that it can act as a camera (i.e. scaling the tiles' positions along with the tiles).
+	geoM.Concat(camera)
+
+	return geoM
 
 }
@@ -0,0 +1,84 @@
+package ebitengine
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/solarlune/ldtkgo"
+	"github.com/solarlune/ldtkgo/internal/gen"
+)
+
+// benchLevel returns a Renderer and a generated Level of 64x36 cells to draw with it; the generated project's tileset image is replaced with
+// a blank image of the same size, so nothing has to be loaded from disk.
+func benchLevel(b *testing.B) (*Renderer, *ldtkgo.Level) {
+
+	options := gen.DefaultOptions()
+	options.Levels = 1
+
+	data, err := gen.Project(options)
+
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	project, err := ldtkgo.Read(data)
+
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	renderer, err := New(nil, nil)
+
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	tileset := project.Tilesets[0]
+	renderer.Tilesets[tileset.Path] = ebiten.NewImage(tileset.Width, tileset.Height)
+
+	return renderer, project.Levels[0]
+
+}
+
+func benchmarkRender(b *testing.B, drawOptions *DrawOptions) {
+
+	renderer, level := benchLevel(b)
+	screen := ebiten.NewImage(level.Width, level.Height)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if err := renderer.Render(level, screen, drawOptions); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+}
+
+func BenchmarkRender(b *testing.B) {
+	benchmarkRender(b, NewDefaultDrawOptions())
+}
+
+func BenchmarkRenderBatched(b *testing.B) {
+	drawOptions := NewDefaultDrawOptions()
+	drawOptions.BatchTiles = true
+	benchmarkRender(b, drawOptions)
+}
+
+func BenchmarkDrawLayer(b *testing.B) {
+
+	renderer, level := benchLevel(b)
+	screen := ebiten.NewImage(level.Width, level.Height)
+	layer := level.LayerByIdentifier("Tiles")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if err := renderer.DrawLayer(layer, screen, nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+}
@@ -0,0 +1,54 @@
+package ebitengine
+
+import (
+	"errors"
+
+	"github.com/solarlune/ldtkgo"
+)
+
+// Errors returned by the Renderer that can be checked for using errors.Is; their messages are the Error strings. Errors for tileset images
+// that couldn't be loaded match ldtkgo.ErrTilesetNotFound instead.
+var (
+	ErrNoLevelGiven         = errors.New(ErrorNoLevelGiven)
+	ErrLayerIndexOutOfRange = errors.New(ErrorLayerIndexOutOfRange)
+	ErrBackgroundNotFound   = errors.New(ErrorBackgroundNotFound)
+	ErrCompositeNotFound    = errors.New(ErrorCompositeNotFound)
+	ErrEmptyRegion          = errors.New(ErrorEmptyRegion)
+	ErrNoThumbnail          = errors.New(ErrorNoThumbnail)
+)
+
+// ImageError is returned when an image used by a Level (a tileset image, a background image, or the composite image of a Super Simple Export)
+// couldn't be loaded. It matches its Kind using errors.Is, and unwraps to the error the image couldn't be loaded with (i.e. fs.ErrNotExist).
+type ImageError struct {
+	Kind error  // ldtkgo.ErrTilesetNotFound, ErrBackgroundNotFound, or ErrCompositeNotFound
+	Path string // The path of the image (or of the Level, for composite images)
+	Err  error  // The error the image couldn't be loaded with
+}
+
+func (err *ImageError) Error() string {
+	message := err.Kind.Error()
+	if err.Kind == ldtkgo.ErrTilesetNotFound {
+		message = ErrorTilesetNotFound
+	}
+	message += ": [" + err.Path + "]"
+	if err.Err != nil {
+		message += ": " + err.Err.Error()
+	}
+	return message
+}
+
+func (err *ImageError) Unwrap() error {
+	return err.Err
+}
+
+func (err *ImageError) Is(target error) bool {
+	return target == err.Kind
+}
+
+// imageError returns an *ImageError for the tileset or background image at the path given.
+func imageError(background bool, path string, err error) error {
+	if background {
+		return &ImageError{Kind: ErrBackgroundNotFound, Path: path, Err: err}
+	}
+	return &ImageError{Kind: ldtkgo.ErrTilesetNotFound, Path: path, Err: err}
+}
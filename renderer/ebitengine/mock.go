@@ -0,0 +1,75 @@
+package ebitengine
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/solarlune/ldtkgo"
+)
+
+// LevelRenderer is the part of a Renderer that game code draws Levels with. Game code can depend on a LevelRenderer rather than on a
+// *Renderer, so that a MockRenderer can be used in its place for testing it headlessly, or another backend can be swapped in.
+type LevelRenderer interface {
+	Render(level *ldtkgo.Level, screen *ebiten.Image, drawOptions *DrawOptions) error
+	RenderToImages(level *ldtkgo.Level) ([]*ebiten.Image, error)
+	Preload(level *ldtkgo.Level) error
+	Unload(level *ldtkgo.Level)
+}
+
+var _ LevelRenderer = (*Renderer)(nil)
+
+// MockRenderer is a LevelRenderer that doesn't load or draw anything, but records the Levels it's called with, so that game code that renders
+// Levels can be tested without a graphics context. Its zero value is ready to use.
+type MockRenderer struct {
+	Rendered  []*ldtkgo.Level // The Levels passed to Render and RenderToImages, in order
+	Preloaded []*ldtkgo.Level // The Levels passed to Preload, in order
+	Unloaded  []*ldtkgo.Level // The Levels passed to Unload, in order
+	Err       error           // If set, the error returned by Render, RenderToImages, and Preload (i.e. to test how errors are handled)
+}
+
+// NewMockRenderer creates a new MockRenderer.
+func NewMockRenderer() *MockRenderer {
+	return &MockRenderer{}
+}
+
+// Render records the Level given without drawing it.
+func (m *MockRenderer) Render(level *ldtkgo.Level, screen *ebiten.Image, drawOptions *DrawOptions) error {
+	if level == nil {
+		return ErrNoLevelGiven
+	}
+	m.Rendered = append(m.Rendered, level)
+	return m.Err
+}
+
+// RenderToImages records the Level given, returning a nil image for each of its Layers.
+func (m *MockRenderer) RenderToImages(level *ldtkgo.Level) ([]*ebiten.Image, error) {
+	if level == nil {
+		return nil, ErrNoLevelGiven
+	}
+	m.Rendered = append(m.Rendered, level)
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	return make([]*ebiten.Image, len(level.Layers)), nil
+}
+
+// Preload records the Level given without loading any images.
+func (m *MockRenderer) Preload(level *ldtkgo.Level) error {
+	if level == nil {
+		return ErrNoLevelGiven
+	}
+	m.Preloaded = append(m.Preloaded, level)
+	return m.Err
+}
+
+// Unload records the Level given.
+func (m *MockRenderer) Unload(level *ldtkgo.Level) {
+	if level != nil {
+		m.Unloaded = append(m.Unloaded, level)
+	}
+}
+
+// Reset clears the Levels the MockRenderer has recorded.
+func (m *MockRenderer) Reset() {
+	m.Rendered = nil
+	m.Preloaded = nil
+	m.Unloaded = nil
+}
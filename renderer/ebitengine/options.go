@@ -0,0 +1,160 @@
+package ebitengine
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/solarlune/ldtkgo"
+)
+
+// Option is an option that customizes how a Renderer is created by New.
+type Option func(config *config)
+
+type config struct {
+	continueOnMissingAssets bool
+	imageLoader             ImageLoadFunc
+}
+
+func newConfig(options []Option) *config {
+	config := &config{}
+	for _, option := range options {
+		if option != nil {
+			option(config)
+		}
+	}
+	return config
+}
+
+// ContinueOnMissingAssets returns an Option that makes New keep loading the rest of the project's images when one can't be loaded, rather
+// than failing on the first one. Each image that can't be loaded is replaced with a checkerboard placeholder image, so the rest of the
+// project can still be drawn, and New returns the Renderer along with a LoadErrors listing every image that couldn't be loaded.
+func ContinueOnMissingAssets() Option {
+	return func(config *config) {
+		config.continueOnMissingAssets = true
+	}
+}
+
+// ImageLoadFunc is a function that loads the image at the path given from the file system given.
+type ImageLoadFunc func(fileSystem fs.FS, path string) (image.Image, error)
+
+// ImageLoader returns an Option that makes the Renderer load its tileset and background images using the function given, rather than
+// decoding them using the image package. This allows projects to use image formats that need their own decoding (i.e. .webp, .qoi, or
+// files exported by Aseprite). Alternatively, formats with a decoder registered with the image package (i.e. by importing
+// golang.org/x/image/webp) are loaded without needing an ImageLoadFunc.
+func ImageLoader(function ImageLoadFunc) Option {
+	return func(config *config) {
+		config.imageLoader = function
+	}
+}
+
+// AsepriteAsPNG returns an ImageLoadFunc (for use with the ImageLoader Option) that loads images that are Aseprite files (.aseprite or .ase,
+// which LDtk can use as tilesets directly) from PNG files exported next to them with the same name instead, as the Renderer can't decode
+// Aseprite files itself; i.e. "tiles.aseprite" is loaded from "tiles.png". All images are loaded using the function given, or decoded using
+// the image package if it's nil. To decode Aseprite files directly instead, pass an ImageLoadFunc that does so to ImageLoader.
+func AsepriteAsPNG(load ImageLoadFunc) ImageLoadFunc {
+
+	if load == nil {
+		load = decodeImage
+	}
+
+	return func(fileSystem fs.FS, filePath string) (image.Image, error) {
+		switch ext := path.Ext(filePath); strings.ToLower(ext) {
+		case ".aseprite", ".ase":
+			filePath = strings.TrimSuffix(filePath, ext) + ".png"
+		}
+		return load(fileSystem, filePath)
+	}
+
+}
+
+// decodeImage loads the image at the path given from the file system given using the image package.
+func decodeImage(fileSystem fs.FS, filePath string) (image.Image, error) {
+
+	file, err := fileSystem.Open(filePath)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	img, _, err := image.Decode(file)
+
+	return img, err
+
+}
+
+// LoadErrors is a list of errors that occurred while loading a Renderer's images.
+type LoadErrors []error
+
+func (errs LoadErrors) Error() string {
+	messages := make([]string, len(errs))
+	for i, err := range errs {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Is returns if any of the errors match the target error given, so that errors.Is can be used on LoadErrors.
+func (errs LoadErrors) Is(target error) bool {
+	for _, err := range errs {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first of the errors that matches the target given, so that errors.As can be used on LoadErrors.
+func (errs LoadErrors) As(target interface{}) bool {
+	for _, err := range errs {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// placeholderSquareSize is the size of the squares of the checkerboard in placeholder images in pixels.
+const placeholderSquareSize = 8
+
+// placeholder returns a magenta and black checkerboard image of the size given, which is drawn in place of images that couldn't be loaded.
+func placeholder(width, height int) *ebiten.Image {
+
+	if width <= 0 || height <= 0 {
+		width, height = placeholderSquareSize*8, placeholderSquareSize*8
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if (x/placeholderSquareSize+y/placeholderSquareSize)%2 == 0 {
+				img.Set(x, y, color.RGBA{255, 0, 255, 255})
+			} else {
+				img.Set(x, y, color.RGBA{0, 0, 0, 255})
+			}
+		}
+	}
+
+	return ebiten.NewImageFromImage(img)
+
+}
+
+// backgroundPlaceholder returns a placeholder image for the Level background image given, large enough to cover its crop rectangle.
+func backgroundPlaceholder(bgImage *ldtkgo.BGImage) *ebiten.Image {
+
+	if len(bgImage.CropRect) < 4 {
+		return placeholder(0, 0)
+	}
+
+	crop := bgImage.CropRect
+
+	return placeholder(int(crop[0]+crop[2]), int(crop[1]+crop[3]))
+
+}
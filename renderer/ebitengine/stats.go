@@ -0,0 +1,117 @@
+package ebitengine
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/solarlune/ldtkgo"
+)
+
+// RenderStats counts the work done by the Renderer's last render call (Render, RenderWorld, RenderTransition, RenderSimpleLevel,
+// RenderLayerToImage, RenderToImages, or RenderRegion), so that the draw options (i.e. CullTiles and BatchTiles) can be tuned with data.
+// The stats of render calls that other render calls make (i.e. the calls to Render that RenderWorld makes for each Level) are added together.
+type RenderStats struct {
+	TilesDrawn          int           // The number of tiles drawn
+	TilesCulled         int           // The number of tiles that weren't drawn because they were entirely outside of the destination image (see DrawOptions.CullTiles)
+	DrawCalls           int           // The number of DrawImage, DrawTriangles, and DrawRectShader calls made, not including the ones made by an EntityDrawCallback
+	LevelsDrawn         int           // The number of Levels drawn
+	LevelsCulled        int           // The number of Levels RenderWorld didn't draw because they were outside of the WorldView
+	SubImageCacheHits   int           // The number of tile and background sub-images that were reused from the Renderer's cache (see SubImageCacheSize)
+	SubImageCacheMisses int           // The number of tile and background sub-images that had to be taken because they weren't cached
+	Layers              []LayerStats  // The stats of each Layer drawn, in the order they were drawn; the slice is reused by the next render call
+	Duration            time.Duration // How long the render call took on the CPU; the GPU draws asynchronously, so this doesn't include its time
+}
+
+// LayerStats counts the work done to draw a Layer in a render call.
+type LayerStats struct {
+	Level       string        // The identifier of the Level the Layer is in
+	Layer       string        // The identifier of the Layer
+	TilesDrawn  int           // The number of the Layer's tiles drawn
+	TilesCulled int           // The number of the Layer's tiles that weren't drawn because they were outside of the destination image
+	Duration    time.Duration // How long drawing the Layer took on the CPU
+}
+
+// beginStats starts counting the stats of a render call, resetting them unless the call is made by another render call. Each call has to
+// be followed by a call to endStats.
+func (r *Renderer) beginStats() {
+
+	if r.statsDepth == 0 {
+		r.Stats = RenderStats{Layers: r.Stats.Layers[:0]}
+		r.statsStart = time.Now()
+	}
+
+	r.statsDepth++
+
+}
+
+// endStats finishes counting the stats of a render call.
+func (r *Renderer) endStats() {
+
+	r.statsDepth--
+
+	if r.statsDepth == 0 {
+		r.Stats.Duration = time.Since(r.statsStart)
+	}
+
+}
+
+// renderLayerWithStats draws the layer given using the function given, adding its stats to the Renderer's.
+func (r *Renderer) renderLayerWithStats(level *ldtkgo.Level, layer *ldtkgo.Layer, render func()) {
+
+	start := time.Now()
+	drawn, culled := r.Stats.TilesDrawn, r.Stats.TilesCulled
+
+	render()
+
+	r.Stats.Layers = append(r.Stats.Layers, LayerStats{
+		Level:       level.Identifier,
+		Layer:       layer.Identifier,
+		TilesDrawn:  r.Stats.TilesDrawn - drawn,
+		TilesCulled: r.Stats.TilesCulled - culled,
+		Duration:    time.Since(start),
+	})
+
+}
+
+// DrawStats prints the Renderer's Stats to the top-left corner of the screen given, i.e. as an overlay when tuning the draw options. This
+// should be called after the render calls the stats are wanted for.
+func (r *Renderer) DrawStats(screen *ebiten.Image) {
+
+	stats := r.Stats
+
+	text := &strings.Builder{}
+
+	fmt.Fprintf(text, "Render: %s\n", stats.Duration)
+	fmt.Fprintf(text, "Levels: %d drawn, %d culled\n", stats.LevelsDrawn, stats.LevelsCulled)
+	fmt.Fprintf(text, "Tiles: %d drawn, %d culled\n", stats.TilesDrawn, stats.TilesCulled)
+	fmt.Fprintf(text, "Draw calls: %d\n", stats.DrawCalls)
+	fmt.Fprintf(text, "Sub-image cache: %d hits, %d misses\n", stats.SubImageCacheHits, stats.SubImageCacheMisses)
+
+	for _, layer := range stats.Layers {
+		fmt.Fprintf(text, "  %s/%s: %d drawn, %d culled, %s\n", layer.Level, layer.Layer, layer.TilesDrawn, layer.TilesCulled, layer.Duration)
+	}
+
+	ebitenutil.DebugPrint(screen, text.String())
+
+}
+
+// tileVisible returns whether any part of a tile of the size given, drawn using the GeoM given, is within the bounds given.
+func tileVisible(geoM ebiten.GeoM, w, h int, bounds image.Rectangle) bool {
+
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+
+	for _, corner := range [4][2]float64{{0, 0}, {float64(w), 0}, {0, float64(h)}, {float64(w), float64(h)}} {
+		x, y := geoM.Apply(corner[0], corner[1])
+		minX, maxX = math.Min(minX, x), math.Max(maxX, x)
+		minY, maxY = math.Min(minY, y), math.Max(maxY, y)
+	}
+
+	return maxX > float64(bounds.Min.X) && minX < float64(bounds.Max.X) && maxY > float64(bounds.Min.Y) && minY < float64(bounds.Max.Y)
+
+}
@@ -0,0 +1,100 @@
+package ebitengine
+
+import (
+	"image"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/solarlune/ldtkgo"
+)
+
+// defaultSubImageCacheSize is the number of sub-images the Renderer caches if its SubImageCacheSize is 0.
+const defaultSubImageCacheSize = 1 << 14
+
+// subImage returns the area of the image given within the rectangle given, reusing the sub-image taken the last time it was asked for, so that
+// tiles don't need a new sub-image every time they're drawn. When the cache is full, it's cleared.
+func (r *Renderer) subImage(img *ebiten.Image, rect image.Rectangle) *ebiten.Image {
+
+	limit := r.SubImageCacheSize
+
+	if limit == 0 {
+		limit = defaultSubImageCacheSize
+	}
+
+	if limit < 0 {
+		r.Stats.SubImageCacheMisses++
+		return img.SubImage(rect).(*ebiten.Image)
+	}
+
+	if sub, exists := r.subImages[img][rect]; exists {
+		r.Stats.SubImageCacheHits++
+		return sub
+	}
+
+	r.Stats.SubImageCacheMisses++
+
+	if r.subImageCount >= limit {
+		r.ClearSubImageCache()
+	}
+
+	if r.subImages == nil {
+		r.subImages = map[*ebiten.Image]map[image.Rectangle]*ebiten.Image{}
+	}
+
+	rects := r.subImages[img]
+
+	if rects == nil {
+		rects = map[image.Rectangle]*ebiten.Image{}
+		r.subImages[img] = rects
+	}
+
+	sub := img.SubImage(rect).(*ebiten.Image)
+	rects[rect] = sub
+	r.subImageCount++
+
+	return sub
+
+}
+
+// forgetSubImages removes the cached sub-images of the image given (i.e. once it's unloaded), so that the image isn't kept alive by them.
+func (r *Renderer) forgetSubImages(img *ebiten.Image) {
+	r.subImageCount -= len(r.subImages[img])
+	delete(r.subImages, img)
+}
+
+// ClearSubImageCache removes all of the sub-images the Renderer has cached. The cache is cleared for each tileset or background image when
+// it's unloaded, but images that are replaced in Tilesets or Backgrounds directly stay cached until this is called (or the cache fills up).
+func (r *Renderer) ClearSubImageCache() {
+	r.subImages = nil
+	r.subImageCount = 0
+}
+
+// LayerTileImage returns the area of the loaded tileset image that the Tile given from the Layer given is drawn from (before it's flipped or
+// rotated), or nil if the tileset image isn't loaded. The sub-image is cached, so this can be called every frame (i.e. to draw tiles
+// individually) without allocating.
+func (r *Renderer) LayerTileImage(layer *ldtkgo.Layer, tile *ldtkgo.Tile) *ebiten.Image {
+
+	if layer == nil || tile == nil {
+		return nil
+	}
+
+	tileset := layer.Tileset
+
+	if tile.TilesetUID != 0 {
+		tileset = tile.Tileset()
+	}
+
+	if tileset == nil || tileset.Path == "" {
+		return nil
+	}
+
+	img := r.texture(tileset.Path, false)
+
+	if img == nil {
+		return nil
+	}
+
+	srcRect, _ := tile.SrcRect(layer)
+
+	return r.subImage(img, tilesetRect(img, srcRect))
+
+}
@@ -0,0 +1,202 @@
+package ebitengine
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/solarlune/ldtkgo"
+)
+
+// textureKey identifies a tileset or background image loaded by the Renderer.
+type textureKey struct {
+	path       string
+	background bool
+}
+
+// less returns if the key sorts before the other key given: tileset images before background images, and then by path.
+func (key textureKey) less(other textureKey) bool {
+	if key.background != other.background {
+		return !key.background
+	}
+	return key.path < other.path
+}
+
+// Preload loads the tileset and background images used by the Level given that aren't loaded yet, so that drawing the Level for the first time
+// doesn't need to load them (i.e. when streaming a world, Levels can be preloaded before they come into view).
+func (r *Renderer) Preload(level *ldtkgo.Level) error {
+
+	if level == nil {
+		return ErrNoLevelGiven
+	}
+
+	project := r.project
+	if project == nil {
+		project = level.Project
+	}
+
+	for _, key := range levelTextures(level) {
+
+		images := r.textures(key.background)
+
+		if _, exists := images[key.path]; exists {
+			r.useTexture(key)
+			continue
+		}
+
+		img, err := r.loadImage(project, key.path)
+
+		if err != nil {
+			return imageError(key.background, key.path, err)
+		}
+
+		images[key.path] = img
+		r.useTexture(key)
+
+	}
+
+	r.evictTextures()
+
+	return nil
+
+}
+
+// UnloadLevelAssets unloads the tileset and background images used by the Level given, so their memory can be reclaimed once they're no
+// longer referenced. Images that are unloaded are loaded again when they're next needed to draw, so this is safe to call for images that
+// other Levels share.
+func (r *Renderer) UnloadLevelAssets(level *ldtkgo.Level) {
+
+	if level == nil {
+		return
+	}
+
+	for _, key := range levelTextures(level) {
+		r.unloadTexture(key)
+	}
+
+}
+
+// Unload unloads the tileset and background images used by the Level given; it's the same as UnloadLevelAssets.
+func (r *Renderer) Unload(level *ldtkgo.Level) {
+	r.UnloadLevelAssets(level)
+}
+
+// levelTextures returns the tileset and background images the Level given draws with.
+func levelTextures(level *ldtkgo.Level) []textureKey {
+
+	keys := []textureKey{}
+	added := map[textureKey]bool{}
+
+	add := func(key textureKey) {
+		if key.path != "" && !added[key] {
+			added[key] = true
+			keys = append(keys, key)
+		}
+	}
+
+	if level.BGImage != nil {
+		add(textureKey{path: level.BGImage.Path, background: true})
+	}
+
+	for _, layer := range level.Layers {
+
+		if layer.Tileset != nil {
+			add(textureKey{path: layer.Tileset.Path})
+		}
+
+		for _, entity := range layer.Entities {
+			if entity.TileRect != nil && entity.TileRect.Tileset != nil {
+				add(textureKey{path: entity.TileRect.Tileset.Path})
+			}
+		}
+
+	}
+
+	return keys
+
+}
+
+// textures returns the map that the Renderer's background images are kept in if background is true, or its tileset images otherwise.
+func (r *Renderer) textures(background bool) map[string]*ebiten.Image {
+	if background {
+		return r.Backgrounds
+	}
+	return r.Tilesets
+}
+
+// texture returns the tileset (or background) image at the path given, loading it if it isn't loaded (i.e. if it was unloaded or evicted
+// to stay within MaxTextures); nil is returned if it can't be loaded.
+func (r *Renderer) texture(path string, background bool) *ebiten.Image {
+
+	key := textureKey{path: path, background: background}
+	images := r.textures(background)
+
+	img, exists := images[path]
+
+	if !exists {
+
+		if r.project == nil {
+			return nil
+		}
+
+		loaded, err := r.loadImage(r.project, path)
+
+		if err != nil {
+			return nil
+		}
+
+		img = loaded
+		images[path] = img
+
+	}
+
+	r.useTexture(key)
+	r.evictTextures()
+
+	return img
+
+}
+
+// useTexture marks the texture given as the most recently used.
+func (r *Renderer) useTexture(key textureKey) {
+	if r.textureUses == nil {
+		r.textureUses = map[textureKey]uint64{}
+	}
+	r.textureUseCount++
+	r.textureUses[key] = r.textureUseCount
+}
+
+func (r *Renderer) unloadTexture(key textureKey) {
+	if img, exists := r.textures(key.background)[key.path]; exists {
+		r.forgetSubImages(img)
+	}
+	delete(r.textures(key.background), key.path)
+	delete(r.textureUses, key)
+}
+
+// evictTextures unloads the least recently used tileset and background images until no more than MaxTextures are loaded.
+func (r *Renderer) evictTextures() {
+
+	if r.MaxTextures <= 0 {
+		return
+	}
+
+	for len(r.Tilesets)+len(r.Backgrounds) > r.MaxTextures {
+
+		var oldest textureKey
+		oldestUse := ^uint64(0)
+
+		for _, background := range []bool{false, true} {
+			for path := range r.textures(background) {
+				key := textureKey{path: path, background: background}
+				// Images that were never used (i.e. loaded by New) count as the least recently used; ties are broken by path, so that the
+				// same images are always evicted regardless of map iteration order.
+				if use := r.textureUses[key]; use < oldestUse || (use == oldestUse && key.less(oldest)) {
+					oldest = key
+					oldestUse = use
+				}
+			}
+		}
+
+		r.unloadTexture(oldest)
+
+	}
+
+}
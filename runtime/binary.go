@@ -0,0 +1,278 @@
+package runtime
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"sort"
+)
+
+var ErrorInvalidStateData = "data isn't a binary level state"
+
+// stateMagic identifies binary LevelState data written by MarshalBinary; the trailing byte is the version of the format.
+var stateMagic = []byte("LDTKGOSTATE\x01")
+
+// MarshalBinary encodes the LevelState into a compact binary form for save games. Only the changes are stored: the IIDs of destroyed and
+// collected Entities, the spawned Entities, and the changed IntGrid cells and tiles, with each cell's index stored as the (usually small)
+// difference from the previous changed cell's, so that Levels with large areas of changed terrain stay small. The Level itself isn't stored;
+// use Attach after UnmarshalBinary to set it.
+func (state *LevelState) MarshalBinary() ([]byte, error) {
+
+	w := &stateWriter{}
+	w.buffer.Write(stateMagic)
+
+	w.string(state.LevelIID)
+	w.set(state.Destroyed)
+	w.set(state.Collected)
+
+	w.uvarint(uint64(len(state.Spawned)))
+
+	for _, spawned := range state.Spawned {
+
+		properties, err := json.Marshal(spawned.Properties)
+
+		if err != nil {
+			return nil, err
+		}
+
+		w.string(spawned.IID)
+		w.string(spawned.Identifier)
+		w.varint(int64(spawned.X))
+		w.varint(int64(spawned.Y))
+		w.string(string(properties))
+
+	}
+
+	w.cells(state.IntGrid)
+	w.cells(state.Tiles)
+
+	return w.buffer.Bytes(), nil
+
+}
+
+// UnmarshalBinary decodes a LevelState written by MarshalBinary, replacing the LevelState's changes.
+func (state *LevelState) UnmarshalBinary(data []byte) error {
+
+	if !bytes.HasPrefix(data, stateMagic) {
+		return ErrInvalidStateData
+	}
+
+	r := &stateReader{reader: bytes.NewReader(data[len(stateMagic):])}
+
+	decoded := &LevelState{}
+
+	decoded.LevelIID = r.string()
+	decoded.Destroyed = r.set()
+	decoded.Collected = r.set()
+
+	count := r.count()
+	decoded.Spawned = make([]*SpawnedEntity, 0, count)
+
+	for i := 0; i < count && r.err == nil; i++ {
+
+		spawned := &SpawnedEntity{
+			IID:        r.string(),
+			Identifier: r.string(),
+			X:          int(r.varint()),
+			Y:          int(r.varint()),
+		}
+
+		properties := r.string()
+
+		if r.err == nil {
+			if err := json.Unmarshal([]byte(properties), &spawned.Properties); err != nil {
+				return err
+			}
+		}
+
+		if spawned.Properties == nil {
+			spawned.Properties = map[string]interface{}{}
+		}
+
+		decoded.Spawned = append(decoded.Spawned, spawned)
+
+	}
+
+	decoded.IntGrid = r.cells()
+	decoded.Tiles = r.cells()
+
+	if r.err != nil {
+		return r.err
+	}
+
+	state.LevelIID = decoded.LevelIID
+	state.Destroyed = decoded.Destroyed
+	state.Collected = decoded.Collected
+	state.Spawned = decoded.Spawned
+	state.IntGrid = decoded.IntGrid
+	state.Tiles = decoded.Tiles
+
+	return nil
+
+}
+
+type stateWriter struct {
+	buffer  bytes.Buffer
+	scratch [binary.MaxVarintLen64]byte
+}
+
+func (w *stateWriter) uvarint(value uint64) {
+	w.buffer.Write(w.scratch[:binary.PutUvarint(w.scratch[:], value)])
+}
+
+func (w *stateWriter) varint(value int64) {
+	w.buffer.Write(w.scratch[:binary.PutVarint(w.scratch[:], value)])
+}
+
+func (w *stateWriter) string(value string) {
+	w.uvarint(uint64(len(value)))
+	w.buffer.WriteString(value)
+}
+
+// set writes the keys of the set given that are true, sorted so the same state always encodes to the same data.
+func (w *stateWriter) set(values map[string]bool) {
+
+	keys := make([]string, 0, len(values))
+
+	for key, value := range values {
+		if value {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	w.uvarint(uint64(len(keys)))
+
+	for _, key := range keys {
+		w.string(key)
+	}
+
+}
+
+// cells writes the changed cells given, layer by layer, with the cells of each layer in order of their indices.
+func (w *stateWriter) cells(changes map[string]map[int]int) {
+
+	layers := make([]string, 0, len(changes))
+
+	for layer := range changes {
+		layers = append(layers, layer)
+	}
+
+	sort.Strings(layers)
+
+	w.uvarint(uint64(len(layers)))
+
+	for _, layer := range layers {
+
+		indices := make([]int, 0, len(changes[layer]))
+
+		for index := range changes[layer] {
+			indices = append(indices, index)
+		}
+
+		sort.Ints(indices)
+
+		w.string(layer)
+		w.uvarint(uint64(len(indices)))
+
+		previous := 0
+
+		for _, index := range indices {
+			w.uvarint(uint64(index - previous))
+			w.varint(int64(changes[layer][index]))
+			previous = index
+		}
+
+	}
+
+}
+
+// stateReader reads the values written by a stateWriter; once an error occurs, it's kept in err, and zero values are returned.
+type stateReader struct {
+	reader *bytes.Reader
+	err    error
+}
+
+func (r *stateReader) uvarint() uint64 {
+	if r.err != nil {
+		return 0
+	}
+	value, err := binary.ReadUvarint(r.reader)
+	r.fail(err)
+	return value
+}
+
+func (r *stateReader) varint() int64 {
+	if r.err != nil {
+		return 0
+	}
+	value, err := binary.ReadVarint(r.reader)
+	r.fail(err)
+	return value
+}
+
+// count reads a number of elements, failing if there can't be that many left in the data (as each element takes at least a byte).
+func (r *stateReader) count() int {
+	count := r.uvarint()
+	if count > uint64(r.reader.Len()) {
+		r.fail(io.ErrUnexpectedEOF)
+		return 0
+	}
+	return int(count)
+}
+
+func (r *stateReader) string() string {
+	length := r.count()
+	if r.err != nil {
+		return ""
+	}
+	value := make([]byte, length)
+	_, err := io.ReadFull(r.reader, value)
+	r.fail(err)
+	return string(value)
+}
+
+func (r *stateReader) set() map[string]bool {
+	values := map[string]bool{}
+	count := r.count()
+	for i := 0; i < count && r.err == nil; i++ {
+		values[r.string()] = true
+	}
+	return values
+}
+
+func (r *stateReader) cells() map[string]map[int]int {
+
+	changes := map[string]map[int]int{}
+	layers := r.count()
+
+	for i := 0; i < layers && r.err == nil; i++ {
+
+		layer := r.string()
+		count := r.count()
+		cells := make(map[int]int, count)
+		index := 0
+
+		for j := 0; j < count && r.err == nil; j++ {
+			index += int(r.uvarint())
+			cells[index] = int(r.varint())
+		}
+
+		changes[layer] = cells
+
+	}
+
+	return changes
+
+}
+
+func (r *stateReader) fail(err error) {
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	if r.err == nil && err != nil {
+		r.err = err
+	}
+}
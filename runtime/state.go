@@ -0,0 +1,321 @@
+package runtime
+
+// runtime tracks the mutable state of Levels while a game is running (Entities that were spawned, destroyed, or collected, and IntGrid cells
+// and tiles that were changed), separately from the authored data loaded from LDtk, which is left untouched. The state of each Level can be
+// serialized to JSON for save games.
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+
+	"github.com/solarlune/ldtkgo"
+)
+
+var ErrorLayerNotFound = "layer not found in level"
+var ErrorCellOutOfRange = "cell is outside of the layer"
+var ErrorLevelMismatch = "level state belongs to a different level"
+
+// Errors returned by LevelStates that can be checked for using errors.Is; their messages are the Error strings. ErrLayerNotFound also
+// matches ldtkgo.ErrLayerNotFound.
+var (
+	ErrLayerNotFound    = fmt.Errorf("%w in level", ldtkgo.ErrLayerNotFound)
+	ErrCellOutOfRange   = errors.New(ErrorCellOutOfRange)
+	ErrLevelMismatch    = errors.New(ErrorLevelMismatch)
+	ErrInvalidStateData = errors.New(ErrorInvalidStateData)
+)
+
+// NoTile is the tile ID used for cells that have no tile.
+const NoTile = -1
+
+// SpawnedEntity represents an Entity that was spawned while the game was running, rather than placed in LDtk.
+type SpawnedEntity struct {
+	IID        string                 `json:"iid"`        // IID of the Entity, generated when it's spawned
+	Identifier string                 `json:"identifier"` // Identifier of the Entity's EntityDefinition
+	X          int                    `json:"x"`          // Position of the Entity in the Level, in pixels
+	Y          int                    `json:"y"`
+	Properties map[string]interface{} `json:"properties"` // Custom values stored with the Entity, keyed by name; these must be serializable to JSON
+}
+
+// LevelState represents the state of a Level while the game is running. Changes are recorded by IID (for Entities) or by layer identifier
+// and cell (for IntGrid values and tiles), so that only what changed needs to be saved.
+type LevelState struct {
+	Level     *ldtkgo.Level          `json:"-"`         // The Level the state belongs to
+	LevelIID  string                 `json:"levelIid"`  // IID of the Level the state belongs to
+	Destroyed map[string]bool        `json:"destroyed"` // IIDs of the Entities (placed in LDtk) that were destroyed
+	Collected map[string]bool        `json:"collected"` // IIDs of the Entities that were collected (i.e. items or pickups)
+	Spawned   []*SpawnedEntity       `json:"spawned"`   // Entities spawned while the game was running
+	IntGrid   map[string]map[int]int `json:"intGrid"`   // Changed IntGrid values, keyed by layer identifier, and then by cell index (y * the layer's CellWidth + x)
+	Tiles     map[string]map[int]int `json:"tiles"`     // Changed tiles (as tile IDs, or NoTile for removed tiles), keyed by layer identifier, and then by cell index
+	layers    map[string]*ldtkgo.Layer
+}
+
+// NewLevelState creates a new LevelState for the Level given, without any changes.
+func NewLevelState(level *ldtkgo.Level) *LevelState {
+	state := &LevelState{
+		LevelIID:  level.IID,
+		Destroyed: map[string]bool{},
+		Collected: map[string]bool{},
+		Spawned:   []*SpawnedEntity{},
+		IntGrid:   map[string]map[int]int{},
+		Tiles:     map[string]map[int]int{},
+	}
+	state.Level = level
+	return state
+}
+
+// Attach sets the Level the LevelState belongs to, i.e. after the LevelState has been loaded from a save game. An error is returned if the
+// LevelState was created for a different Level.
+func (state *LevelState) Attach(level *ldtkgo.Level) error {
+
+	if state.LevelIID != "" && state.LevelIID != level.IID {
+		return fmt.Errorf("%w: [%s]", ErrLevelMismatch, level.Identifier)
+	}
+
+	state.Level = level
+	state.LevelIID = level.IID
+	state.layers = nil
+
+	if state.Destroyed == nil {
+		state.Destroyed = map[string]bool{}
+	}
+	if state.Collected == nil {
+		state.Collected = map[string]bool{}
+	}
+	if state.Spawned == nil {
+		state.Spawned = []*SpawnedEntity{}
+	}
+	if state.IntGrid == nil {
+		state.IntGrid = map[string]map[int]int{}
+	}
+	if state.Tiles == nil {
+		state.Tiles = map[string]map[int]int{}
+	}
+
+	return nil
+
+}
+
+// Reset clears all of the changes recorded in the LevelState, returning the Level to its authored state.
+func (state *LevelState) Reset() {
+	state.Destroyed = map[string]bool{}
+	state.Collected = map[string]bool{}
+	state.Spawned = []*SpawnedEntity{}
+	state.IntGrid = map[string]map[int]int{}
+	state.Tiles = map[string]map[int]int{}
+}
+
+// Destroy marks the Entity with the IID given as destroyed. If the Entity was spawned while the game was running, it's removed instead.
+func (state *LevelState) Destroy(iid string) {
+	for i, spawned := range state.Spawned {
+		if spawned.IID == iid {
+			state.Spawned = append(state.Spawned[:i], state.Spawned[i+1:]...)
+			return
+		}
+	}
+	state.Destroyed[iid] = true
+}
+
+// IsDestroyed returns if the Entity with the IID given was destroyed.
+func (state *LevelState) IsDestroyed(iid string) bool {
+	return state.Destroyed[iid]
+}
+
+// Collect marks the Entity with the IID given as collected.
+func (state *LevelState) Collect(iid string) {
+	state.Collected[iid] = true
+}
+
+// IsCollected returns if the Entity with the IID given was collected.
+func (state *LevelState) IsCollected(iid string) bool {
+	return state.Collected[iid]
+}
+
+// Spawn records an Entity with the identifier given as spawned at the position given (in pixels, relative to the Level), returning it. The
+// Entity is given a new IID.
+func (state *LevelState) Spawn(identifier string, x, y int) *SpawnedEntity {
+	spawned := &SpawnedEntity{
+		IID:        newIID(),
+		Identifier: identifier,
+		X:          x,
+		Y:          y,
+		Properties: map[string]interface{}{},
+	}
+	state.Spawned = append(state.Spawned, spawned)
+	return spawned
+}
+
+// SpawnedByIID returns the spawned Entity with the IID given, or nil if one isn't found.
+func (state *LevelState) SpawnedByIID(iid string) *SpawnedEntity {
+	for _, spawned := range state.Spawned {
+		if spawned.IID == iid {
+			return spawned
+		}
+	}
+	return nil
+}
+
+// Entities returns the Entities placed in the Level in LDtk that haven't been destroyed or collected.
+func (state *LevelState) Entities() []*ldtkgo.Entity {
+	entities := []*ldtkgo.Entity{}
+	for _, layer := range state.Level.Layers {
+		for _, entity := range layer.Entities {
+			if !state.Destroyed[entity.IID] && !state.Collected[entity.IID] {
+				entities = append(entities, entity)
+			}
+		}
+	}
+	return entities
+}
+
+// layer returns the Layer of the Level with the identifier given.
+func (state *LevelState) layer(identifier string) (*ldtkgo.Layer, error) {
+
+	if state.layers == nil {
+		state.layers = map[string]*ldtkgo.Layer{}
+		for _, layer := range state.Level.Layers {
+			state.layers[layer.Identifier] = layer
+		}
+	}
+
+	layer, exists := state.layers[identifier]
+
+	if !exists {
+		return nil, fmt.Errorf("%w: [%s]", ErrLayerNotFound, identifier)
+	}
+
+	return layer, nil
+
+}
+
+// cellIndex returns the index of the cell given in the Layer with the identifier given.
+func (state *LevelState) cellIndex(layerIdentifier string, x, y int) (*ldtkgo.Layer, int, error) {
+
+	layer, err := state.layer(layerIdentifier)
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if x < 0 || y < 0 || x >= layer.CellWidth || y >= layer.CellHeight {
+		return nil, 0, fmt.Errorf("%w: [%s %d, %d]", ErrCellOutOfRange, layerIdentifier, x, y)
+	}
+
+	return layer, y*layer.CellWidth + x, nil
+
+}
+
+// SetIntGrid changes the IntGrid value of the cell given (in grid coordinates) in the Layer with the identifier given.
+func (state *LevelState) SetIntGrid(layerIdentifier string, x, y, value int) error {
+
+	layer, index, err := state.cellIndex(layerIdentifier, x, y)
+
+	if err != nil {
+		return err
+	}
+
+	authored := 0
+	if integer := layer.IntegerAt(x, y); integer != nil {
+		authored = integer.Value
+	}
+
+	setChange(state.IntGrid, layerIdentifier, index, value, value == authored)
+
+	return nil
+
+}
+
+// IntGridAt returns the current IntGrid value of the cell given (in grid coordinates) in the Layer with the identifier given, or 0 if the cell
+// is empty or doesn't exist.
+func (state *LevelState) IntGridAt(layerIdentifier string, x, y int) int {
+
+	layer, index, err := state.cellIndex(layerIdentifier, x, y)
+
+	if err != nil {
+		return 0
+	}
+
+	if value, changed := state.IntGrid[layerIdentifier][index]; changed {
+		return value
+	}
+
+	if integer := layer.IntegerAt(x, y); integer != nil {
+		return integer.Value
+	}
+
+	return 0
+
+}
+
+// SetTile changes the tile of the cell given (in grid coordinates) in the Layer with the identifier given to the tile ID given; use NoTile to
+// remove the cell's tile.
+func (state *LevelState) SetTile(layerIdentifier string, x, y, tileID int) error {
+
+	layer, index, err := state.cellIndex(layerIdentifier, x, y)
+
+	if err != nil {
+		return err
+	}
+
+	setChange(state.Tiles, layerIdentifier, index, tileID, tileID == authoredTileID(layer, x, y))
+
+	return nil
+
+}
+
+// TileIDAt returns the current tile ID of the cell given (in grid coordinates) in the Layer with the identifier given, or NoTile if the cell
+// has no tile or doesn't exist.
+func (state *LevelState) TileIDAt(layerIdentifier string, x, y int) int {
+
+	layer, index, err := state.cellIndex(layerIdentifier, x, y)
+
+	if err != nil {
+		return NoTile
+	}
+
+	if tileID, changed := state.Tiles[layerIdentifier][index]; changed {
+		return tileID
+	}
+
+	return authoredTileID(layer, x, y)
+
+}
+
+// authoredTileID returns the ID of the tile placed in the cell given in LDtk (manually, or by auto-layer rules), or NoTile if there isn't one.
+func authoredTileID(layer *ldtkgo.Layer, x, y int) int {
+	if tile := layer.TileAt(x, y); tile != nil {
+		return tile.ID
+	}
+	if tile := layer.AutoTileAt(x, y); tile != nil {
+		return tile.ID
+	}
+	return NoTile
+}
+
+// setChange records the value of the cell given in the changes given, or removes the cell's change if the value is the authored one.
+func setChange(changes map[string]map[int]int, layerIdentifier string, index, value int, authored bool) {
+
+	if authored {
+		delete(changes[layerIdentifier], index)
+		if len(changes[layerIdentifier]) == 0 {
+			delete(changes, layerIdentifier)
+		}
+		return
+	}
+
+	if changes[layerIdentifier] == nil {
+		changes[layerIdentifier] = map[int]int{}
+	}
+
+	changes[layerIdentifier][index] = value
+
+}
+
+// newIID returns a new random IID in the same format as LDtk's (a version 4 UUID).
+func newIID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
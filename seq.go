@@ -0,0 +1,70 @@
+//go:build go1.23
+
+package ldtkgo
+
+import "iter"
+
+// TilesSeq returns an iterator over the tiles in the Layer that pass the filter given (which can be one of the TileFilter constants), in drawing
+// order (manually placed tiles first, then auto-layer tiles). Unlike concatenating Tiles and AutoTiles, this doesn't allocate.
+func (layer *Layer) TilesSeq(filter int) iter.Seq[*Tile] {
+	return func(yield func(*Tile) bool) {
+		if filter != TileFilterAuto {
+			for _, tile := range layer.Tiles {
+				if !yield(tile) {
+					return
+				}
+			}
+		}
+		if filter != TileFilterManual {
+			for _, tile := range layer.AutoTiles {
+				if !yield(tile) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// EntitiesSeq returns an iterator over the Entities in the Layer.
+func (layer *Layer) EntitiesSeq() iter.Seq[*Entity] {
+	return func(yield func(*Entity) bool) {
+		for _, entity := range layer.Entities {
+			if !yield(entity) {
+				return
+			}
+		}
+	}
+}
+
+// LayersSeq returns an iterator over the Layers in the Level, in the same order as Level.Layers (from the top-most Layer to the bottom-most).
+func (level *Level) LayersSeq() iter.Seq[*Layer] {
+	return func(yield func(*Layer) bool) {
+		for _, layer := range level.Layers {
+			if !yield(layer) {
+				return
+			}
+		}
+	}
+}
+
+// EntitiesSeq returns an iterator over the Entities in all of the Level's Layers. Unlike Level.Entities, this doesn't allocate.
+func (level *Level) EntitiesSeq() iter.Seq[*Entity] {
+	return func(yield func(*Entity) bool) {
+		for _, layer := range level.Layers {
+			for _, entity := range layer.Entities {
+				if !yield(entity) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// AllEntities returns an iterator over every Entity in the Project, across all Levels and Layers.
+func (project *Project) AllEntities() iter.Seq[*Entity] {
+	return func(yield func(*Entity) bool) {
+		project.EachEntity(func(entity *Entity, layer *Layer, level *Level) bool {
+			return yield(entity)
+		})
+	}
+}
@@ -0,0 +1,308 @@
+package simple
+
+// simple loads Levels exported from LDtk using the "Super Simple Export" option, which writes each Level to its own directory as a set of
+// pre-rendered PNG images (one for each layer, along with a composite of all of them), CSV files for IntGrid layers, and a data.json file
+// containing the Level's Entities and custom fields. This is useful if you'd rather ship pre-rendered levels than draw them tile by tile.
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/solarlune/ldtkgo"
+
+	_ "image/png" // Importing for loading PNGs
+)
+
+// File names LDtk uses for the images of each Level in a Super Simple Export.
+const (
+	CompositeImageName  = "_composite.png" // All of the Level's layers (and its background) drawn together
+	BackgroundImageName = "_bg.png"        // The Level's background color and image
+	dataFileName        = "data.json"
+)
+
+// Level represents a Level exported using LDtk's Super Simple Export.
+type Level struct {
+	Identifier    string                 `json:"identifier"`      // Identifier (name) of the Level
+	IID           string                 `json:"uniqueIdentifer"` // IID of the Level (LDtk spells the key this way)
+	WorldX        int                    `json:"x"`               // Position of the Level in the world
+	WorldY        int                    `json:"y"`
+	Width         int                    `json:"width"` // Width and height of the Level in pixels
+	Height        int                    `json:"height"`
+	BGColorString string                 `json:"bgColor"`         // Background color of the Level as a hex string
+	BGColor       color.Color            `json:"-"`               // Background color of the Level
+	Neighbours    []ldtkgo.Neighbour     `json:"neighbourLevels"` // The Levels that touch or overlap this one in the world
+	CustomFields  map[string]interface{} `json:"customFields"`    // Values of the Level's custom fields, keyed by field identifier
+	LayerImages   []string               `json:"layers"`          // File names of the images of each of the Level's layers, relative to the Level's directory
+	Entities      map[string][]*Entity   `json:"entities"`        // The Level's Entities, keyed by identifier
+	IntGrids      map[string][][]int     `json:"-"`               // Values of the Level's IntGrid layers as rows of cells, keyed by layer identifier
+	Path          string                 `json:"-"`               // Path to the Level's directory in the file system it was loaded from; slash-separated
+	fileSystem    fs.FS
+}
+
+// Entity represents an Entity in a Level exported using LDtk's Super Simple Export.
+type Entity struct {
+	Identifier   string                 `json:"id"`    // Identifier (name) of the Entity
+	IID          string                 `json:"iid"`   // IID of the Entity
+	Layer        string                 `json:"layer"` // Identifier of the layer the Entity is on
+	X            int                    `json:"x"`     // Position of the Entity in the Level, in pixels
+	Y            int                    `json:"y"`
+	Width        int                    `json:"width"`        // Width of the Entity in pixels
+	Height       int                    `json:"height"`       // Height of the Entity in pixels
+	ColorValue   int                    `json:"color"`        // Color of the Entity as a 24-bit RGB value
+	Color        color.Color            `json:"-"`            // Color of the Entity
+	CustomFields map[string]interface{} `json:"customFields"` // Values of the Entity's custom fields, keyed by field identifier
+}
+
+// Open loads the Level exported to the directory given (i.e. "simplified/Level_0") in the file system provided.
+func Open(dir string, fileSystem fs.FS) (*Level, error) {
+
+	data, err := fs.ReadFile(fileSystem, path.Join(dir, dataFileName))
+
+	if err != nil {
+		return nil, err
+	}
+
+	level := &Level{
+		Path:       dir,
+		fileSystem: fileSystem,
+	}
+
+	if err := json.Unmarshal(data, level); err != nil {
+		return nil, fmt.Errorf("%s: %w", path.Join(dir, dataFileName), err)
+	}
+
+	level.BGColor = parseHexColor(level.BGColorString)
+
+	if level.Entities == nil {
+		level.Entities = map[string][]*Entity{}
+	}
+
+	for _, entities := range level.Entities {
+		for _, entity := range entities {
+			if entity == nil {
+				return nil, fmt.Errorf("%s: null entity", path.Join(dir, dataFileName))
+			}
+			entity.Color = color.RGBA{R: uint8(entity.ColorValue >> 16), G: uint8(entity.ColorValue >> 8), B: uint8(entity.ColorValue), A: 255}
+		}
+	}
+
+	// IntGrid layers are exported as CSV files named after the layer.
+	files, err := fs.ReadDir(fileSystem, dir)
+
+	if err != nil {
+		return nil, err
+	}
+
+	level.IntGrids = map[string][][]int{}
+
+	for _, file := range files {
+
+		if file.IsDir() || path.Ext(file.Name()) != ".csv" {
+			continue
+		}
+
+		csv, err := fs.ReadFile(fileSystem, path.Join(dir, file.Name()))
+
+		if err != nil {
+			return nil, err
+		}
+
+		rows, err := parseCSV(csv)
+
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path.Join(dir, file.Name()), err)
+		}
+
+		level.IntGrids[strings.TrimSuffix(file.Name(), ".csv")] = rows
+
+	}
+
+	return level, nil
+
+}
+
+// OpenAll loads every Level exported to the directory given (i.e. the "simplified" directory next to the project file), which contains a
+// directory for each Level. The Levels are returned in the order of their directories' names.
+func OpenAll(dir string, fileSystem fs.FS) ([]*Level, error) {
+
+	entries, err := fs.ReadDir(fileSystem, dir)
+
+	if err != nil {
+		return nil, err
+	}
+
+	levels := []*Level{}
+
+	for _, entry := range entries {
+
+		if !entry.IsDir() {
+			continue
+		}
+
+		levelDir := path.Join(dir, entry.Name())
+
+		if _, err := fs.Stat(fileSystem, path.Join(levelDir, dataFileName)); err != nil {
+			continue
+		}
+
+		level, err := Open(levelDir, fileSystem)
+
+		if err != nil {
+			return nil, err
+		}
+
+		levels = append(levels, level)
+
+	}
+
+	return levels, nil
+
+}
+
+// Image loads the image with the file name given from the Level's directory (i.e. one of the LayerImages).
+func (level *Level) Image(name string) (image.Image, error) {
+
+	data, err := fs.ReadFile(level.fileSystem, path.Join(level.Path, name))
+
+	if err != nil {
+		return nil, err
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+
+	return img, err
+
+}
+
+// CompositeImage loads the image of all of the Level's layers drawn together, including its background.
+func (level *Level) CompositeImage() (image.Image, error) {
+	return level.Image(CompositeImageName)
+}
+
+// BackgroundImage loads the image of the Level's background color and image.
+func (level *Level) BackgroundImage() (image.Image, error) {
+	return level.Image(BackgroundImageName)
+}
+
+// LayerImage loads the image of the layer with the identifier given.
+func (level *Level) LayerImage(layerIdentifier string) (image.Image, error) {
+	return level.Image(layerIdentifier + ".png")
+}
+
+// IntGridAt returns the value of the cell at the grid position given in the IntGrid layer with the identifier given. If the layer doesn't
+// exist or the position is outside of the layer, 0 is returned.
+func (level *Level) IntGridAt(layerIdentifier string, x, y int) int {
+
+	rows := level.IntGrids[layerIdentifier]
+
+	if y < 0 || y >= len(rows) || x < 0 || x >= len(rows[y]) {
+		return 0
+	}
+
+	return rows[y][x]
+
+}
+
+// EntitiesByIdentifier returns the Entities in the Level with the identifier (name) given.
+func (level *Level) EntitiesByIdentifier(identifier string) []*Entity {
+	return level.Entities[identifier]
+}
+
+// EntityByIID returns the Entity in the Level with the IID given, or nil if one isn't found.
+func (level *Level) EntityByIID(iid string) *Entity {
+	for _, entities := range level.Entities {
+		for _, entity := range entities {
+			if entity.IID == iid {
+				return entity
+			}
+		}
+	}
+	return nil
+}
+
+// EntityIdentifiers returns the identifiers of the Entities in the Level (the keys of Entities), sorted alphabetically, so that the Entities
+// can be gone through in the same order every time.
+func (level *Level) EntityIdentifiers() []string {
+	identifiers := make([]string, 0, len(level.Entities))
+	for identifier := range level.Entities {
+		identifiers = append(identifiers, identifier)
+	}
+	sort.Strings(identifiers)
+	return identifiers
+}
+
+// IntGridIdentifiers returns the identifiers of the Level's IntGrid layers (the keys of IntGrids), sorted alphabetically.
+func (level *Level) IntGridIdentifiers() []string {
+	identifiers := make([]string, 0, len(level.IntGrids))
+	for identifier := range level.IntGrids {
+		identifiers = append(identifiers, identifier)
+	}
+	sort.Strings(identifiers)
+	return identifiers
+}
+
+// parseCSV parses an IntGrid CSV file exported by LDtk, in which each row of cells is on its own line and each value is followed by a comma.
+func parseCSV(data []byte) ([][]int, error) {
+
+	rows := [][]int{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(data)+1)
+
+	for scanner.Scan() {
+
+		line := strings.TrimSpace(scanner.Text())
+
+		if line == "" {
+			continue
+		}
+
+		row := []int{}
+
+		for _, field := range strings.Split(line, ",") {
+
+			field = strings.TrimSpace(field)
+
+			if field == "" {
+				continue
+			}
+
+			value, err := strconv.Atoi(field)
+
+			if err != nil {
+				return nil, fmt.Errorf("invalid IntGrid CSV value on row %d: %w", len(rows), err)
+			}
+
+			row = append(row, value)
+
+		}
+
+		rows = append(rows, row)
+
+	}
+
+	return rows, scanner.Err()
+
+}
+
+// parseHexColor parses a color in the "#RRGGBB" format LDtk uses; invalid colors are transparent.
+func parseHexColor(hex string) color.Color {
+
+	value, err := strconv.ParseUint(strings.TrimPrefix(hex, "#"), 16, 32)
+
+	if err != nil || len(strings.TrimPrefix(hex, "#")) != 6 {
+		return color.RGBA{}
+	}
+
+	return color.RGBA{R: uint8(value >> 16), G: uint8(value >> 8), B: uint8(value), A: 255}
+
+}
@@ -0,0 +1,93 @@
+package ldtkgo
+
+import (
+	"bytes"
+	"io/fs"
+	"path"
+	"strings"
+	"time"
+)
+
+// AssetSource provides the data of a game's assets (project files, external level files, and tileset and background images) by path, for
+// games that pack their assets into custom containers (i.e. encrypted or obfuscated zips, or pak files) rather than shipping them as files.
+// Paths are slash-separated and relative to the root of the source, like the paths of an fs.FS (i.e. "levels/world.ldtk" or "gfx/tiles.png").
+// If an asset doesn't exist, ReadAsset should return an error that wraps fs.ErrNotExist.
+type AssetSource interface {
+	ReadAsset(path string) ([]byte, error)
+}
+
+// AssetSourceFunc is a function that implements AssetSource.
+type AssetSourceFunc func(path string) ([]byte, error)
+
+// ReadAsset calls the function with the path given.
+func (function AssetSourceFunc) ReadAsset(path string) ([]byte, error) {
+	return function(path)
+}
+
+// OpenSource loads the LDtk project at the path specified from the AssetSource given, like Open. External level files are read from the
+// AssetSource too, and the Project's FileSystem reads from it, so the renderers can load the tileset and background images from it as well.
+func OpenSource(path string, source AssetSource, options ...LoadOption) (*Project, error) {
+	return Open(path, AssetFS(source), options...)
+}
+
+// AssetFS returns a read-only fs.FS that reads files from the AssetSource given, for use anywhere a file system is accepted (like a renderer).
+// Names are cleaned before they're passed to the AssetSource (so "gfx/../tiles.png" is read as "tiles.png"). As an AssetSource can't list
+// its assets, the file system doesn't support directories, and so can't be used with OpenAll or fs.Glob.
+func AssetFS(source AssetSource) fs.FS {
+	return assetFS{source: source}
+}
+
+type assetFS struct {
+	source AssetSource
+}
+
+func (a assetFS) Open(name string) (fs.File, error) {
+
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+
+	data, err := a.ReadFile(name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &assetFile{Reader: bytes.NewReader(data), name: path.Base(name), size: int64(len(data))}, nil
+
+}
+
+func (a assetFS) ReadFile(name string) ([]byte, error) {
+
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+
+	data, err := a.source.ReadAsset(strings.TrimPrefix(path.Clean(name), "./"))
+
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+
+	return data, nil
+
+}
+
+// assetFile is a file read from an AssetSource; its data is held in memory.
+type assetFile struct {
+	*bytes.Reader
+	name string
+	size int64
+}
+
+func (file *assetFile) Stat() (fs.FileInfo, error) { return file, nil }
+func (file *assetFile) Close() error               { return nil }
+
+// assetFile implements fs.FileInfo as well.
+
+func (file *assetFile) Name() string       { return file.name }
+func (file *assetFile) Size() int64        { return file.size }
+func (file *assetFile) Mode() fs.FileMode  { return 0444 }
+func (file *assetFile) ModTime() time.Time { return time.Time{} }
+func (file *assetFile) IsDir() bool        { return false }
+func (file *assetFile) Sys() interface{}   { return nil }
@@ -0,0 +1,154 @@
+package spawn
+
+// spawn maps LDtk Entities to the entities of an ECS (i.e. donburi or arche) or any other game object system. Factory functions are bound to
+// Entity identifiers or tags in a Registry, which can then spawn every Entity in a Level with a single call. As the factories are plain
+// functions, they close over whatever world or scene they create entities in, so this package doesn't depend on any particular ECS:
+//
+//	registry := spawn.NewRegistry()
+//	registry.Register("Player", func(entity *ldtkgo.Entity, fields spawn.Fields) error {
+//		player := world.Create(Position, Health)
+//		...
+//		return nil
+//	})
+//	registry.SpawnLevel(level)
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/solarlune/ldtkgo"
+)
+
+var ErrorNoFactory = "no factory is registered for entity"
+
+// ErrNoFactory is returned by Strict Registries for Entities without a Factory, and can be checked for using errors.Is.
+var ErrNoFactory = errors.New(ErrorNoFactory)
+
+// Fields holds the Properties of an Entity, keyed by their identifiers. Properties that weren't set on the Entity hold the default values of
+// their fields if the Project uses Property defaults (see ldtkgo.UsePropertyDefaults).
+type Fields map[string]*ldtkgo.Property
+
+// Factory is a function that creates a game object for the Entity given, using its Fields.
+type Factory func(entity *ldtkgo.Entity, fields Fields) error
+
+type tagFactory struct {
+	tag     string
+	factory Factory
+}
+
+// Registry binds Factories to Entity identifiers and tags.
+type Registry struct {
+	Fallback     Factory // The Factory used for Entities that don't have one bound to their identifier or tags; if nil, they're skipped
+	Strict       bool    // If true, spawning an Entity that doesn't have a Factory (and with no Fallback) returns an error instead of skipping it
+	byIdentifier map[string]Factory
+	byTag        []tagFactory
+}
+
+// NewRegistry creates a new, empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		byIdentifier: map[string]Factory{},
+		byTag:        []tagFactory{},
+	}
+}
+
+// Register binds the Factory given to Entities with the identifier given, replacing any Factory bound to it already.
+func (registry *Registry) Register(identifier string, factory Factory) {
+	registry.byIdentifier[identifier] = factory
+}
+
+// RegisterTag binds the Factory given to Entities with the tag given. Factories bound to an Entity's identifier take precedence over ones
+// bound to its tags; if an Entity has several tags with Factories, the one registered first is used.
+func (registry *Registry) RegisterTag(tag string, factory Factory) {
+	registry.byTag = append(registry.byTag, tagFactory{tag: tag, factory: factory})
+}
+
+// Factory returns the Factory used to spawn the Entity given, or nil if it doesn't have one.
+func (registry *Registry) Factory(entity *ldtkgo.Entity) Factory {
+
+	if factory, exists := registry.byIdentifier[entity.Identifier]; exists {
+		return factory
+	}
+
+	for _, bound := range registry.byTag {
+		if entity.HasTag(bound.tag) {
+			return bound.factory
+		}
+	}
+
+	return registry.Fallback
+
+}
+
+// Spawn calls the Factory of the Entity given with its Fields, returning whether the Entity was spawned. Entities that don't have a Factory
+// are skipped, unless the Registry is Strict.
+func (registry *Registry) Spawn(entity *ldtkgo.Entity) (bool, error) {
+
+	factory := registry.Factory(entity)
+
+	if factory == nil {
+		if registry.Strict {
+			return false, fmt.Errorf("%w: [%s]", ErrNoFactory, entity.Identifier)
+		}
+		return false, nil
+	}
+
+	if err := factory(entity, EntityFields(entity)); err != nil {
+		return false, fmt.Errorf("spawning %s (%s): %w", entity.Identifier, entity.IID, err)
+	}
+
+	return true, nil
+
+}
+
+// SpawnLevel spawns each of the Entities in the Level given, layer by layer in the order of the Level's Layers, returning how many were
+// spawned. Spawning stops at the first error.
+func (registry *Registry) SpawnLevel(level *ldtkgo.Level) (int, error) {
+
+	spawned := 0
+
+	for _, layer := range level.Layers {
+
+		for _, entity := range layer.Entities {
+
+			ok, err := registry.Spawn(entity)
+
+			if err != nil {
+				return spawned, err
+			}
+
+			if ok {
+				spawned++
+			}
+
+		}
+
+	}
+
+	return spawned, nil
+
+}
+
+// EntityFields returns the Fields of the Entity given: each of its Properties, along with a Property for each field of its definition
+// that it doesn't have (holding the field's default value if the Project uses Property defaults).
+func EntityFields(entity *ldtkgo.Entity) Fields {
+
+	fields := Fields{}
+
+	for _, prop := range entity.Properties {
+		fields[prop.Identifier] = entity.PropertyByIdentifier(prop.Identifier)
+	}
+
+	if def := entity.Definition(); def != nil {
+		for _, fieldDef := range def.FieldDefinitions {
+			if _, exists := fields[fieldDef.Identifier]; !exists {
+				if prop := entity.PropertyByIdentifier(fieldDef.Identifier); prop != nil {
+					fields[fieldDef.Identifier] = prop
+				}
+			}
+		}
+	}
+
+	return fields
+
+}
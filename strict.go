@@ -0,0 +1,167 @@
+package ldtkgo
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// schemaTypes maps the types with UnmarshalJSON methods to the types they're actually decoded into (see decode.go), as those list all of the
+// JSON keys that are read.
+var schemaTypes = map[reflect.Type]reflect.Type{
+	reflect.TypeOf(Project{}):         reflect.TypeOf(projectJSON{}),
+	reflect.TypeOf(Tileset{}):         reflect.TypeOf(tilesetJSON{}),
+	reflect.TypeOf(Level{}):           reflect.TypeOf(levelJSON{}),
+	reflect.TypeOf(World{}):           reflect.TypeOf(worldJSON{}),
+	reflect.TypeOf(Layer{}):           reflect.TypeOf(layerJSON{}),
+	reflect.TypeOf(TOCEntry{}):        reflect.TypeOf(tocEntryJSON{}),
+	reflect.TypeOf(AutoRule{}):        reflect.TypeOf(autoRuleJSON{}),
+	reflect.TypeOf(FieldDefinition{}): reflect.TypeOf(fieldDefinitionJSON{}),
+	reflect.TypeOf(Property{}):        reflect.TypeOf(propertyJSON{}),
+}
+
+// schemaNames holds the names that unknown fields are reported under for unexported types.
+var schemaNames = map[reflect.Type]string{
+	reflect.TypeOf(projectDefinitions{}): "Definitions",
+	reflect.TypeOf(tileData{}):           "Tile",
+	reflect.TypeOf(enumDefinition{}):     "EnumDefinition",
+}
+
+// unknownFields records the JSON keys that aren't read by LDtk-Go, keyed by the name of the structure they were found in.
+type unknownFields map[string]map[string]bool
+
+// check records the keys in the JSON data given that aren't read when decoding it into a value of the type given.
+func (unknown unknownFields) check(data []byte, t reflect.Type) error {
+
+	var value interface{}
+
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	unknown.checkValue(value, t, "")
+
+	return nil
+
+}
+
+func (unknown unknownFields) checkValue(value interface{}, t reflect.Type, name string) {
+
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+
+	case reflect.Slice, reflect.Array:
+
+		if values, ok := value.([]interface{}); ok {
+			for _, v := range values {
+				unknown.checkValue(v, t.Elem(), name)
+			}
+		}
+
+	case reflect.Struct:
+
+		object, ok := value.(map[string]interface{})
+
+		if !ok {
+			return
+		}
+
+		if n, exists := schemaNames[t]; exists {
+			name = n
+		} else if t.Name() != "" {
+			name = t.Name()
+		}
+
+		if schema, exists := schemaTypes[t]; exists {
+			t = schema
+		}
+
+		fields := map[string]reflect.Type{}
+		schemaFields(t, fields)
+
+		for key, v := range object {
+
+			// Keys are matched case-insensitively, like encoding/json does.
+			fieldType, exists := fields[strings.ToLower(key)]
+
+			if !exists {
+				if unknown[name] == nil {
+					unknown[name] = map[string]bool{}
+				}
+				unknown[name][key] = true
+				continue
+			}
+
+			unknown.checkValue(v, fieldType, name+"."+key)
+
+		}
+
+	}
+
+}
+
+// schemaFields adds the JSON keys (in lowercase) of the fields of the struct type given to the map provided, along with their types.
+func schemaFields(t reflect.Type, fields map[string]reflect.Type) {
+
+	for i := 0; i < t.NumField(); i++ {
+
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+
+		if tag == "-" {
+			continue
+		}
+
+		key := strings.Split(tag, ",")[0]
+
+		if field.Anonymous && key == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				schemaFields(embedded, fields)
+				continue
+			}
+		}
+
+		if field.PkgPath != "" {
+			continue
+		}
+
+		if key == "" {
+			key = field.Name
+		}
+
+		fields[strings.ToLower(key)] = field.Type
+
+	}
+
+}
+
+// UnknownFields returns the keys in the project's JSON data that LDtk-Go doesn't read (and so aren't available through the Project), keyed
+// by the name of the structure they were found in (i.e. "Level", "Layer", or "Entity"); the keys for each are sorted. This can be used to
+// discover data added in newer versions of LDtk that LDtk-Go doesn't support yet. Unknown fields are only recorded if the Project was loaded
+// using the StrictSchema LoadOption; otherwise, nil is returned.
+func (project *Project) UnknownFields() map[string][]string {
+
+	if project.unknownFields == nil {
+		return nil
+	}
+
+	report := map[string][]string{}
+
+	for name, keys := range project.unknownFields {
+		for key := range keys {
+			report[name] = append(report[name], key)
+		}
+		sort.Strings(report[name])
+	}
+
+	return report
+
+}
@@ -0,0 +1,58 @@
+package ldtkgo
+
+import (
+	"image"
+	"os"
+	"testing"
+)
+
+// TestTileSrcRectSpacing checks the source rectangles of tiles drawn from a Tileset with padding and spacing, on a Layer with the same grid
+// size as the Tileset and on one with a larger grid size; the rectangles are always the size of the Tileset's grid.
+func TestTileSrcRectSpacing(t *testing.T) {
+
+	project, err := Open("spacing.ldtk", os.DirFS("testdata/spacing"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The tileset has 3x2 tiles of 8x8 pixels, with 3 pixels of padding around them and 2 pixels of spacing between them.
+	tileRect := func(id int) image.Rectangle {
+		x, y := 3+(id%3)*10, 3+(id/3)*10
+		return image.Rect(x, y, x+8, y+8)
+	}
+
+	level := project.Levels[0]
+
+	for _, layerID := range []string{"Small", "Large"} {
+
+		layer := level.LayerByIdentifier(layerID)
+
+		if len(layer.Tiles) == 0 {
+			t.Fatalf("layer %s has no tiles", layerID)
+		}
+
+		for _, tile := range layer.Tiles {
+			if rect, _ := tile.SrcRect(layer); rect != tileRect(tile.ID) {
+				t.Errorf("layer %s: tile %d at %v has source rectangle %v, not %v", layerID, tile.ID, tile.Position, rect, tileRect(tile.ID))
+			}
+		}
+
+	}
+
+	// Flipped tiles are flipped around the center of the Tileset's grid, not the Layer's.
+	tile := level.LayerByIdentifier("Large").Tiles[0]
+	tile.Flip = TileFlipX
+
+	if _, transform := tile.SrcRect(nil); transform.Matrix[0][2] != 8 {
+		t.Errorf("flipped tile is moved by %v, not 8", transform.Matrix[0][2])
+	}
+
+	// Tiles created in code without a Src are found from their ID.
+	tile = &Tile{ID: 4}
+
+	if rect, _ := tile.SrcRect(level.LayerByIdentifier("Large")); rect != tileRect(4) {
+		t.Errorf("tile without a Src has source rectangle %v, not %v", rect, tileRect(4))
+	}
+
+}
@@ -0,0 +1,56 @@
+package ldtkgo
+
+// EntityReference represents the set of IIDs needed to locate a specific Entity within a Project.
+type EntityReference struct {
+	EntityIID string `json:"entityIid"` // IID of the referenced Entity
+	LayerIID  string `json:"layerIid"`  // IID of the Layer containing the Entity
+	LevelIID  string `json:"levelIid"`  // IID of the Level containing the Entity
+	WorldIID  string `json:"worldIid"`  // IID of the World containing the Entity
+}
+
+// TOCInstance represents a single Entity instance listed in the Project's table of contents.
+type TOCInstance struct {
+	WorldX int                    `json:"worldX"` // Position of the Entity's pivot in world space
+	WorldY int                    `json:"worldY"`
+	Width  int                    `json:"widPx"`  // Width of the Entity in pixels
+	Height int                    `json:"heiPx"`  // Height of the Entity in pixels
+	Fields map[string]interface{} `json:"fields"` // Values of the Entity's fields that were flagged to be exported to the table of contents, keyed by field identifier
+	IIDs   EntityReference        `json:"iids"`   // IIDs used to find the Entity in the Project
+}
+
+// TOCEntry represents an entry in the Project's table of contents, which lists every instance of an Entity whose definition is flagged
+// with "Add to table of content" in LDtk (available in LDtk 1.4+). This allows you to find these Entities (i.e. save points, doors, or
+// minimap markers) without searching through each Level.
+type TOCEntry struct {
+	Identifier string         `json:"identifier"`    // Identifier (name) of the Entity definition
+	Instances  []*TOCInstance `json:"instancesData"` // The instances of the Entity across the Project
+}
+
+// CustomCommand represents a custom command that LDtk runs at a specific point (i.e. after saving).
+type CustomCommand struct {
+	Command string `json:"command"` // The command line to run
+	When    string `json:"when"`    // When the command is run; can be "Manual", "AfterLoad", "BeforeSave", or "AfterSave"
+}
+
+// TOCEntryByIdentifier returns the table of contents entry for the Entity identifier (name) given, or nil if one isn't found.
+func (project *Project) TOCEntryByIdentifier(identifier string) *TOCEntry {
+	for _, entry := range project.TableOfContents {
+		if entry.Identifier == identifier {
+			return entry
+		}
+	}
+	return nil
+}
+
+// TOCEntity returns the Entity a table of contents instance refers to, or nil if it can't be found.
+func (project *Project) TOCEntity(instance *TOCInstance) *Entity {
+	level := project.LevelByIID(instance.IIDs.LevelIID)
+	if level == nil {
+		return nil
+	}
+	layer := level.LayerByIID(instance.IIDs.LayerIID)
+	if layer == nil {
+		return nil
+	}
+	return layer.EntityByIID(instance.IIDs.EntityIID)
+}
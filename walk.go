@@ -0,0 +1,126 @@
+package ldtkgo
+
+// WalkAction indicates how Project.Walk should continue after visiting part of a Project.
+type WalkAction int
+
+// WalkAction constants returned by a Visitor's methods.
+const (
+	WalkContinue WalkAction = iota // Continue walking, visiting the contents of the Level or Layer just visited
+	WalkSkip                       // Skip the contents of the Level or Layer just visited; for Tiles and Entities, skip the rest of the Layer's Tiles or Entities
+	WalkStop                       // Stop walking entirely
+)
+
+// Visitor visits the parts of a Project as they're walked using Project.Walk or Level.Walk. Each method returns a WalkAction indicating
+// how the walk should continue. VisitorFuncs can be used to implement a Visitor using only the functions that are needed.
+type Visitor interface {
+	VisitLevel(level *Level) WalkAction
+	VisitLayer(layer *Layer) WalkAction
+	VisitTile(tile *Tile) WalkAction
+	VisitEntity(entity *Entity) WalkAction
+}
+
+// VisitorFuncs implements Visitor using a function for each part of the Project; any functions left nil are skipped, continuing the walk.
+type VisitorFuncs struct {
+	Level  func(level *Level) WalkAction
+	Layer  func(layer *Layer) WalkAction
+	Tile   func(tile *Tile) WalkAction
+	Entity func(entity *Entity) WalkAction
+}
+
+func (funcs VisitorFuncs) VisitLevel(level *Level) WalkAction {
+	if funcs.Level == nil {
+		return WalkContinue
+	}
+	return funcs.Level(level)
+}
+
+func (funcs VisitorFuncs) VisitLayer(layer *Layer) WalkAction {
+	if funcs.Layer == nil {
+		return WalkContinue
+	}
+	return funcs.Layer(layer)
+}
+
+func (funcs VisitorFuncs) VisitTile(tile *Tile) WalkAction {
+	if funcs.Tile == nil {
+		return WalkContinue
+	}
+	return funcs.Tile(tile)
+}
+
+func (funcs VisitorFuncs) VisitEntity(entity *Entity) WalkAction {
+	if funcs.Entity == nil {
+		return WalkContinue
+	}
+	return funcs.Entity(entity)
+}
+
+// Walk visits each Level in the Project in order, along with their contents (see Level.Walk). Walk returns false if the Visitor stopped
+// the walk early.
+func (project *Project) Walk(visitor Visitor) bool {
+	for _, level := range project.Levels {
+		if !level.Walk(visitor) {
+			return false
+		}
+	}
+	return true
+}
+
+// Walk visits the Level, followed by each of its Layers in drawing order (from the bottom-most Layer to the top-most, which is the reverse of
+// Level.Layers). For each Layer, its Tiles (manually placed Tiles first, then auto-layer Tiles, in drawing order) and then its Entities are
+// visited. Walk returns false if the Visitor stopped the walk early.
+func (level *Level) Walk(visitor Visitor) bool {
+
+	switch visitor.VisitLevel(level) {
+	case WalkSkip:
+		return true
+	case WalkStop:
+		return false
+	}
+
+	for layerIndex := len(level.Layers) - 1; layerIndex >= 0; layerIndex-- {
+
+		layer := level.Layers[layerIndex]
+
+		switch visitor.VisitLayer(layer) {
+		case WalkSkip:
+			continue
+		case WalkStop:
+			return false
+		}
+
+		for _, tiles := range [][]*Tile{layer.Tiles, layer.AutoTiles} {
+
+			action := WalkContinue
+
+			for _, tile := range tiles {
+				if action = visitor.VisitTile(tile); action != WalkContinue {
+					break
+				}
+			}
+
+			if action == WalkStop {
+				return false
+			} else if action == WalkSkip {
+				break
+			}
+
+		}
+
+		for _, entity := range layer.Entities {
+
+			action := visitor.VisitEntity(entity)
+
+			if action == WalkStop {
+				return false
+			} else if action == WalkSkip {
+				break
+			}
+
+		}
+
+	}
+
+	return true
+
+}
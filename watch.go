@@ -0,0 +1,140 @@
+package ldtkgo
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"path"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// PropertyChangeFunc is a function that's called when a Property changes; old is the Property before the change, and new is the Property
+// after it. Either is nil if the Property didn't exist before or after the change.
+type PropertyChangeFunc func(old, new *Property)
+
+type propertySubscription struct {
+	selector string
+	function PropertyChangeFunc
+}
+
+// OnChange registers a function to be called when the Properties matching the selector given change between this Project and an updated
+// version of it (i.e. when it's reloaded by Watch after being edited in LDtk, or when passed to NotifyChanges). This allows gameplay constants
+// stored in LDtk to be tweaked and applied to a running game live. The selector is the Identifier of a Property of the Project ("Gravity"),
+// or the Identifier of a Level and one of its Properties, separated by a period ("Level_0.Music"); a Level Identifier of "*" matches all Levels.
+func (project *Project) OnChange(selector string, function PropertyChangeFunc) {
+	project.subscriptions = append(project.subscriptions, propertySubscription{selector: selector, function: function})
+}
+
+// NotifyChanges compares the Properties of the Project with those of the updated version of it given, calling the functions registered using
+// OnChange for each Property that changed. The registered functions are then carried over to the updated Project, so further changes can be
+// detected by calling NotifyChanges on it.
+func (project *Project) NotifyChanges(updated *Project) {
+
+	for _, sub := range project.subscriptions {
+
+		levelIdentifier, identifier := "", sub.selector
+
+		if i := strings.LastIndex(sub.selector, "."); i >= 0 {
+			levelIdentifier, identifier = sub.selector[:i], sub.selector[i+1:]
+		}
+
+		if levelIdentifier == "" {
+			notifyChange(project.PropertyByIdentifier(identifier), updated.PropertyByIdentifier(identifier), sub.function)
+			continue
+		}
+
+		for _, level := range updated.Levels {
+			if levelIdentifier == "*" || level.Identifier == levelIdentifier {
+				var old *Property
+				if oldLevel := project.LevelByIdentifier(level.Identifier); oldLevel != nil {
+					old = oldLevel.PropertyByIdentifier(identifier)
+				}
+				notifyChange(old, level.PropertyByIdentifier(identifier), sub.function)
+			}
+		}
+
+	}
+
+	updated.subscriptions = append(updated.subscriptions, project.subscriptions...)
+
+}
+
+// notifyChange calls the function given if the two Properties differ.
+func notifyChange(old, new *Property, function PropertyChangeFunc) {
+
+	if old == nil && new == nil {
+		return
+	}
+
+	if old != nil && new != nil && reflect.DeepEqual(old.Value, new.Value) {
+		return
+	}
+
+	function(old, new)
+
+}
+
+// Watch checks the Project's file for changes every interval until the context given is cancelled, returning the context's error. Each time
+// the file is modified, the Project is loaded again using the LoadOptions given, NotifyChanges is called to notify the functions registered
+// using OnChange, and then onReload is called with the reloaded Project (or with the error if it couldn't be loaded). Later changes are
+// compared against the reloaded Project. The Project must have been loaded using Open or OpenContext. Watch blocks, so it's usually run in
+// its own goroutine; note that the functions are called from that goroutine.
+func (project *Project) Watch(ctx context.Context, interval time.Duration, onReload func(updated *Project, err error), options ...LoadOption) error {
+
+	if project.fileSystem == nil || project.Path == "" {
+		return errors.New("project wasn't loaded from a file")
+	}
+
+	name := path.Join(project.dir, path.Base(project.Path))
+
+	info, err := fs.Stat(project.fileSystem, name)
+
+	if err != nil {
+		return err
+	}
+
+	modTime := info.ModTime()
+	current := project
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		info, err := fs.Stat(current.fileSystem, name)
+
+		if err != nil || info.ModTime().Equal(modTime) {
+			continue
+		}
+
+		modTime = info.ModTime()
+
+		updated, err := OpenContext(ctx, name, current.fileSystem, options...)
+
+		if err != nil {
+			if onReload != nil {
+				onReload(nil, err)
+			}
+			continue
+		}
+
+		updated.Path = current.Path
+
+		current.NotifyChanges(updated)
+		current = updated
+
+		if onReload != nil {
+			onReload(updated, nil)
+		}
+
+	}
+
+}
@@ -0,0 +1,164 @@
+package ldtkgo
+
+import (
+	"fmt"
+	"image"
+)
+
+// World represents one of the worlds of an LDtk Project saved with multiple worlds (see ProjectFlagMultiWorlds), each of which has its own
+// Levels and layout. The Levels of all of the Project's Worlds are also in the Project's Levels, in order. As Levels in different Worlds can
+// be at the same positions, lookups by position should be done on a World (or using Project.LevelAtWorld) in Projects with multiple Worlds.
+type World struct {
+	Identifier      string `json:"identifier"`      // Identifier (name) of the World
+	IID             string `json:"iid"`             // IID of the World
+	WorldLayout     string `json:"worldLayout"`     // How the World's Levels are laid out; can be compared using WorldLayout constants
+	WorldGridWidth  int    `json:"worldGridWidth"`  // Width of the cells of the world grid that Levels are aligned to in GridVania layouts
+	WorldGridHeight int    `json:"worldGridHeight"` // Height of the cells of the world grid that Levels are aligned to in GridVania layouts
+
+	levels []*Level
+	bounds image.Rectangle // The bounds of the World's Levels, found when they're loaded (see UpdateBounds)
+}
+
+// Levels returns the Levels in the World, in order.
+func (world *World) Levels() []*Level {
+	return world.levels
+}
+
+// LevelByIdentifier returns the Level in the World with the identifier given, or nil if one isn't found.
+func (world *World) LevelByIdentifier(identifier string) *Level {
+	for _, level := range world.levels {
+		if level.Identifier == identifier {
+			return level
+		}
+	}
+	return nil
+}
+
+// LevelAt returns the Level in the World that contains the world position given (in pixels), or nil if no Level does. If Levels overlap, the
+// first one in the World's Levels is returned.
+func (world *World) LevelAt(worldX, worldY int) *Level {
+	for _, level := range world.levels {
+		if level.Contains(worldX, worldY) {
+			return level
+		}
+	}
+	return nil
+}
+
+// GridSize returns the width and height of the cells of the World's grid that Levels are aligned to in GridVania layouts, in pixels.
+func (world *World) GridSize() (int, int) {
+	return world.WorldGridWidth, world.WorldGridHeight
+}
+
+// ToWorldGrid converts the world position given (in pixels) to the cell of the World's grid (see GridSize) that contains it. If the World
+// doesn't have a world grid, 0, 0 is returned.
+func (world *World) ToWorldGrid(worldX, worldY int) (int, int) {
+	if world.WorldGridWidth <= 0 || world.WorldGridHeight <= 0 {
+		return 0, 0
+	}
+	return floorDiv(worldX, world.WorldGridWidth), floorDiv(worldY, world.WorldGridHeight)
+}
+
+// FromWorldGrid converts the cell of the World's grid given to the world position of its top-left corner, in pixels.
+func (world *World) FromWorldGrid(gridX, gridY int) (int, int) {
+	return gridX * world.WorldGridWidth, gridY * world.WorldGridHeight
+}
+
+// LevelAtGrid returns the Level in the World that covers the cell of the World's grid given, or nil if no Level does.
+func (world *World) LevelAtGrid(gridX, gridY int) *Level {
+	if world.WorldGridWidth <= 0 || world.WorldGridHeight <= 0 {
+		return nil
+	}
+	return world.LevelAt(world.FromWorldGrid(gridX, gridY))
+}
+
+// Bounds returns the smallest rectangle that contains all of the World's Levels, in pixels; an empty rectangle is returned if the World has
+// no Levels. The rectangle is found when the Project is loaded (and again by Project.AddLevel), so if Levels are moved afterwards, call
+// UpdateBounds.
+func (world *World) Bounds() image.Rectangle {
+	return world.bounds
+}
+
+// UpdateBounds finds the World's Bounds again, i.e. after moving its Levels.
+func (world *World) UpdateBounds() {
+
+	world.bounds = image.Rectangle{}
+
+	for i, level := range world.levels {
+		if i == 0 {
+			world.bounds = level.WorldBounds()
+		} else {
+			world.bounds = world.bounds.Union(level.WorldBounds())
+		}
+	}
+
+}
+
+// World returns the World the Level belongs to, or nil if the Project doesn't have multiple Worlds (or the Level isn't part of a Project).
+func (level *Level) World() *World {
+	return level.world
+}
+
+// WorldByIdentifier returns the World with the identifier given, or nil if one isn't found.
+func (project *Project) WorldByIdentifier(identifier string) *World {
+	for _, world := range project.Worlds {
+		if world.Identifier == identifier {
+			return world
+		}
+	}
+	return nil
+}
+
+// WorldByIID returns the World with the IID given, or nil if one isn't found.
+func (project *Project) WorldByIID(iid string) *World {
+	for _, world := range project.Worlds {
+		if world.IID == iid {
+			return world
+		}
+	}
+	return nil
+}
+
+// LevelAtWorld returns the Level in the World with the identifier given that contains the world position given (in pixels), or nil if the
+// World doesn't exist or no Level in it contains the position.
+func (project *Project) LevelAtWorld(worldIdentifier string, worldX, worldY int) *Level {
+	if world := project.WorldByIdentifier(worldIdentifier); world != nil {
+		return world.LevelAt(worldX, worldY)
+	}
+	return nil
+}
+
+// addWorldLevels adds the Levels of the Project's Worlds that haven't been yet to the Project's Levels (LDtk stores the Levels in the Worlds
+// rather than in the Project when the Project has multiple Worlds), linking them to their Worlds and caching the Worlds' Bounds.
+func (project *Project) addWorldLevels() {
+	for _, world := range project.Worlds {
+		for _, level := range world.levels {
+			if level.world == nil {
+				level.world = world
+				project.Levels = append(project.Levels, level)
+			}
+		}
+		world.UpdateBounds()
+	}
+}
+
+// validateWorlds returns an error if any of the Project's Worlds (or their Levels) are null.
+func (project *Project) validateWorlds() error {
+
+	for i, world := range project.Worlds {
+
+		if world == nil {
+			return nullEntryError("worlds", i)
+		}
+
+		for j, level := range world.levels {
+			if level == nil {
+				return nullEntryError(fmt.Sprintf("worlds[%d].levels", i), j)
+			}
+		}
+
+	}
+
+	return nil
+
+}